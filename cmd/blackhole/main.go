@@ -2,15 +2,23 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+	// зоны расписаний доступны и без системной базы часовых поясов
+	_ "time/tzdata"
 
+	"github.com/denisdubovitskiy/blackhole/internal/acl"
 	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
 	"github.com/denisdubovitskiy/blackhole/internal/configuration"
 	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/denisdubovitskiy/blackhole/internal/dnssec"
+	"github.com/denisdubovitskiy/blackhole/internal/expiry"
 	"github.com/denisdubovitskiy/blackhole/internal/externalsource"
 	"github.com/denisdubovitskiy/blackhole/internal/handler"
 	"github.com/denisdubovitskiy/blackhole/internal/history"
@@ -19,13 +27,28 @@ import (
 	"github.com/denisdubovitskiy/blackhole/internal/listeners/grpcgateway"
 	"github.com/denisdubovitskiy/blackhole/internal/listeners/grpcserver"
 	"github.com/denisdubovitskiy/blackhole/internal/listeners/swagger"
+	"github.com/denisdubovitskiy/blackhole/internal/patterns"
+	"github.com/denisdubovitskiy/blackhole/internal/pause"
+	"github.com/denisdubovitskiy/blackhole/internal/policy"
 	"github.com/denisdubovitskiy/blackhole/internal/provider/sources"
+	"github.com/denisdubovitskiy/blackhole/internal/ratelimit"
+	"github.com/denisdubovitskiy/blackhole/internal/records"
+	"github.com/denisdubovitskiy/blackhole/internal/recursor"
+	"github.com/denisdubovitskiy/blackhole/internal/resolver"
+	"github.com/denisdubovitskiy/blackhole/internal/safesearch"
+	"github.com/denisdubovitskiy/blackhole/internal/schedule"
+	"github.com/denisdubovitskiy/blackhole/internal/services"
+	"github.com/denisdubovitskiy/blackhole/internal/ttl"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 func main() {
-	config := configuration.Parse()
+	config, err := configuration.Parse()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	logConfig := zap.NewProductionEncoderConfig()
 	logConfig.EncodeTime = zapcore.ISO8601TimeEncoder
@@ -64,7 +87,7 @@ func main() {
 	log.Debug("database schema is up to date")
 
 	downloader := externalsource.NewDownloader(http.DefaultClient)
-	sourceProvider := sources.NewProvider(storage, downloader)
+	sourceProvider := sources.NewProvider(storage, bl, downloader)
 	historyLogger := history.NewLogger(storage, log)
 	historyLogger.Run(ctx)
 
@@ -81,7 +104,247 @@ func main() {
 		}()
 	})
 
-	controller := handler.New(bl, sourceProvider)
+	// загружаемые домены сразу попадают и в черный список: прогрев из
+	// базы может пройти раньше, чем закончится загрузка
+	if storage.SourcesOutdated() {
+		log.Info("migration: reloading domains from sources")
+		if err := sourceProvider.RefreshSources(ctx); err != nil {
+			log.Error("migration: unable to refresh sources", zap.Error(err))
+		}
+	}
+
+	upstreams, err := toUpstreams(config.Upstreams)
+	if err != nil {
+		log.Fatal("invalid upstream configuration", zap.Error(err))
+	}
+
+	groups := make([]resolver.Group, 0, len(config.UpstreamGroups))
+	for name, g := range config.UpstreamGroups {
+		groupUpstreams, err := toUpstreams(g.Upstreams)
+		if err != nil {
+			log.Fatal("invalid upstream configuration", zap.String("group", name), zap.Error(err))
+		}
+		groups = append(groups, resolver.Group{
+			Name:      name,
+			Strategy:  g.Strategy,
+			Parallel:  g.Parallel,
+			Upstreams: groupUpstreams,
+		})
+	}
+
+	forwarding := make([]resolver.ForwardingRule, len(config.Forwarding))
+	for i, rule := range config.Forwarding {
+		forwarding[i] = resolver.ForwardingRule{Zone: rule.Zone, Group: rule.Group}
+	}
+
+	dnsResolver, err := resolver.New(resolver.Config{
+		Upstreams:  upstreams,
+		Strategy:   config.Resolver.Strategy,
+		Parallel:   config.Resolver.Parallel,
+		Groups:     groups,
+		Forwarding: forwarding,
+		HealthCheck: resolver.HealthCheck{
+			Interval: config.Resolver.HealthCheck.Interval,
+			Domain:   config.Resolver.HealthCheck.Domain,
+			Failures: config.Resolver.HealthCheck.Failures,
+		},
+		Bootstrap: config.Resolver.Bootstrap,
+		Logger:    log,
+	})
+	if err != nil {
+		log.Fatal("unable to create a resolver", zap.Error(err))
+	}
+
+	var lookuper resolver.Lookuper = dnsResolver
+	if config.Resolver.Mode == "recursive" {
+		log.Debug("using recursive resolution")
+		rec := recursor.New(recursor.Config{
+			Roots:                    config.Resolver.Recursive.Roots,
+			Port:                     config.Resolver.Recursive.Port,
+			Timeout:                  config.Resolver.Recursive.Timeout,
+			DisableQNameMinimisation: !config.Resolver.Recursive.QNameMinimisation,
+			Logger:                   log,
+		})
+		rec.RunPeriodicCleanup(ctx)
+		lookuper = rec
+	} else {
+		dnsResolver.RunHealthChecks(ctx)
+	}
+
+	// chain достраивает цепочку разрешения: проверка DNSSEC и объединение
+	// одинаковых запросов
+	chain := func(lookuper resolver.Lookuper) dnsserver.Resolver {
+		if config.Resolver.DNSSEC.Enabled {
+			validator, err := dnssec.New(lookuper, dnssec.Config{
+				TrustAnchors: config.Resolver.DNSSEC.TrustAnchors,
+				Logger:       log,
+			})
+			if err != nil {
+				log.Fatal("unable to create a DNSSEC validator", zap.Error(err))
+			}
+			lookuper = validator
+		}
+		return resolver.NewCoalescer(lookuper)
+	}
+	if config.Resolver.DNSSEC.Enabled {
+		log.Debug("DNSSEC validation is enabled")
+	}
+
+	upstreamGroups := make(map[string]bool)
+	for _, g := range dnsResolver.Groups() {
+		upstreamGroups[g.Name] = true
+	}
+
+	policies, err := toPolicies(config)
+	if err != nil {
+		log.Fatal("invalid client groups", zap.Error(err))
+	}
+	macForwarders, err := toPrefixes(config.MACForwarders)
+	if err != nil {
+		log.Fatal("invalid mac forwarders", zap.Error(err))
+	}
+
+	groupResolvers := make(map[string]dnsserver.Resolver)
+	for _, name := range policies.Upstreams() {
+		if !upstreamGroups[name] {
+			log.Warn("unknown upstream group of a client group, the default group is used", zap.String("upstreams", name))
+		}
+		groupResolvers[name] = chain(dnsResolver.InGroup(name))
+	}
+
+	if len(config.AllowedClients) > 0 {
+		log.Warn("allowed_clients is deprecated, its entries are added to acl.allow")
+	}
+	allowRules, err := toAccessRules(acl.ActionAllow, config.ACL.Allow)
+	if err != nil {
+		log.Fatal("invalid access rules", zap.Error(err))
+	}
+	denyRules, err := toAccessRules(acl.ActionDeny, config.ACL.Deny)
+	if err != nil {
+		log.Fatal("invalid access rules", zap.Error(err))
+	}
+	accessList, err := acl.New(storage, append(allowRules, denyRules...))
+	if err != nil {
+		log.Fatal("invalid access rules", zap.Error(err))
+	}
+	if err := accessList.Load(ctx); err != nil {
+		log.Fatal("unable to load access rules", zap.Error(err))
+	}
+
+	schedules := schedule.New(storage, schedule.SystemClock{})
+	if err := schedules.Load(ctx); err != nil {
+		log.Fatal("unable to load schedules", zap.Error(err))
+	}
+
+	pauses := pause.New(storage, schedule.SystemClock{}, log)
+	if err := pauses.Load(ctx); err != nil {
+		log.Fatal("unable to load pauses", zap.Error(err))
+	}
+	pauses.RunPeriodicResume(ctx)
+
+	expiries := expiry.New(bl, storage, schedule.SystemClock{}, log)
+
+	localRecords := records.New(storage)
+	if err := localRecords.Load(ctx); err != nil {
+		log.Fatal("unable to load local records", zap.Error(err))
+	}
+	recordFiles := records.NewWatcher(localRecords, toRecordFiles(config.LocalRecords), log)
+	if err := recordFiles.Load(); err != nil {
+		log.Fatal("unable to load local records", zap.Error(err))
+	}
+	recordFiles.Run(ctx)
+
+	safeSearch := safesearch.New(storage)
+	if err := safeSearch.Load(ctx); err != nil {
+		log.Fatal("unable to load safe search settings", zap.Error(err))
+	}
+
+	catalog, err := services.LoadCatalog(config.ServicesCatalog)
+	if err != nil {
+		log.Fatal("unable to load the services catalog", zap.Error(err))
+	}
+	blockedServices := services.New(bl, storage, catalog)
+	if err := blockedServices.Load(ctx); err != nil {
+		log.Fatal("unable to load blocked services", zap.Error(err))
+	}
+	log.Debug("services catalog loaded", zap.Int("version", catalog.Version), zap.Int("services", len(catalog.Services)))
+
+	regexRules := patterns.New(storage)
+	if err := regexRules.Load(ctx); err != nil {
+		log.Fatal("unable to load regex rules", zap.Error(err))
+	}
+
+	limiter := ratelimit.New(ratelimit.Config{
+		Rate:       config.RateLimit.Rate,
+		Burst:      config.RateLimit.Burst,
+		IPv4Prefix: config.RateLimit.IPv4Prefix,
+		IPv6Prefix: config.RateLimit.IPv6Prefix,
+	})
+	limiter.RunPeriodicCleanup(ctx)
+
+	packetConns, listeners, err := dnsserver.ActivationSockets()
+	if err != nil {
+		log.Fatal("unable to use activation sockets", zap.Error(err))
+	}
+	if len(packetConns) > 0 || len(listeners) > 0 {
+		log.Debug("using sockets passed by the service manager")
+		config.DNS.UDP, config.DNS.TCP = nil, nil
+	}
+
+	dnsServer := dnsserver.New(dnsserver.Config{
+		UDP:            config.DNS.UDP,
+		TCP:            config.DNS.TCP,
+		PacketConns:    packetConns,
+		Listeners:      listeners,
+		TLS:            config.DNS.TLS,
+		HTTPS:          config.DNS.HTTPS,
+		CertFile:       config.DNS.CertFile,
+		KeyFile:        config.DNS.KeyFile,
+		DoHPath:        config.DNS.DoHPath,
+		QUIC:           config.DNS.QUIC,
+		Allow0RTT:      config.DNS.Allow0RTT,
+		Access:         accessList,
+		DropDenied:     config.ACL.Action == "drop",
+		ClientIDDomain: config.ACL.ClientIDDomain,
+		Limiter:        limiter,
+		Slip:           config.RateLimit.Slip,
+		BlockTTL:       config.BlockTTL,
+		TTL: ttl.Policy{
+			Positive: ttl.Bounds{Min: config.TTL.PositiveMin, Max: config.TTL.PositiveMax},
+			Negative: ttl.Bounds{Min: config.TTL.NegativeMin, Max: config.TTL.NegativeMax},
+			Blocked:  ttl.Bounds{Min: config.TTL.BlockedMin, Max: config.TTL.BlockedMax},
+		},
+		Resolver:       chain(lookuper),
+		GroupResolvers: groupResolvers,
+		Blacklist:      bl,
+		Policies:       policies,
+		MACForwarders:  macForwarders,
+		Schedules:      schedules,
+		Pauses:         pauses,
+		Services:       blockedServices,
+		Patterns:       regexRules,
+		Local:          localRecords,
+		SafeSearch:     safeSearch,
+		History:        historyLogger,
+		Logger:         log,
+	})
+
+	controller := handler.New(handler.Config{
+		Blacklist:       bl,
+		SourcesProvider: sourceProvider,
+		Upstreams:       dnsResolver,
+		Access:          accessList,
+		Schedules:       schedules,
+		Pauses:          pauses,
+		Expiries:        expiries,
+		Records:         localRecords,
+		SafeSearch:      safeSearch,
+		Services:        blockedServices,
+		Patterns:        regexRules,
+		Checker:         dnsServer,
+		Domains:         storage,
+		Recursive:       config.Resolver.Mode == "recursive",
+	})
 
 	ui := swagger.NewUI(config.SwaggerAddr, config.HttpAddr, log)
 	ui.Run(ctx)
@@ -98,40 +361,111 @@ func main() {
 	go func() {
 		log.Debug("migration: populating blacklist from the database")
 		// Прогрев черного списка на старте из базы данных
-		forEachErr := storage.ForEachDomain(ctx, func(domain string) {
-			bl.Add(ctx, domain)
+		forEachErr := storage.ForEachDomain(ctx, func(domain, origin string) {
+			bl.AddFrom(ctx, origin, domain)
 		})
 		if forEachErr != nil {
 			log.Fatal("migration: unable to populate blacklist from the database", zap.Error(forEachErr))
 		}
 		log.Debug("migration: blacklist is up to date")
+
+		// временные правила применяются поверх загруженного списка
+		if err := expiries.Load(ctx); err != nil {
+			log.Fatal("unable to load expiring rules", zap.Error(err))
+		}
+		expiries.RunPeriodicReaper(ctx)
 	}()
 
-	dnsServer := dnsserver.New(dnsserver.Config{
-		BlockTTL: 10 * time.Second,
-		UpstreamDNSServers: []string{
-			// google
-			"8.8.8.8:53",
-			"8.8.4.4:53",
-			// cloudflare
-			"1.1.1.1:53",
-			"1.0.0.1:53",
-			// control d
-			"76.76.2.0:53",
-			"76.76.10.0:53",
-			// quad9
-			"9.9.9.9:53",
-			"149.112.112.112:53",
-			// open dns home
-			"208.67.222.222:53",
-			"208.67.220.220:53",
-		},
-		Blacklist: bl,
-		History:   historyLogger,
-		Logger:    log,
-	})
 	log.Debug("starting DNS server")
 	if err := dnsServer.Run(ctx); err != nil {
-		log.Error("DNS dnsserver listen error", zap.Error(err))
+		log.Fatal("DNS server error", zap.Error(err))
+	}
+}
+
+func toPrefixes(config []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(config))
+	for _, s := range config {
+		prefix, err := acl.ParseSubnet(s)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+func toPolicies(config configuration.Config) (*policy.Policies, error) {
+	groups := make([]policy.Group, 0, len(config.Groups))
+	for name, g := range config.Groups {
+		groups = append(groups, policy.Group{
+			Name:       name,
+			Sources:    g.Sources,
+			Block:      g.Block,
+			Allow:      g.Allow,
+			BlockMode:  policy.BlockMode(g.BlockMode),
+			Unfiltered: g.Unfiltered,
+			Upstreams:  g.Upstreams,
+		})
+	}
+
+	clients := make([]policy.Client, len(config.Clients))
+	for i, c := range config.Clients {
+		clients[i] = policy.Client{Name: c.Name, Group: c.Group, IDs: c.IDs}
 	}
+
+	return policy.New(policy.Config{Groups: groups, Clients: clients})
+}
+
+func toRecordFiles(config configuration.LocalRecords) []records.File {
+	var files []records.File
+	for _, path := range config.Hosts {
+		files = append(files, records.File{Path: path, Format: records.FormatHosts})
+	}
+	for _, zone := range config.Zones {
+		files = append(files, records.File{Path: zone.File, Format: records.FormatZone, Origin: zone.Origin})
+	}
+	return files
+}
+
+// toAccessRules разбирает правила доступа из конфигурации: адреса,
+// подсети или идентификаторы клиентов с префиксом id:.
+func toAccessRules(action string, config []string) ([]acl.Rule, error) {
+	rules := make([]acl.Rule, 0, len(config))
+
+	for _, s := range config {
+		if id, ok := strings.CutPrefix(s, "id:"); ok {
+			rules = append(rules, acl.Rule{Action: action, ClientID: id})
+			continue
+		}
+		subnet, err := acl.ParseSubnet(s)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, acl.Rule{Action: action, Subnet: subnet})
+	}
+
+	return rules, nil
+}
+
+func toUpstreams(config []configuration.Upstream) ([]resolver.Upstream, error) {
+	upstreams := make([]resolver.Upstream, len(config))
+
+	for i, u := range config {
+		upstream, err := resolver.NewUpstream(u.Address, resolver.Upstream{
+			Protocol:   u.Protocol,
+			Timeout:    u.Timeout,
+			Weight:     u.Weight,
+			ServerName: u.ServerName,
+			Pins:       u.Pins,
+			CAFile:     u.CAFile,
+			Method:     u.Method,
+			UDPSize:    u.UDPSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		upstreams[i] = upstream
+	}
+
+	return upstreams, nil
 }
@@ -0,0 +1,226 @@
+package acl
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sync"
+
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+)
+
+const (
+	ActionAllow = "allow"
+	ActionDeny  = "deny"
+)
+
+// Rule разрешает или запрещает доступ клиентам из подсети или клиенту
+// с идентификатором (DoH, DoT, DoQ). Задается ровно одно из полей
+// Subnet и ClientID.
+type Rule struct {
+	Action   string
+	Subnet   netip.Prefix
+	ClientID string
+	// Static - правило из файла конфигурации, его нельзя удалить через API.
+	Static bool
+}
+
+func (r Rule) validate() error {
+	if r.Action != ActionAllow && r.Action != ActionDeny {
+		return fmt.Errorf("acl: unknown action %q", r.Action)
+	}
+	if r.Subnet.IsValid() == (r.ClientID != "") {
+		return fmt.Errorf("acl: either subnet or client id must be set")
+	}
+	return nil
+}
+
+func (r Rule) matches(ip netip.Addr, clientID string) bool {
+	if r.ClientID != "" {
+		return r.ClientID == clientID
+	}
+	return r.Subnet.Contains(ip)
+}
+
+func (r Rule) same(other Rule) bool {
+	return r.Action == other.Action && r.Subnet == other.Subnet && r.ClientID == other.ClientID
+}
+
+// ParseSubnet разбирает подсеть CIDR, одиночный адрес считается подсетью
+// из одного адреса.
+func ParseSubnet(s string) (netip.Prefix, error) {
+	if addr, err := netip.ParseAddr(s); err == nil {
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("acl: invalid subnet %q", s)
+	}
+	return prefix.Masked(), nil
+}
+
+type Storage interface {
+	AccessRules(ctx context.Context) ([]datastore.AccessRule, error)
+	AddAccessRule(ctx context.Context, rule datastore.AccessRule) error
+	DeleteAccessRule(ctx context.Context, rule datastore.AccessRule) error
+}
+
+// List - правила доступа. Запрещающие правила сильнее разрешающих.
+// Если есть хотя бы одно разрешающее правило, обслуживаются только
+// подходящие под него клиенты.
+type List struct {
+	storage Storage
+
+	// update упорядочивает изменения: запись в storage идет без mu,
+	// чтобы Allowed не ждал диска
+	update sync.Mutex
+	mu     sync.RWMutex
+	rules  []Rule
+}
+
+// New создает список из правил конфигурации. Правила, добавленные через
+// API, хранятся в storage и загружаются методом Load.
+func New(storage Storage, static []Rule) (*List, error) {
+	l := &List{storage: storage}
+
+	for _, rule := range static {
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+		rule.Static = true
+		l.rules = append(l.rules, rule)
+	}
+
+	return l, nil
+}
+
+func (l *List) Load(ctx context.Context) error {
+	stored, err := l.storage.AccessRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	rules := make([]Rule, 0, len(stored))
+	for _, s := range stored {
+		rule := Rule{Action: s.Action, ClientID: s.ClientID}
+		if s.Subnet != "" {
+			if rule.Subnet, err = ParseSubnet(s.Subnet); err != nil {
+				return err
+			}
+		}
+		if err := rule.validate(); err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, rule := range rules {
+		l.add(rule)
+	}
+	return nil
+}
+
+// Allowed сообщает, можно ли обслужить клиента.
+func (l *List) Allowed(ip netip.Addr, clientID string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	restricted, allowed := false, false
+	for _, rule := range l.rules {
+		switch {
+		case rule.Action == ActionDeny && rule.matches(ip, clientID):
+			return false
+		case rule.Action == ActionAllow:
+			restricted = true
+			allowed = allowed || rule.matches(ip, clientID)
+		}
+	}
+	return !restricted || allowed
+}
+
+func (l *List) Rules() []Rule {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return append([]Rule(nil), l.rules...)
+}
+
+func (l *List) Add(ctx context.Context, rule Rule) error {
+	if err := rule.validate(); err != nil {
+		return err
+	}
+	rule.Static = false
+
+	l.update.Lock()
+	defer l.update.Unlock()
+
+	if err := l.storage.AddAccessRule(ctx, toStored(rule)); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.add(rule)
+	return nil
+}
+
+func (l *List) add(rule Rule) {
+	for _, existing := range l.rules {
+		if existing.same(rule) {
+			return
+		}
+	}
+	l.rules = append(l.rules, rule)
+}
+
+func (l *List) Delete(ctx context.Context, rule Rule) error {
+	l.update.Lock()
+	defer l.update.Unlock()
+
+	existing, found := l.find(rule)
+	if !found {
+		return fmt.Errorf("acl: rule does not exist")
+	}
+	if existing.Static {
+		return fmt.Errorf("acl: rule is defined in the configuration")
+	}
+	if err := l.storage.DeleteAccessRule(ctx, toStored(rule)); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, existing := range l.rules {
+		if existing.same(rule) {
+			l.rules = append(l.rules[:i:i], l.rules[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (l *List) find(rule Rule) (Rule, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, existing := range l.rules {
+		if existing.same(rule) {
+			return existing, true
+		}
+	}
+	return Rule{}, false
+}
+
+func toStored(rule Rule) datastore.AccessRule {
+	stored := datastore.AccessRule{Action: rule.Action, ClientID: rule.ClientID}
+	if rule.Subnet.IsValid() {
+		stored.Subnet = rule.Subnet.String()
+	}
+	return stored
+}
@@ -0,0 +1,123 @@
+package acl
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStorage struct {
+	rules []datastore.AccessRule
+}
+
+func (s *fakeStorage) AccessRules(context.Context) ([]datastore.AccessRule, error) {
+	return s.rules, nil
+}
+
+func (s *fakeStorage) AddAccessRule(_ context.Context, rule datastore.AccessRule) error {
+	s.rules = append(s.rules, rule)
+	return nil
+}
+
+func (s *fakeStorage) DeleteAccessRule(_ context.Context, rule datastore.AccessRule) error {
+	for i, existing := range s.rules {
+		if existing == rule {
+			s.rules = append(s.rules[:i], s.rules[i+1:]...)
+		}
+	}
+	return nil
+}
+
+func subnet(s string) netip.Prefix {
+	p, err := ParseSubnet(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	office := netip.MustParseAddr("10.1.2.3")
+	guest := netip.MustParseAddr("10.1.9.9")
+	outside := netip.MustParseAddr("192.0.2.1")
+
+	storage := &fakeStorage{}
+	l, err := New(storage, []Rule{{Action: ActionAllow, Subnet: subnet("10.0.0.0/8")}})
+	require.NoError(t, err)
+
+	require.True(t, l.Allowed(office, ""))
+	require.False(t, l.Allowed(outside, ""))
+
+	// запрет сильнее разрешения
+	require.NoError(t, l.Add(ctx, Rule{Action: ActionDeny, Subnet: subnet("10.1.9.0/24")}))
+	require.False(t, l.Allowed(guest, ""))
+	require.True(t, l.Allowed(office, ""))
+
+	// клиент с идентификатором разрешен из любой сети
+	require.NoError(t, l.Add(ctx, Rule{Action: ActionAllow, ClientID: "laptop"}))
+	require.True(t, l.Allowed(outside, "laptop"))
+
+	// правила сохраняются и переживают перезапуск
+	restored, err := New(storage, nil)
+	require.NoError(t, err)
+	require.NoError(t, restored.Load(ctx))
+	require.Len(t, restored.Rules(), 2)
+	require.True(t, restored.Allowed(outside, "laptop"))
+	require.False(t, restored.Allowed(guest, ""))
+
+	// правила конфигурации нельзя удалить через API
+	require.Error(t, l.Delete(ctx, Rule{Action: ActionAllow, Subnet: subnet("10.0.0.0/8")}))
+	require.NoError(t, l.Delete(ctx, Rule{Action: ActionDeny, Subnet: subnet("10.1.9.0/24")}))
+	require.True(t, l.Allowed(guest, ""))
+	require.Len(t, storage.rules, 1)
+}
+
+func TestRuleValidation(t *testing.T) {
+	l, err := New(&fakeStorage{}, nil)
+	require.NoError(t, err)
+
+	require.True(t, l.Allowed(netip.MustParseAddr("192.0.2.1"), ""))
+	require.Error(t, l.Add(context.Background(), Rule{Action: "maybe", ClientID: "x"}))
+	require.Error(t, l.Add(context.Background(), Rule{Action: ActionDeny}))
+	require.Error(t, l.Add(context.Background(), Rule{Action: ActionDeny, ClientID: "x", Subnet: subnet("192.0.2.1")}))
+
+	_, err = ParseSubnet("not a subnet")
+	require.Error(t, err)
+	require.Equal(t, "192.0.2.0/24", subnet("192.0.2.7/24").String())
+}
+
+// slowStorage держит запись, пока не закроют release.
+type slowStorage struct {
+	fakeStorage
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (s *slowStorage) AddAccessRule(ctx context.Context, rule datastore.AccessRule) error {
+	close(s.writing)
+	<-s.release
+	return s.fakeStorage.AddAccessRule(ctx, rule)
+}
+
+func TestListSlowStorage(t *testing.T) {
+	storage := &slowStorage{writing: make(chan struct{}), release: make(chan struct{})}
+	l, err := New(storage, nil)
+	require.NoError(t, err)
+
+	added := make(chan error)
+	go func() {
+		added <- l.Add(context.Background(), Rule{Action: ActionDeny, Subnet: subnet("192.0.2.0/24")})
+	}()
+
+	// проверка доступа не ждет записи в хранилище
+	<-storage.writing
+	require.True(t, l.Allowed(netip.MustParseAddr("192.0.2.1"), ""))
+
+	close(storage.release)
+	require.NoError(t, <-added)
+	require.False(t, l.Allowed(netip.MustParseAddr("192.0.2.1"), ""))
+}
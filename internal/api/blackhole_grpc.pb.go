@@ -21,10 +21,44 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Blackhole_Block_FullMethodName          = "/denisdubovitskiy.blackhole.api.Blackhole/Block"
-	Blackhole_Unblock_FullMethodName        = "/denisdubovitskiy.blackhole.api.Blackhole/Unblock"
-	Blackhole_AddSource_FullMethodName      = "/denisdubovitskiy.blackhole.api.Blackhole/AddSource"
-	Blackhole_RefreshSources_FullMethodName = "/denisdubovitskiy.blackhole.api.Blackhole/RefreshSources"
+	Blackhole_Block_FullMethodName                = "/denisdubovitskiy.blackhole.api.Blackhole/Block"
+	Blackhole_Unblock_FullMethodName              = "/denisdubovitskiy.blackhole.api.Blackhole/Unblock"
+	Blackhole_AddSource_FullMethodName            = "/denisdubovitskiy.blackhole.api.Blackhole/AddSource"
+	Blackhole_RefreshSources_FullMethodName       = "/denisdubovitskiy.blackhole.api.Blackhole/RefreshSources"
+	Blackhole_ListUpstreams_FullMethodName        = "/denisdubovitskiy.blackhole.api.Blackhole/ListUpstreams"
+	Blackhole_SetUpstreams_FullMethodName         = "/denisdubovitskiy.blackhole.api.Blackhole/SetUpstreams"
+	Blackhole_ListUpstreamGroups_FullMethodName   = "/denisdubovitskiy.blackhole.api.Blackhole/ListUpstreamGroups"
+	Blackhole_DeleteUpstreamGroup_FullMethodName  = "/denisdubovitskiy.blackhole.api.Blackhole/DeleteUpstreamGroup"
+	Blackhole_ListForwardingRules_FullMethodName  = "/denisdubovitskiy.blackhole.api.Blackhole/ListForwardingRules"
+	Blackhole_SetForwardingRule_FullMethodName    = "/denisdubovitskiy.blackhole.api.Blackhole/SetForwardingRule"
+	Blackhole_DeleteForwardingRule_FullMethodName = "/denisdubovitskiy.blackhole.api.Blackhole/DeleteForwardingRule"
+	Blackhole_ListAccessRules_FullMethodName      = "/denisdubovitskiy.blackhole.api.Blackhole/ListAccessRules"
+	Blackhole_AddAccessRule_FullMethodName        = "/denisdubovitskiy.blackhole.api.Blackhole/AddAccessRule"
+	Blackhole_DeleteAccessRule_FullMethodName     = "/denisdubovitskiy.blackhole.api.Blackhole/DeleteAccessRule"
+	Blackhole_ListSchedules_FullMethodName        = "/denisdubovitskiy.blackhole.api.Blackhole/ListSchedules"
+	Blackhole_SetSchedule_FullMethodName          = "/denisdubovitskiy.blackhole.api.Blackhole/SetSchedule"
+	Blackhole_DeleteSchedule_FullMethodName       = "/denisdubovitskiy.blackhole.api.Blackhole/DeleteSchedule"
+	Blackhole_AttachSchedule_FullMethodName       = "/denisdubovitskiy.blackhole.api.Blackhole/AttachSchedule"
+	Blackhole_DetachSchedule_FullMethodName       = "/denisdubovitskiy.blackhole.api.Blackhole/DetachSchedule"
+	Blackhole_ListPauses_FullMethodName           = "/denisdubovitskiy.blackhole.api.Blackhole/ListPauses"
+	Blackhole_PauseProtection_FullMethodName      = "/denisdubovitskiy.blackhole.api.Blackhole/PauseProtection"
+	Blackhole_ResumeProtection_FullMethodName     = "/denisdubovitskiy.blackhole.api.Blackhole/ResumeProtection"
+	Blackhole_ListExpiries_FullMethodName         = "/denisdubovitskiy.blackhole.api.Blackhole/ListExpiries"
+	Blackhole_ListLocalRecords_FullMethodName     = "/denisdubovitskiy.blackhole.api.Blackhole/ListLocalRecords"
+	Blackhole_AddLocalRecord_FullMethodName       = "/denisdubovitskiy.blackhole.api.Blackhole/AddLocalRecord"
+	Blackhole_UpdateLocalRecord_FullMethodName    = "/denisdubovitskiy.blackhole.api.Blackhole/UpdateLocalRecord"
+	Blackhole_DeleteLocalRecord_FullMethodName    = "/denisdubovitskiy.blackhole.api.Blackhole/DeleteLocalRecord"
+	Blackhole_GetSafeSearch_FullMethodName        = "/denisdubovitskiy.blackhole.api.Blackhole/GetSafeSearch"
+	Blackhole_SetSafeSearch_FullMethodName        = "/denisdubovitskiy.blackhole.api.Blackhole/SetSafeSearch"
+	Blackhole_ResetSafeSearch_FullMethodName      = "/denisdubovitskiy.blackhole.api.Blackhole/ResetSafeSearch"
+	Blackhole_ListServices_FullMethodName         = "/denisdubovitskiy.blackhole.api.Blackhole/ListServices"
+	Blackhole_SetService_FullMethodName           = "/denisdubovitskiy.blackhole.api.Blackhole/SetService"
+	Blackhole_ResetService_FullMethodName         = "/denisdubovitskiy.blackhole.api.Blackhole/ResetService"
+	Blackhole_ListRegexRules_FullMethodName       = "/denisdubovitskiy.blackhole.api.Blackhole/ListRegexRules"
+	Blackhole_AddRegexRule_FullMethodName         = "/denisdubovitskiy.blackhole.api.Blackhole/AddRegexRule"
+	Blackhole_DeleteRegexRule_FullMethodName      = "/denisdubovitskiy.blackhole.api.Blackhole/DeleteRegexRule"
+	Blackhole_CheckDomain_FullMethodName          = "/denisdubovitskiy.blackhole.api.Blackhole/CheckDomain"
+	Blackhole_ListDomains_FullMethodName          = "/denisdubovitskiy.blackhole.api.Blackhole/ListDomains"
 )
 
 // BlackholeClient is the client API for Blackhole service.
@@ -35,6 +69,40 @@ type BlackholeClient interface {
 	Unblock(ctx context.Context, in *DomainsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	AddSource(ctx context.Context, in *AddSourceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	RefreshSources(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListUpstreams(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*UpstreamsResponse, error)
+	SetUpstreams(ctx context.Context, in *SetUpstreamsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListUpstreamGroups(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*UpstreamGroupsResponse, error)
+	DeleteUpstreamGroup(ctx context.Context, in *DeleteUpstreamGroupRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListForwardingRules(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ForwardingRulesResponse, error)
+	SetForwardingRule(ctx context.Context, in *ForwardingRule, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteForwardingRule(ctx context.Context, in *DeleteForwardingRuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListAccessRules(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AccessRulesResponse, error)
+	AddAccessRule(ctx context.Context, in *AccessRule, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteAccessRule(ctx context.Context, in *AccessRule, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListSchedules(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SchedulesResponse, error)
+	SetSchedule(ctx context.Context, in *Schedule, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteSchedule(ctx context.Context, in *DeleteScheduleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	AttachSchedule(ctx context.Context, in *ScheduleAttachment, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DetachSchedule(ctx context.Context, in *DetachScheduleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListPauses(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*PausesResponse, error)
+	PauseProtection(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*Pause, error)
+	ResumeProtection(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListExpiries(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ExpiriesResponse, error)
+	ListLocalRecords(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LocalRecordsResponse, error)
+	AddLocalRecord(ctx context.Context, in *LocalRecord, opts ...grpc.CallOption) (*LocalRecord, error)
+	UpdateLocalRecord(ctx context.Context, in *LocalRecord, opts ...grpc.CallOption) (*LocalRecord, error)
+	DeleteLocalRecord(ctx context.Context, in *DeleteLocalRecordRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetSafeSearch(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SafeSearchResponse, error)
+	SetSafeSearch(ctx context.Context, in *SafeSearchSetting, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ResetSafeSearch(ctx context.Context, in *ResetSafeSearchRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListServices(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ServicesResponse, error)
+	SetService(ctx context.Context, in *SetServiceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ResetService(ctx context.Context, in *ResetServiceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListRegexRules(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RegexRulesResponse, error)
+	AddRegexRule(ctx context.Context, in *RegexRule, opts ...grpc.CallOption) (*RegexRule, error)
+	DeleteRegexRule(ctx context.Context, in *DeleteRegexRuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CheckDomain(ctx context.Context, in *CheckDomainRequest, opts ...grpc.CallOption) (*CheckDomainResponse, error)
+	ListDomains(ctx context.Context, in *ListDomainsRequest, opts ...grpc.CallOption) (*ListDomainsResponse, error)
 }
 
 type blackholeClient struct {
@@ -81,6 +149,312 @@ func (c *blackholeClient) RefreshSources(ctx context.Context, in *emptypb.Empty,
 	return out, nil
 }
 
+func (c *blackholeClient) ListUpstreams(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*UpstreamsResponse, error) {
+	out := new(UpstreamsResponse)
+	err := c.cc.Invoke(ctx, Blackhole_ListUpstreams_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) SetUpstreams(ctx context.Context, in *SetUpstreamsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_SetUpstreams_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ListUpstreamGroups(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*UpstreamGroupsResponse, error) {
+	out := new(UpstreamGroupsResponse)
+	err := c.cc.Invoke(ctx, Blackhole_ListUpstreamGroups_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) DeleteUpstreamGroup(ctx context.Context, in *DeleteUpstreamGroupRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_DeleteUpstreamGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ListForwardingRules(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ForwardingRulesResponse, error) {
+	out := new(ForwardingRulesResponse)
+	err := c.cc.Invoke(ctx, Blackhole_ListForwardingRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) SetForwardingRule(ctx context.Context, in *ForwardingRule, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_SetForwardingRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) DeleteForwardingRule(ctx context.Context, in *DeleteForwardingRuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_DeleteForwardingRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ListAccessRules(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AccessRulesResponse, error) {
+	out := new(AccessRulesResponse)
+	err := c.cc.Invoke(ctx, Blackhole_ListAccessRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) AddAccessRule(ctx context.Context, in *AccessRule, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_AddAccessRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) DeleteAccessRule(ctx context.Context, in *AccessRule, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_DeleteAccessRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ListSchedules(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SchedulesResponse, error) {
+	out := new(SchedulesResponse)
+	err := c.cc.Invoke(ctx, Blackhole_ListSchedules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) SetSchedule(ctx context.Context, in *Schedule, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_SetSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) DeleteSchedule(ctx context.Context, in *DeleteScheduleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_DeleteSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) AttachSchedule(ctx context.Context, in *ScheduleAttachment, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_AttachSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) DetachSchedule(ctx context.Context, in *DetachScheduleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_DetachSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ListPauses(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*PausesResponse, error) {
+	out := new(PausesResponse)
+	err := c.cc.Invoke(ctx, Blackhole_ListPauses_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) PauseProtection(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*Pause, error) {
+	out := new(Pause)
+	err := c.cc.Invoke(ctx, Blackhole_PauseProtection_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ResumeProtection(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_ResumeProtection_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ListExpiries(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ExpiriesResponse, error) {
+	out := new(ExpiriesResponse)
+	err := c.cc.Invoke(ctx, Blackhole_ListExpiries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ListLocalRecords(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LocalRecordsResponse, error) {
+	out := new(LocalRecordsResponse)
+	err := c.cc.Invoke(ctx, Blackhole_ListLocalRecords_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) AddLocalRecord(ctx context.Context, in *LocalRecord, opts ...grpc.CallOption) (*LocalRecord, error) {
+	out := new(LocalRecord)
+	err := c.cc.Invoke(ctx, Blackhole_AddLocalRecord_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) UpdateLocalRecord(ctx context.Context, in *LocalRecord, opts ...grpc.CallOption) (*LocalRecord, error) {
+	out := new(LocalRecord)
+	err := c.cc.Invoke(ctx, Blackhole_UpdateLocalRecord_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) DeleteLocalRecord(ctx context.Context, in *DeleteLocalRecordRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_DeleteLocalRecord_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) GetSafeSearch(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SafeSearchResponse, error) {
+	out := new(SafeSearchResponse)
+	err := c.cc.Invoke(ctx, Blackhole_GetSafeSearch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) SetSafeSearch(ctx context.Context, in *SafeSearchSetting, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_SetSafeSearch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ResetSafeSearch(ctx context.Context, in *ResetSafeSearchRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_ResetSafeSearch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ListServices(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ServicesResponse, error) {
+	out := new(ServicesResponse)
+	err := c.cc.Invoke(ctx, Blackhole_ListServices_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) SetService(ctx context.Context, in *SetServiceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_SetService_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ResetService(ctx context.Context, in *ResetServiceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_ResetService_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ListRegexRules(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RegexRulesResponse, error) {
+	out := new(RegexRulesResponse)
+	err := c.cc.Invoke(ctx, Blackhole_ListRegexRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) AddRegexRule(ctx context.Context, in *RegexRule, opts ...grpc.CallOption) (*RegexRule, error) {
+	out := new(RegexRule)
+	err := c.cc.Invoke(ctx, Blackhole_AddRegexRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) DeleteRegexRule(ctx context.Context, in *DeleteRegexRuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Blackhole_DeleteRegexRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) CheckDomain(ctx context.Context, in *CheckDomainRequest, opts ...grpc.CallOption) (*CheckDomainResponse, error) {
+	out := new(CheckDomainResponse)
+	err := c.cc.Invoke(ctx, Blackhole_CheckDomain_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackholeClient) ListDomains(ctx context.Context, in *ListDomainsRequest, opts ...grpc.CallOption) (*ListDomainsResponse, error) {
+	out := new(ListDomainsResponse)
+	err := c.cc.Invoke(ctx, Blackhole_ListDomains_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BlackholeServer is the dnsserver API for Blackhole service.
 // All implementations must embed UnimplementedBlackholeServer
 // for forward compatibility
@@ -89,6 +463,40 @@ type BlackholeServer interface {
 	Unblock(context.Context, *DomainsRequest) (*emptypb.Empty, error)
 	AddSource(context.Context, *AddSourceRequest) (*emptypb.Empty, error)
 	RefreshSources(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	ListUpstreams(context.Context, *emptypb.Empty) (*UpstreamsResponse, error)
+	SetUpstreams(context.Context, *SetUpstreamsRequest) (*emptypb.Empty, error)
+	ListUpstreamGroups(context.Context, *emptypb.Empty) (*UpstreamGroupsResponse, error)
+	DeleteUpstreamGroup(context.Context, *DeleteUpstreamGroupRequest) (*emptypb.Empty, error)
+	ListForwardingRules(context.Context, *emptypb.Empty) (*ForwardingRulesResponse, error)
+	SetForwardingRule(context.Context, *ForwardingRule) (*emptypb.Empty, error)
+	DeleteForwardingRule(context.Context, *DeleteForwardingRuleRequest) (*emptypb.Empty, error)
+	ListAccessRules(context.Context, *emptypb.Empty) (*AccessRulesResponse, error)
+	AddAccessRule(context.Context, *AccessRule) (*emptypb.Empty, error)
+	DeleteAccessRule(context.Context, *AccessRule) (*emptypb.Empty, error)
+	ListSchedules(context.Context, *emptypb.Empty) (*SchedulesResponse, error)
+	SetSchedule(context.Context, *Schedule) (*emptypb.Empty, error)
+	DeleteSchedule(context.Context, *DeleteScheduleRequest) (*emptypb.Empty, error)
+	AttachSchedule(context.Context, *ScheduleAttachment) (*emptypb.Empty, error)
+	DetachSchedule(context.Context, *DetachScheduleRequest) (*emptypb.Empty, error)
+	ListPauses(context.Context, *emptypb.Empty) (*PausesResponse, error)
+	PauseProtection(context.Context, *PauseRequest) (*Pause, error)
+	ResumeProtection(context.Context, *ResumeRequest) (*emptypb.Empty, error)
+	ListExpiries(context.Context, *emptypb.Empty) (*ExpiriesResponse, error)
+	ListLocalRecords(context.Context, *emptypb.Empty) (*LocalRecordsResponse, error)
+	AddLocalRecord(context.Context, *LocalRecord) (*LocalRecord, error)
+	UpdateLocalRecord(context.Context, *LocalRecord) (*LocalRecord, error)
+	DeleteLocalRecord(context.Context, *DeleteLocalRecordRequest) (*emptypb.Empty, error)
+	GetSafeSearch(context.Context, *emptypb.Empty) (*SafeSearchResponse, error)
+	SetSafeSearch(context.Context, *SafeSearchSetting) (*emptypb.Empty, error)
+	ResetSafeSearch(context.Context, *ResetSafeSearchRequest) (*emptypb.Empty, error)
+	ListServices(context.Context, *emptypb.Empty) (*ServicesResponse, error)
+	SetService(context.Context, *SetServiceRequest) (*emptypb.Empty, error)
+	ResetService(context.Context, *ResetServiceRequest) (*emptypb.Empty, error)
+	ListRegexRules(context.Context, *emptypb.Empty) (*RegexRulesResponse, error)
+	AddRegexRule(context.Context, *RegexRule) (*RegexRule, error)
+	DeleteRegexRule(context.Context, *DeleteRegexRuleRequest) (*emptypb.Empty, error)
+	CheckDomain(context.Context, *CheckDomainRequest) (*CheckDomainResponse, error)
+	ListDomains(context.Context, *ListDomainsRequest) (*ListDomainsResponse, error)
 	mustEmbedUnimplementedBlackholeServer()
 }
 
@@ -108,6 +516,108 @@ func (UnimplementedBlackholeServer) AddSource(context.Context, *AddSourceRequest
 func (UnimplementedBlackholeServer) RefreshSources(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RefreshSources not implemented")
 }
+func (UnimplementedBlackholeServer) ListUpstreams(context.Context, *emptypb.Empty) (*UpstreamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUpstreams not implemented")
+}
+func (UnimplementedBlackholeServer) SetUpstreams(context.Context, *SetUpstreamsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUpstreams not implemented")
+}
+func (UnimplementedBlackholeServer) ListUpstreamGroups(context.Context, *emptypb.Empty) (*UpstreamGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUpstreamGroups not implemented")
+}
+func (UnimplementedBlackholeServer) DeleteUpstreamGroup(context.Context, *DeleteUpstreamGroupRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUpstreamGroup not implemented")
+}
+func (UnimplementedBlackholeServer) ListForwardingRules(context.Context, *emptypb.Empty) (*ForwardingRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListForwardingRules not implemented")
+}
+func (UnimplementedBlackholeServer) SetForwardingRule(context.Context, *ForwardingRule) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetForwardingRule not implemented")
+}
+func (UnimplementedBlackholeServer) DeleteForwardingRule(context.Context, *DeleteForwardingRuleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteForwardingRule not implemented")
+}
+func (UnimplementedBlackholeServer) ListAccessRules(context.Context, *emptypb.Empty) (*AccessRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccessRules not implemented")
+}
+func (UnimplementedBlackholeServer) AddAccessRule(context.Context, *AccessRule) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddAccessRule not implemented")
+}
+func (UnimplementedBlackholeServer) DeleteAccessRule(context.Context, *AccessRule) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccessRule not implemented")
+}
+func (UnimplementedBlackholeServer) ListSchedules(context.Context, *emptypb.Empty) (*SchedulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchedules not implemented")
+}
+func (UnimplementedBlackholeServer) SetSchedule(context.Context, *Schedule) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSchedule not implemented")
+}
+func (UnimplementedBlackholeServer) DeleteSchedule(context.Context, *DeleteScheduleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSchedule not implemented")
+}
+func (UnimplementedBlackholeServer) AttachSchedule(context.Context, *ScheduleAttachment) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AttachSchedule not implemented")
+}
+func (UnimplementedBlackholeServer) DetachSchedule(context.Context, *DetachScheduleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DetachSchedule not implemented")
+}
+func (UnimplementedBlackholeServer) ListPauses(context.Context, *emptypb.Empty) (*PausesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPauses not implemented")
+}
+func (UnimplementedBlackholeServer) PauseProtection(context.Context, *PauseRequest) (*Pause, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseProtection not implemented")
+}
+func (UnimplementedBlackholeServer) ResumeProtection(context.Context, *ResumeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeProtection not implemented")
+}
+func (UnimplementedBlackholeServer) ListExpiries(context.Context, *emptypb.Empty) (*ExpiriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListExpiries not implemented")
+}
+func (UnimplementedBlackholeServer) ListLocalRecords(context.Context, *emptypb.Empty) (*LocalRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLocalRecords not implemented")
+}
+func (UnimplementedBlackholeServer) AddLocalRecord(context.Context, *LocalRecord) (*LocalRecord, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddLocalRecord not implemented")
+}
+func (UnimplementedBlackholeServer) UpdateLocalRecord(context.Context, *LocalRecord) (*LocalRecord, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLocalRecord not implemented")
+}
+func (UnimplementedBlackholeServer) DeleteLocalRecord(context.Context, *DeleteLocalRecordRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLocalRecord not implemented")
+}
+func (UnimplementedBlackholeServer) GetSafeSearch(context.Context, *emptypb.Empty) (*SafeSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSafeSearch not implemented")
+}
+func (UnimplementedBlackholeServer) SetSafeSearch(context.Context, *SafeSearchSetting) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSafeSearch not implemented")
+}
+func (UnimplementedBlackholeServer) ResetSafeSearch(context.Context, *ResetSafeSearchRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetSafeSearch not implemented")
+}
+func (UnimplementedBlackholeServer) ListServices(context.Context, *emptypb.Empty) (*ServicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServices not implemented")
+}
+func (UnimplementedBlackholeServer) SetService(context.Context, *SetServiceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetService not implemented")
+}
+func (UnimplementedBlackholeServer) ResetService(context.Context, *ResetServiceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetService not implemented")
+}
+func (UnimplementedBlackholeServer) ListRegexRules(context.Context, *emptypb.Empty) (*RegexRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRegexRules not implemented")
+}
+func (UnimplementedBlackholeServer) AddRegexRule(context.Context, *RegexRule) (*RegexRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRegexRule not implemented")
+}
+func (UnimplementedBlackholeServer) DeleteRegexRule(context.Context, *DeleteRegexRuleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRegexRule not implemented")
+}
+func (UnimplementedBlackholeServer) CheckDomain(context.Context, *CheckDomainRequest) (*CheckDomainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckDomain not implemented")
+}
+func (UnimplementedBlackholeServer) ListDomains(context.Context, *ListDomainsRequest) (*ListDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDomains not implemented")
+}
 func (UnimplementedBlackholeServer) mustEmbedUnimplementedBlackholeServer() {}
 
 // UnsafeBlackholeServer may be embedded to opt out of forward compatibility for this service.
@@ -193,7 +703,619 @@ func _Blackhole_RefreshSources_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
-// Blackhole_ServiceDesc is the grpc.ServiceDesc for Blackhole service.
+func _Blackhole_ListUpstreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ListUpstreams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ListUpstreams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ListUpstreams(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_SetUpstreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUpstreamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).SetUpstreams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_SetUpstreams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).SetUpstreams(ctx, req.(*SetUpstreamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ListUpstreamGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ListUpstreamGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ListUpstreamGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ListUpstreamGroups(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_DeleteUpstreamGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUpstreamGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).DeleteUpstreamGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_DeleteUpstreamGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).DeleteUpstreamGroup(ctx, req.(*DeleteUpstreamGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ListForwardingRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ListForwardingRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ListForwardingRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ListForwardingRules(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_SetForwardingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardingRule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).SetForwardingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_SetForwardingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).SetForwardingRule(ctx, req.(*ForwardingRule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_DeleteForwardingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteForwardingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).DeleteForwardingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_DeleteForwardingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).DeleteForwardingRule(ctx, req.(*DeleteForwardingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ListAccessRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ListAccessRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ListAccessRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ListAccessRules(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_AddAccessRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccessRule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).AddAccessRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_AddAccessRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).AddAccessRule(ctx, req.(*AccessRule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_DeleteAccessRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccessRule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).DeleteAccessRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_DeleteAccessRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).DeleteAccessRule(ctx, req.(*AccessRule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ListSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ListSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ListSchedules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ListSchedules(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_SetSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Schedule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).SetSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_SetSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).SetSchedule(ctx, req.(*Schedule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_DeleteSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).DeleteSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_DeleteSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).DeleteSchedule(ctx, req.(*DeleteScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_AttachSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleAttachment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).AttachSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_AttachSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).AttachSchedule(ctx, req.(*ScheduleAttachment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_DetachSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetachScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).DetachSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_DetachSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).DetachSchedule(ctx, req.(*DetachScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ListPauses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ListPauses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ListPauses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ListPauses(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_PauseProtection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).PauseProtection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_PauseProtection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).PauseProtection(ctx, req.(*PauseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ResumeProtection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ResumeProtection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ResumeProtection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ResumeProtection(ctx, req.(*ResumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ListExpiries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ListExpiries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ListExpiries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ListExpiries(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ListLocalRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ListLocalRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ListLocalRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ListLocalRecords(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_AddLocalRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocalRecord)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).AddLocalRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_AddLocalRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).AddLocalRecord(ctx, req.(*LocalRecord))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_UpdateLocalRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocalRecord)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).UpdateLocalRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_UpdateLocalRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).UpdateLocalRecord(ctx, req.(*LocalRecord))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_DeleteLocalRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLocalRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).DeleteLocalRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_DeleteLocalRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).DeleteLocalRecord(ctx, req.(*DeleteLocalRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_GetSafeSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).GetSafeSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_GetSafeSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).GetSafeSearch(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_SetSafeSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SafeSearchSetting)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).SetSafeSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_SetSafeSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).SetSafeSearch(ctx, req.(*SafeSearchSetting))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ResetSafeSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetSafeSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ResetSafeSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ResetSafeSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ResetSafeSearch(ctx, req.(*ResetSafeSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ListServices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ListServices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ListServices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ListServices(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_SetService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetServiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).SetService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_SetService_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).SetService(ctx, req.(*SetServiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ResetService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetServiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ResetService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ResetService_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ResetService(ctx, req.(*ResetServiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ListRegexRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ListRegexRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ListRegexRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ListRegexRules(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_AddRegexRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegexRule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).AddRegexRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_AddRegexRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).AddRegexRule(ctx, req.(*RegexRule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_DeleteRegexRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRegexRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).DeleteRegexRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_DeleteRegexRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).DeleteRegexRule(ctx, req.(*DeleteRegexRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_CheckDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).CheckDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_CheckDomain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).CheckDomain(ctx, req.(*CheckDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackhole_ListDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackholeServer).ListDomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackhole_ListDomains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackholeServer).ListDomains(ctx, req.(*ListDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Blackhole_ServiceDesc is the grpc.ServiceDesc for Blackhole service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var Blackhole_ServiceDesc = grpc.ServiceDesc{
@@ -216,6 +1338,142 @@ var Blackhole_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RefreshSources",
 			Handler:    _Blackhole_RefreshSources_Handler,
 		},
+		{
+			MethodName: "ListUpstreams",
+			Handler:    _Blackhole_ListUpstreams_Handler,
+		},
+		{
+			MethodName: "SetUpstreams",
+			Handler:    _Blackhole_SetUpstreams_Handler,
+		},
+		{
+			MethodName: "ListUpstreamGroups",
+			Handler:    _Blackhole_ListUpstreamGroups_Handler,
+		},
+		{
+			MethodName: "DeleteUpstreamGroup",
+			Handler:    _Blackhole_DeleteUpstreamGroup_Handler,
+		},
+		{
+			MethodName: "ListForwardingRules",
+			Handler:    _Blackhole_ListForwardingRules_Handler,
+		},
+		{
+			MethodName: "SetForwardingRule",
+			Handler:    _Blackhole_SetForwardingRule_Handler,
+		},
+		{
+			MethodName: "DeleteForwardingRule",
+			Handler:    _Blackhole_DeleteForwardingRule_Handler,
+		},
+		{
+			MethodName: "ListAccessRules",
+			Handler:    _Blackhole_ListAccessRules_Handler,
+		},
+		{
+			MethodName: "AddAccessRule",
+			Handler:    _Blackhole_AddAccessRule_Handler,
+		},
+		{
+			MethodName: "DeleteAccessRule",
+			Handler:    _Blackhole_DeleteAccessRule_Handler,
+		},
+		{
+			MethodName: "ListSchedules",
+			Handler:    _Blackhole_ListSchedules_Handler,
+		},
+		{
+			MethodName: "SetSchedule",
+			Handler:    _Blackhole_SetSchedule_Handler,
+		},
+		{
+			MethodName: "DeleteSchedule",
+			Handler:    _Blackhole_DeleteSchedule_Handler,
+		},
+		{
+			MethodName: "AttachSchedule",
+			Handler:    _Blackhole_AttachSchedule_Handler,
+		},
+		{
+			MethodName: "DetachSchedule",
+			Handler:    _Blackhole_DetachSchedule_Handler,
+		},
+		{
+			MethodName: "ListPauses",
+			Handler:    _Blackhole_ListPauses_Handler,
+		},
+		{
+			MethodName: "PauseProtection",
+			Handler:    _Blackhole_PauseProtection_Handler,
+		},
+		{
+			MethodName: "ResumeProtection",
+			Handler:    _Blackhole_ResumeProtection_Handler,
+		},
+		{
+			MethodName: "ListExpiries",
+			Handler:    _Blackhole_ListExpiries_Handler,
+		},
+		{
+			MethodName: "ListLocalRecords",
+			Handler:    _Blackhole_ListLocalRecords_Handler,
+		},
+		{
+			MethodName: "AddLocalRecord",
+			Handler:    _Blackhole_AddLocalRecord_Handler,
+		},
+		{
+			MethodName: "UpdateLocalRecord",
+			Handler:    _Blackhole_UpdateLocalRecord_Handler,
+		},
+		{
+			MethodName: "DeleteLocalRecord",
+			Handler:    _Blackhole_DeleteLocalRecord_Handler,
+		},
+		{
+			MethodName: "GetSafeSearch",
+			Handler:    _Blackhole_GetSafeSearch_Handler,
+		},
+		{
+			MethodName: "SetSafeSearch",
+			Handler:    _Blackhole_SetSafeSearch_Handler,
+		},
+		{
+			MethodName: "ResetSafeSearch",
+			Handler:    _Blackhole_ResetSafeSearch_Handler,
+		},
+		{
+			MethodName: "ListServices",
+			Handler:    _Blackhole_ListServices_Handler,
+		},
+		{
+			MethodName: "SetService",
+			Handler:    _Blackhole_SetService_Handler,
+		},
+		{
+			MethodName: "ResetService",
+			Handler:    _Blackhole_ResetService_Handler,
+		},
+		{
+			MethodName: "ListRegexRules",
+			Handler:    _Blackhole_ListRegexRules_Handler,
+		},
+		{
+			MethodName: "AddRegexRule",
+			Handler:    _Blackhole_AddRegexRule_Handler,
+		},
+		{
+			MethodName: "DeleteRegexRule",
+			Handler:    _Blackhole_DeleteRegexRule_Handler,
+		},
+		{
+			MethodName: "CheckDomain",
+			Handler:    _Blackhole_CheckDomain_Handler,
+		},
+		{
+			MethodName: "ListDomains",
+			Handler:    _Blackhole_ListDomains_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "blackhole.proto",
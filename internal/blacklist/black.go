@@ -7,42 +7,101 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/miekg/dns"
 	"go.uber.org/atomic"
 )
 
+// OriginManual - происхождение доменов, заблокированных через API,
+// в отличие от загруженных из источников, происхождение которых - URL.
+const OriginManual = "manual"
+
+// OriginServicePrefix - префикс происхождения правил сервисов из каталога:
+// service:tiktok.
+const OriginServicePrefix = "service:"
+
+// OriginRegex - происхождение блокировок по регулярным выражениям.
+const OriginRegex = "regex"
+
+// wildcardPrefix - домен вида *.example.com. блокирует все поддомены
+// example.com., но не его самого.
+const wildcardPrefix = "*."
+
 func NewBucket() *Bucket {
-	return &Bucket{data: make(map[string]struct{})}
+	return &Bucket{data: make(map[string][]uint16)}
 }
 
+// Bucket хранит для каждого домена номера источников, в которых он
+// встретился.
 type Bucket struct {
 	mu   sync.RWMutex
-	data map[string]struct{}
+	data map[string][]uint16
 }
 
 func (c *Bucket) Has(domain string) bool {
+	_, ok := c.Get(domain)
+	return ok
+}
+
+func (c *Bucket) Get(domain string) ([]uint16, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	domain = clean(domain)
 	if domain == "" {
-		return false
+		return nil, false
 	}
 
-	_, ok := c.data[domain]
-	return ok
+	origins, ok := c.data[domain]
+	return origins, ok
+}
+
+// Add добавляет домен и сообщает, не было ли его раньше.
+func (c *Bucket) Add(domain string, origin uint16) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	origins, ok := c.data[domain]
+	for _, o := range origins {
+		if o == origin {
+			return false
+		}
+	}
+	c.data[domain] = append(origins, origin)
+	return !ok
 }
 
-func (c *Bucket) Add(domain string) {
+// Remove удаляет домен и сообщает, был ли он.
+func (c *Bucket) Remove(domain string) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.data[domain] = struct{}{}
+
+	_, ok := c.data[domain]
+	delete(c.data, domain)
+	return ok
 }
 
-func (c *Bucket) Remove(domain string) {
+// RemoveOrigin удаляет источник домена и сообщает, остался ли домен
+// без источников и удален целиком.
+func (c *Bucket) RemoveOrigin(domain string, origin uint16) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	origins, ok := c.data[domain]
+	if !ok {
+		return false
+	}
+	rest := make([]uint16, 0, len(origins))
+	for _, o := range origins {
+		if o != origin {
+			rest = append(rest, o)
+		}
+	}
+	if len(rest) > 0 {
+		c.data[domain] = rest
+		return false
+	}
 	delete(c.data, domain)
+	return true
 }
 
 func (c *Bucket) Len() int {
@@ -55,8 +114,22 @@ func (c *Bucket) Len() int {
 type BlackList struct {
 	buckets      []*Bucket
 	domainsCount *atomic.Int32
-	hits         *atomic.Int32
-	misses       *atomic.Int32
+	// wildcards - количество шаблонов, без них поиск по родительским
+	// доменам пропускается
+	wildcards *atomic.Int32
+	hits      *atomic.Int32
+	misses    *atomic.Int32
+
+	// exceptions - домены, которые не блокируются никакими правилами,
+	// в том числе шаблонами родительских доменов
+	exceptionsMu    sync.RWMutex
+	exceptions      map[string]bool
+	exceptionsCount *atomic.Int32
+
+	// происхождения хранятся в корзинах номерами
+	originsMu sync.RWMutex
+	origins   []string
+	originIDs map[string]uint16
 }
 
 func New(bucketsCount int) *BlackList {
@@ -67,12 +140,19 @@ func New(bucketsCount int) *BlackList {
 	b := &BlackList{
 		buckets:      buckets,
 		domainsCount: atomic.NewInt32(0),
+		wildcards:    atomic.NewInt32(0),
 		hits:         atomic.NewInt32(0),
 		misses:       atomic.NewInt32(0),
+		originIDs:    make(map[string]uint16),
+
+		exceptions:      make(map[string]bool),
+		exceptionsCount: atomic.NewInt32(0),
+	}
+	if expvar.Get("blackhole_blacklist") == nil {
+		expvar.Publish("blackhole_blacklist", expvar.Func(func() any {
+			return b.dumpStats()
+		}))
 	}
-	expvar.Publish("blackhole_blacklist", expvar.Func(func() any {
-		return b.dumpStats()
-	}))
 	return b
 }
 
@@ -122,20 +202,65 @@ func (b *BlackList) remove(domain string) bool {
 		return false
 	}
 	idx := b.calcBucketIndex(domain)
-	b.buckets[idx].Remove(domain)
+	if !b.buckets[idx].Remove(domain) {
+		return false
+	}
+	if isWildcard(domain) {
+		b.wildcards.Dec()
+	}
 	return true
 }
 
-func (b *BlackList) add(domain string) bool {
+func (b *BlackList) add(domain string, origin uint16) bool {
 	domain = clean(domain)
 	if domain == "" {
 		return false
 	}
 	idx := b.calcBucketIndex(domain)
-	b.buckets[idx].Add(domain)
+	if !b.buckets[idx].Add(domain, origin) {
+		return false
+	}
+	if isWildcard(domain) {
+		b.wildcards.Inc()
+	}
 	return true
 }
 
+func isWildcard(domain string) bool {
+	return strings.HasPrefix(domain, wildcardPrefix)
+}
+
+func (b *BlackList) originID(origin string) uint16 {
+	b.originsMu.RLock()
+	id, ok := b.originIDs[origin]
+	b.originsMu.RUnlock()
+	if ok {
+		return id
+	}
+
+	b.originsMu.Lock()
+	defer b.originsMu.Unlock()
+
+	if id, ok := b.originIDs[origin]; ok {
+		return id
+	}
+	id = uint16(len(b.origins))
+	b.origins = append(b.origins, origin)
+	b.originIDs[origin] = id
+	return id
+}
+
+func (b *BlackList) originNames(ids []uint16) []string {
+	b.originsMu.RLock()
+	defer b.originsMu.RUnlock()
+
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = b.origins[id]
+	}
+	return names
+}
+
 func clean(domain string) string {
 	domain = strings.TrimSpace(domain)
 	if len(domain) == 0 {
@@ -149,9 +274,18 @@ func clean(domain string) string {
 	return domain
 }
 
+// Add блокирует домены вручную.
 func (b *BlackList) Add(ctx context.Context, domains ...string) (count int) {
+	return b.AddFrom(ctx, OriginManual, domains...)
+}
+
+// AddFrom блокирует домены из источника origin и возвращает количество
+// доменов, которых раньше не было.
+func (b *BlackList) AddFrom(ctx context.Context, origin string, domains ...string) (count int) {
+	id := b.originID(origin)
+
 	for _, domain := range domains {
-		if b.add(domain) {
+		if b.add(domain, id) {
 			count++
 			b.domainsCount.Inc()
 		}
@@ -171,14 +305,174 @@ func (b *BlackList) Remove(ctx context.Context, domains ...string) (count int) {
 	return
 }
 
+// RemoveFrom снимает блокировку доменов источником origin, не трогая
+// другие источники, и возвращает количество доменов, которые перестали
+// блокироваться.
+func (b *BlackList) RemoveFrom(ctx context.Context, origin string, domains ...string) (count int) {
+	id := b.originID(origin)
+
+	for _, domain := range domains {
+		domain = clean(domain)
+		if domain == "" {
+			continue
+		}
+		if b.buckets[b.calcBucketIndex(domain)].RemoveOrigin(domain, id) {
+			count++
+			b.domainsCount.Dec()
+			if isWildcard(domain) {
+				b.wildcards.Dec()
+			}
+		}
+	}
+
+	return
+}
+
 func (b *BlackList) Has(ctx context.Context, domain string) bool {
+	return len(b.Origins(ctx, domain)) > 0
+}
+
+// Origins возвращает источники, в которых встретился домен или шаблон
+// одного из его родительских доменов, или nil, если домен
+// не заблокирован.
+func (b *BlackList) Origins(ctx context.Context, domain string) []string {
 	domain = clean(domain)
-	idx := b.calcBucketIndex(domain)
-	has := b.buckets[idx].Has(domain)
-	if has {
-		b.hits.Inc()
-	} else {
+	if b.excepted(domain) {
+		b.misses.Inc()
+		return nil
+	}
+	ids, has := b.get(domain)
+
+	if b.wildcards.Load() > 0 && domain != "" {
+		for off, end := dns.NextLabel(domain, 0); !end; off, end = dns.NextLabel(domain, off) {
+			if matched, ok := b.get(wildcardPrefix + domain[off:]); ok {
+				ids = mergeIDs(ids, matched)
+				has = true
+			}
+		}
+	}
+
+	if !has {
+		b.misses.Inc()
+		return nil
+	}
+	b.hits.Inc()
+	return b.originNames(ids)
+}
+
+// Match - правило черного списка, под которое попал домен: сам домен
+// или шаблон родительского домена, и его источники.
+type Match struct {
+	Rule    string
+	Origins []string
+}
+
+// Matches возвращает все правила, под которые попал домен, от самого
+// домена к шаблонам родительских доменов. В отличие от Origins,
+// статистика попаданий не меняется.
+func (b *BlackList) Matches(ctx context.Context, domain string) []Match {
+	domain = clean(domain)
+	if domain == "" || b.excepted(domain) {
+		return nil
+	}
+
+	var matches []Match
+	if ids, ok := b.get(domain); ok {
+		matches = append(matches, Match{Rule: domain, Origins: b.originNames(ids)})
+	}
+	if b.wildcards.Load() == 0 {
+		return matches
+	}
+	for off, end := dns.NextLabel(domain, 0); !end; off, end = dns.NextLabel(domain, off) {
+		rule := wildcardPrefix + domain[off:]
+		if ids, ok := b.get(rule); ok {
+			matches = append(matches, Match{Rule: rule, Origins: b.originNames(ids)})
+		}
+	}
+	return matches
+}
+
+// Lookup возвращает правила, под которые попал домен, как Matches,
+// и учитывает попадание в статистике.
+func (b *BlackList) Lookup(ctx context.Context, domain string) []Match {
+	matches := b.Matches(ctx, domain)
+	if len(matches) == 0 {
 		b.misses.Inc()
+		return nil
+	}
+	b.hits.Inc()
+	return matches
+}
+
+// ExactOrigins возвращает источники правила для самого домена без
+// шаблонов родительских доменов и исключений. Статистика попаданий
+// не меняется.
+func (b *BlackList) ExactOrigins(ctx context.Context, domain string) []string {
+	domain = clean(domain)
+	if domain == "" {
+		return nil
+	}
+	if ids, ok := b.get(domain); ok {
+		return b.originNames(ids)
+	}
+	return nil
+}
+
+// Except снимает с доменов блокировку всеми правилами, пока исключение
+// не отменят методом Unexcept. Правила при этом сохраняются.
+func (b *BlackList) Except(ctx context.Context, domains ...string) {
+	b.exceptionsMu.Lock()
+	defer b.exceptionsMu.Unlock()
+
+	for _, domain := range domains {
+		domain = clean(domain)
+		if domain != "" && !b.exceptions[domain] {
+			b.exceptions[domain] = true
+			b.exceptionsCount.Inc()
+		}
+	}
+}
+
+// Unexcept отменяет исключения доменов.
+func (b *BlackList) Unexcept(ctx context.Context, domains ...string) {
+	b.exceptionsMu.Lock()
+	defer b.exceptionsMu.Unlock()
+
+	for _, domain := range domains {
+		domain = clean(domain)
+		if b.exceptions[domain] {
+			delete(b.exceptions, domain)
+			b.exceptionsCount.Dec()
+		}
+	}
+}
+
+func (b *BlackList) excepted(domain string) bool {
+	if b.exceptionsCount.Load() == 0 {
+		return false
+	}
+
+	b.exceptionsMu.RLock()
+	defer b.exceptionsMu.RUnlock()
+
+	return b.exceptions[domain]
+}
+
+func (b *BlackList) get(domain string) ([]uint16, bool) {
+	return b.buckets[b.calcBucketIndex(domain)].Get(domain)
+}
+
+// mergeIDs добавляет к ids недостающие номера, не изменяя срез корзины.
+func mergeIDs(ids, other []uint16) []uint16 {
+	ids = ids[:len(ids):len(ids)]
+next:
+	for _, o := range other {
+		for _, id := range ids {
+			if id == o {
+				continue next
+			}
+		}
+		ids = append(ids, o)
 	}
-	return has
+	return ids
 }
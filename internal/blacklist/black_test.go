@@ -21,6 +21,7 @@ func TestBlacklist(t *testing.T) {
 	}
 
 	bl := New(bucketsCount)
+	bl.originID(OriginManual)
 
 	wg := sync.WaitGroup{}
 	wg.Add(len(domains))
@@ -28,7 +29,7 @@ func TestBlacklist(t *testing.T) {
 	for _, domain := range domains {
 		go func(d string) {
 			defer wg.Done()
-			bl.add(d)
+			bl.add(d, 0)
 		}(domain)
 	}
 
@@ -51,3 +52,85 @@ func TestBlacklist(t *testing.T) {
 	require.False(t, bl.Has(context.Background(), " "))
 	require.False(t, bl.Has(context.Background(), ""))
 }
+
+func TestBlacklistOrigins(t *testing.T) {
+	ctx := context.Background()
+	bl := New(16)
+
+	require.Equal(t, 1, bl.AddFrom(ctx, "https://lists.test/ads", "ads.test"))
+	require.Equal(t, 0, bl.AddFrom(ctx, "https://lists.test/trackers", "ads.test"))
+	require.Equal(t, 1, bl.Add(ctx, "manual.test."))
+
+	require.ElementsMatch(t, []string{"https://lists.test/ads", "https://lists.test/trackers"}, bl.Origins(ctx, "ads.test."))
+	require.Equal(t, []string{OriginManual}, bl.Origins(ctx, "manual.test"))
+	require.Nil(t, bl.Origins(ctx, "clean.test"))
+
+	require.Equal(t, 1, bl.Remove(ctx, "ads.test"))
+	require.Equal(t, 0, bl.Remove(ctx, "ads.test"))
+	require.False(t, bl.Has(ctx, "ads.test"))
+	require.Equal(t, int32(1), bl.dumpStats().Domains)
+}
+
+func TestBlacklistRemoveFrom(t *testing.T) {
+	ctx := context.Background()
+	bl := New(16)
+
+	bl.AddFrom(ctx, "https://lists.test/ads", "ads.test")
+	bl.Add(ctx, "ads.test", "manual.test")
+
+	// домен из источника остается заблокированным
+	require.Equal(t, 0, bl.RemoveFrom(ctx, OriginManual, "ads.test"))
+	require.Equal(t, []string{"https://lists.test/ads"}, bl.Origins(ctx, "ads.test"))
+
+	require.Equal(t, 1, bl.RemoveFrom(ctx, OriginManual, "manual.test", "clean.test"))
+	require.False(t, bl.Has(ctx, "manual.test"))
+	require.Equal(t, int32(1), bl.dumpStats().Domains)
+}
+
+func TestBlacklistWildcard(t *testing.T) {
+	ctx := context.Background()
+	bl := New(16)
+
+	bl.AddFrom(ctx, "service:tiktok", "tiktok.com", "*.tiktok.com")
+	bl.Add(ctx, "*.cdn.tiktok.com")
+
+	require.Equal(t, []string{"service:tiktok"}, bl.Origins(ctx, "tiktok.com."))
+	require.Equal(t, []string{"service:tiktok"}, bl.Origins(ctx, "www.tiktok.com."))
+	require.ElementsMatch(t, []string{"service:tiktok", OriginManual}, bl.Origins(ctx, "v16.cdn.tiktok.com."))
+	require.Nil(t, bl.Origins(ctx, "nottiktok.com."))
+	require.Equal(t, []Match{
+		{Rule: "*.cdn.tiktok.com.", Origins: []string{OriginManual}},
+		{Rule: "*.tiktok.com.", Origins: []string{"service:tiktok"}},
+	}, bl.Matches(ctx, "v16.cdn.tiktok.com"))
+	hits := bl.hits.Load()
+	require.Equal(t, bl.Matches(ctx, "v16.cdn.tiktok.com"), bl.Lookup(ctx, "v16.cdn.tiktok.com"))
+	require.Equal(t, hits+1, bl.hits.Load())
+
+	// шаблон не блокирует сам домен
+	bl.Remove(ctx, "tiktok.com")
+	require.False(t, bl.Has(ctx, "tiktok.com"))
+
+	require.Equal(t, 1, bl.RemoveFrom(ctx, "service:tiktok", "*.tiktok.com"))
+	require.False(t, bl.Has(ctx, "www.tiktok.com."))
+	require.Equal(t, []string{OriginManual}, bl.Origins(ctx, "v16.cdn.tiktok.com."))
+	require.Equal(t, int32(1), bl.wildcards.Load())
+}
+
+func TestBlacklistExceptions(t *testing.T) {
+	ctx := context.Background()
+	bl := New(16)
+	bl.AddFrom(ctx, "https://lists.test/ads", "*.ads.test", "cdn.ads.test")
+
+	require.Equal(t, []string{"https://lists.test/ads"}, bl.ExactOrigins(ctx, "cdn.ads.test"))
+	require.Nil(t, bl.ExactOrigins(ctx, "img.ads.test"))
+
+	bl.Except(ctx, "cdn.ads.test")
+	require.False(t, bl.Has(ctx, "cdn.ads.test"))
+	require.Empty(t, bl.Matches(ctx, "cdn.ads.test"))
+	require.True(t, bl.Has(ctx, "img.ads.test"))
+	// правила сохраняются
+	require.Equal(t, []string{"https://lists.test/ads"}, bl.ExactOrigins(ctx, "cdn.ads.test"))
+
+	bl.Unexcept(ctx, "cdn.ads.test")
+	require.True(t, bl.Has(ctx, "cdn.ads.test"))
+}
@@ -12,12 +12,18 @@ import (
 )
 
 type Cache interface {
-	Get(reqType uint16, domain string) (dns.RR, bool)
-	Set(reqType uint16, domain string, ip dns.RR)
+	Get(reqType uint16, domain string) (Entry, bool)
+	Set(reqType uint16, domain string, entry Entry, ttl uint32)
+}
+
+// Entry - закешированный ответ вместе с результатом его проверки DNSSEC.
+type Entry struct {
+	Msg        *dns.Msg
+	Validation string
 }
 
 type Item struct {
-	Ip  dns.RR
+	Entry
 	Die time.Time
 }
 
@@ -40,9 +46,11 @@ func NewMemoryCache() *MemoryCache {
 		cleanups: atomic.NewInt32(0),
 	}
 
-	expvar.Publish("blackhole_cache", expvar.Func(func() any {
-		return cache.dumpStats()
-	}))
+	if expvar.Get("blackhole_cache") == nil {
+		expvar.Publish("blackhole_cache", expvar.Func(func() any {
+			return cache.dumpStats()
+		}))
+	}
 
 	return cache
 }
@@ -58,24 +66,30 @@ func (c *MemoryCache) Close() error {
 	return nil
 }
 
-func (c *MemoryCache) Get(reqType uint16, domain string) (dns.RR, bool) {
+// Get возвращает копию закешированного ответа, в которой TTL записей
+// уменьшены на время, прошедшее с момента сохранения.
+func (c *MemoryCache) Get(reqType uint16, domain string) (Entry, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if m, ok := c.cache[reqType]; ok {
-		if ip, ok := m[domain]; ok {
-			if ip.Die.After(time.Now()) {
+		if item, ok := m[domain]; ok {
+			now := time.Now()
+			if item.Die.After(now) {
 				c.hits.Inc()
-				return ip.Ip, true
+				return Entry{
+					Msg:        withRemainingTTL(item.Msg, item.Die.Sub(now)),
+					Validation: item.Validation,
+				}, true
 			}
 		}
 	}
 
 	c.misses.Inc()
-	return nil, false
+	return Entry{}, false
 }
 
-func (c *MemoryCache) Set(reqType uint16, domain string, ip dns.RR) {
+func (c *MemoryCache) Set(reqType uint16, domain string, entry Entry, ttl uint32) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -88,9 +102,29 @@ func (c *MemoryCache) Set(reqType uint16, domain string, ip dns.RR) {
 	}
 
 	m[domain] = &Item{
-		Ip:  ip,
-		Die: time.Now().Add(time.Duration(ip.Header().Ttl) * time.Second),
+		Entry: Entry{Msg: entry.Msg.Copy(), Validation: entry.Validation},
+		Die:   time.Now().Add(time.Duration(ttl) * time.Second),
+	}
+}
+
+func withRemainingTTL(msg *dns.Msg, remaining time.Duration) *dns.Msg {
+	msg = msg.Copy()
+
+	// округляем вверх, чтобы не отдавать клиентам нулевой TTL
+	left := uint32((remaining + time.Second - 1) / time.Second)
+
+	for _, section := range [][]dns.RR{msg.Answer, msg.Ns, msg.Extra} {
+		for _, rr := range section {
+			if rr.Header().Rrtype == dns.TypeOPT {
+				continue
+			}
+			if rr.Header().Ttl > left {
+				rr.Header().Ttl = left
+			}
+		}
 	}
+
+	return msg
 }
 
 func (c *MemoryCache) cleanPeriodically(ctx context.Context) {
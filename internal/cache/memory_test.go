@@ -8,6 +8,21 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func newMsg(ttl uint32) *dns.Msg {
+	msg := &dns.Msg{}
+	msg.SetQuestion("test.domain.com.", dns.TypeAAAA)
+	msg.Answer = append(msg.Answer, &dns.AAAA{
+		Hdr: dns.RR_Header{
+			Name:   "test.domain.com.",
+			Rrtype: dns.TypeAAAA,
+			Class:  dns.ClassINET,
+			Ttl:    ttl,
+		},
+		AAAA: net.ParseIP("10.160.104.10"),
+	})
+	return msg
+}
+
 func TestCache(t *testing.T) {
 	t.Run("empty.proto cache", func(t *testing.T) {
 		cache := NewMemoryCache()
@@ -20,34 +35,23 @@ func TestCache(t *testing.T) {
 	})
 
 	t.Run("populated cache", func(t *testing.T) {
-		want := &dns.AAAA{
-			Hdr: dns.RR_Header{
-				Name: "test.domain.com",
-				Ttl:  10,
-			},
-			AAAA: net.ParseIP("10.160.104.10"),
-		}
+		want := newMsg(10)
 		cache := NewMemoryCache()
-		cache.Set(dns.TypeAAAA, "test.domain.com", want)
+		cache.Set(dns.TypeAAAA, "test.domain.com", Entry{Msg: want, Validation: "secure"}, 10)
 
 		// act
 		got, ok := cache.Get(dns.TypeAAAA, "test.domain.com")
 
 		// assert
 		require.True(t, ok)
-		require.Equal(t, want, got)
+		require.Equal(t, want.Answer, got.Msg.Answer)
+		require.Equal(t, "secure", got.Validation)
 	})
 
 	t.Run("populated cache (BlockTTL exceeded)", func(t *testing.T) {
-		want := &dns.AAAA{
-			Hdr: dns.RR_Header{
-				Name: "test.domain.com",
-				Ttl:  0,
-			},
-			AAAA: net.ParseIP("10.160.104.10"),
-		}
+		want := newMsg(0)
 		cache := NewMemoryCache()
-		cache.Set(dns.TypeAAAA, "test.domain.com", want)
+		cache.Set(dns.TypeAAAA, "test.domain.com", Entry{Msg: want}, 0)
 
 		// act
 		_, ok := cache.Get(dns.TypeAAAA, "test.domain.com")
@@ -55,4 +59,16 @@ func TestCache(t *testing.T) {
 		// assert
 		require.False(t, ok)
 	})
+
+	t.Run("lifetime shorter than record TTL", func(t *testing.T) {
+		cache := NewMemoryCache()
+		cache.Set(dns.TypeAAAA, "test.domain.com", Entry{Msg: newMsg(3600)}, 30)
+
+		// act
+		got, ok := cache.Get(dns.TypeAAAA, "test.domain.com")
+
+		// assert
+		require.True(t, ok)
+		require.Equal(t, uint32(30), got.Msg.Answer[0].Header().Ttl)
+	})
 }
@@ -1,25 +1,422 @@
 package configuration
 
-import "github.com/spf13/pflag"
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
 
 type Config struct {
-	GrpcAddr              string
-	HttpAddr              string
-	SwaggerAddr           string
-	DebugAddr             string
-	BlacklistBucketsCount int
-	HistorySize           int
-}
-
-func Parse() Config {
-	var c Config
-
-	pflag.StringVar(&c.GrpcAddr, "grpc-addr", "127.0.0.1:8082", "")
-	pflag.StringVar(&c.HttpAddr, "http-addr", "127.0.0.1:8080", "")
-	pflag.StringVar(&c.DebugAddr, "debug-addr", "127.0.0.1:8083", "")
-	pflag.StringVar(&c.SwaggerAddr, "swagger-addr", "127.0.0.1:8081", "")
-	pflag.IntVar(&c.HistorySize, "history-size", 100, "")
-	pflag.IntVar(&c.BlacklistBucketsCount, "blacklist-buckets-count", 512, "")
-	pflag.Parse()
+	GrpcAddr              string        `yaml:"grpc_addr"`
+	HttpAddr              string        `yaml:"http_addr"`
+	SwaggerAddr           string        `yaml:"swagger_addr"`
+	DebugAddr             string        `yaml:"debug_addr"`
+	BlacklistBucketsCount int           `yaml:"blacklist_buckets_count"`
+	HistorySize           int           `yaml:"history_size"`
+	DNS                   DNS           `yaml:"dns"`
+	BlockTTL              time.Duration `yaml:"block_ttl"`
+	TTL                   TTLConfig     `yaml:"ttl"`
+	RateLimit             RateLimit     `yaml:"rate_limit"`
+	ACL                   ACL           `yaml:"acl"`
+	Upstreams             []Upstream    `yaml:"upstreams"`
+	Resolver              Resolver      `yaml:"resolver"`
+	// UpstreamGroups - дополнительные группы серверов, на которые
+	// ссылаются правила Forwarding.
+	UpstreamGroups map[string]UpstreamGroup `yaml:"upstream_groups"`
+	Forwarding     []ForwardingRule         `yaml:"forwarding"`
+	// Groups - политики фильтрации групп клиентов, Clients - клиенты
+	// и их группы. Группа default действует для всех остальных.
+	Groups  map[string]ClientGroup `yaml:"groups"`
+	Clients []Client               `yaml:"clients"`
+	// MACForwarders - адреса и подсети пересылающих серверов (dnsmasq
+	// с --add-mac), которым доверяется MAC-адрес клиента в запросе.
+	// От остальных клиентов MAC-адрес не принимается.
+	MACForwarders []string `yaml:"mac_forwarders"`
+	// LocalRecords - файлы с локальными записями в дополнение к записям
+	// из API.
+	LocalRecords LocalRecords `yaml:"local_records"`
+	// ServicesCatalog - файл каталога сервисов, используется вместо
+	// встроенного, если его версия новее.
+	ServicesCatalog string `yaml:"services_catalog"`
+	// AllowedClients - устаревший ключ, адреса и подсети из него
+	// добавляются в ACL.Allow.
+	AllowedClients []string `yaml:"allowed_clients"`
+}
+
+// LocalRecords - файлы hosts и файлы зон BIND. Файлы перечитываются при
+// изменении, записи API сильнее записей из файлов.
+type LocalRecords struct {
+	Hosts []string   `yaml:"hosts"`
+	Zones []ZoneFile `yaml:"zones"`
+}
+
+// ZoneFile - файл зоны, Origin нужен для относительных имен, если
+// в файле нет $ORIGIN.
+type ZoneFile struct {
+	File   string `yaml:"file"`
+	Origin string `yaml:"origin"`
+}
+
+// ClientGroup - политика фильтрации группы клиентов.
+type ClientGroup struct {
+	// Sources - URL списков блокировки, manual (домены, заблокированные
+	// через API) и regex (правила по регулярным выражениям). Пустой
+	// список включает все источники.
+	Sources []string `yaml:"sources"`
+	// Block и Allow - домены группы, блокируемые и разрешенные вместе
+	// с поддоменами независимо от источников.
+	Block []string `yaml:"block"`
+	Allow []string `yaml:"allow"`
+	// BlockMode - localhost, null, nxdomain или refused.
+	BlockMode string `yaml:"block_mode"`
+	// Unfiltered отключает блокировку по источникам, ручные правила
+	// группы и сервисы каталога продолжают действовать.
+	Unfiltered bool `yaml:"unfiltered"`
+	// Upstreams - группа из upstream_groups для запросов клиентов группы.
+	Upstreams string `yaml:"upstreams"`
+}
+
+// Client относит клиента к группе. IDs - адреса, подсети CIDR, MAC-адреса
+// (EDNS-опция 65001, например, dnsmasq --add-mac) и идентификаторы
+// зашифрованных транспортов с префиксом id:.
+type Client struct {
+	Name  string   `yaml:"name"`
+	Group string   `yaml:"group"`
+	IDs   []string `yaml:"ids"`
+}
+
+type UpstreamGroup struct {
+	Strategy  string     `yaml:"strategy"`
+	Parallel  int        `yaml:"parallel"`
+	Upstreams []Upstream `yaml:"upstreams"`
+}
+
+// ForwardingRule пересылает запросы к зоне и ее поддоменам в группу серверов.
+type ForwardingRule struct {
+	Zone  string `yaml:"zone"`
+	Group string `yaml:"group"`
+}
+
+// Resolver задает стратегию выбора вышестоящих серверов и проверку их
+// доступности.
+type Resolver struct {
+	// Mode - forward (пересылка вышестоящим серверам) или recursive
+	// (самостоятельное разрешение от корневых серверов). Рекурсивный
+	// режим несовместим с правилами forwarding и upstreams групп.
+	Mode        string      `yaml:"mode"`
+	Recursive   Recursive   `yaml:"recursive"`
+	DNSSEC      DNSSEC      `yaml:"dnssec"`
+	Strategy    string      `yaml:"strategy"`
+	Parallel    int         `yaml:"parallel"`
+	HealthCheck HealthCheck `yaml:"health_check"`
+	Bootstrap   []string    `yaml:"bootstrap"`
+}
+
+// Recursive настраивает рекурсивный режим. Пустой список Roots означает
+// встроенные адреса корневых серверов.
+type Recursive struct {
+	Roots             []string      `yaml:"roots"`
+	Port              string        `yaml:"port"`
+	Timeout           time.Duration `yaml:"timeout"`
+	QNameMinimisation bool          `yaml:"qname_minimisation"`
+}
+
+// DNSSEC включает проверку подписей. Пустой список TrustAnchors означает
+// ключи корневой зоны.
+type DNSSEC struct {
+	Enabled      bool     `yaml:"enabled"`
+	TrustAnchors []string `yaml:"trust_anchors"`
+}
+
+type HealthCheck struct {
+	Interval time.Duration `yaml:"interval"`
+	Domain   string        `yaml:"domain"`
+	Failures int           `yaml:"failures"`
+}
+
+// DNS - адреса, на которых принимаются DNS-запросы. Пустой список
+// отключает транспорт. Сокеты, переданные через socket activation,
+// заменяют списки UDP и TCP.
+type DNS struct {
+	UDP []string `yaml:"udp"`
+	TCP []string `yaml:"tcp"`
+	// TLS (DNS-over-TLS), HTTPS (DNS-over-HTTPS) и QUIC по умолчанию выключены
+	// и требуют сертификата. Файлы сертификата перечитываются при изменении.
+	TLS      []string `yaml:"tls"`
+	HTTPS    []string `yaml:"https"`
+	CertFile string   `yaml:"cert_file"`
+	KeyFile  string   `yaml:"key_file"`
+	DoHPath  string   `yaml:"doh_path"`
+	// QUIC - адреса DNS-over-QUIC с тем же сертификатом. 0-RTT выключен,
+	// пока не разрешен явно: такие запросы могут быть повторены.
+	QUIC      []string `yaml:"quic"`
+	Allow0RTT bool     `yaml:"allow_0rtt"`
+}
+
+// RateLimit ограничивает частоту запросов одного клиента (адреса или
+// подсети размером IPv4Prefix/IPv6Prefix). Нулевой Rate отключает
+// ограничение. Slip - какой по счету ограниченный UDP-запрос получит
+// пустой ответ с флагом TC, 0 - отбрасывать все.
+type RateLimit struct {
+	Rate       float64 `yaml:"rate"`
+	Burst      int     `yaml:"burst"`
+	IPv4Prefix int     `yaml:"ipv4_prefix"`
+	IPv6Prefix int     `yaml:"ipv6_prefix"`
+	Slip       int     `yaml:"slip"`
+}
+
+// ACL ограничивает круг клиентов сервера. Allow и Deny - адреса, подсети
+// или идентификаторы клиентов зашифрованных транспортов с префиксом id:.
+// Запрет сильнее разрешения, непустой Allow разрешает только перечисленных.
+// Action - что делать с остальными: refuse (ответить REFUSED) или drop.
+type ACL struct {
+	Allow  []string `yaml:"allow"`
+	Deny   []string `yaml:"deny"`
+	Action string   `yaml:"action"`
+	// ClientIDDomain - домен, поддомены которого в SNI DoT и DoQ считаются
+	// идентификаторами клиентов.
+	ClientIDDomain string `yaml:"client_id_domain"`
+}
+
+// TTLConfig задает границы TTL для положительных, отрицательных
+// и заблокированных ответов. Нулевое значение отключает границу.
+type TTLConfig struct {
+	PositiveMin time.Duration `yaml:"positive_min"`
+	PositiveMax time.Duration `yaml:"positive_max"`
+	NegativeMin time.Duration `yaml:"negative_min"`
+	NegativeMax time.Duration `yaml:"negative_max"`
+	BlockedMin  time.Duration `yaml:"blocked_min"`
+	BlockedMax  time.Duration `yaml:"blocked_max"`
+}
+
+// Upstream описывает вышестоящий сервер. Address может содержать протокол
+// и параметры в виде udp://8.8.8.8:53?timeout=2s&weight=10 или
+// https://dns.google/dns-query, явно заданные поля имеют приоритет.
+type Upstream struct {
+	Address    string        `yaml:"address"`
+	Protocol   string        `yaml:"protocol"`
+	Timeout    time.Duration `yaml:"timeout"`
+	Weight     int           `yaml:"weight"`
+	ServerName string        `yaml:"server_name"`
+	Pins       []string      `yaml:"pins"`
+	CAFile     string        `yaml:"ca_file"`
+	Method     string        `yaml:"method"`
+	UDPSize    uint16        `yaml:"udp_size"`
+}
+
+var defaultUpstreams = []string{
+	// google
+	"8.8.8.8:53",
+	"8.8.4.4:53",
+	// cloudflare
+	"1.1.1.1:53",
+	"1.0.0.1:53",
+	// control d
+	"76.76.2.0:53",
+	"76.76.10.0:53",
+	// quad9
+	"9.9.9.9:53",
+	"149.112.112.112:53",
+	// open dns home
+	"208.67.222.222:53",
+	"208.67.220.220:53",
+}
+
+func defaults() Config {
+	c := Config{
+		GrpcAddr:              "127.0.0.1:8082",
+		HttpAddr:              "127.0.0.1:8080",
+		DebugAddr:             "127.0.0.1:8083",
+		SwaggerAddr:           "127.0.0.1:8081",
+		HistorySize:           100,
+		BlacklistBucketsCount: 512,
+		BlockTTL:              10 * time.Second,
+		RateLimit: RateLimit{
+			IPv4Prefix: 32,
+			IPv6Prefix: 56,
+			Slip:       2,
+		},
+		ACL: ACL{
+			Action: "refuse",
+		},
+		DNS: DNS{
+			UDP:     []string{"0.0.0.0:53"},
+			TCP:     []string{"0.0.0.0:53"},
+			DoHPath: "/dns-query",
+		},
+		Resolver: Resolver{
+			Mode: "forward",
+			Recursive: Recursive{
+				Port:              "53",
+				Timeout:           2 * time.Second,
+				QNameMinimisation: true,
+			},
+			Strategy: "fastest",
+			Parallel: 2,
+			HealthCheck: HealthCheck{
+				Interval: 30 * time.Second,
+				Domain:   ".",
+				Failures: 3,
+			},
+			Bootstrap: []string{"8.8.8.8:53", "1.1.1.1:53"},
+		},
+	}
+	for _, addr := range defaultUpstreams {
+		c.Upstreams = append(c.Upstreams, Upstream{Address: addr})
+	}
 	return c
 }
+
+// Parse собирает конфигурацию: значения по умолчанию перекрываются файлом
+// конфигурации (--config), а он, в свою очередь, флагами командной строки.
+func Parse() (Config, error) {
+	c := defaults()
+
+	if path := configPath(os.Args[1:]); path != "" {
+		if err := load(path, &c); err != nil {
+			return Config{}, err
+		}
+	}
+
+	var (
+		configFile string
+		upstreams  []string
+		zones      []string
+	)
+
+	pflag.StringVar(&configFile, "config", "", "path to a YAML configuration file")
+	pflag.StringVar(&c.GrpcAddr, "grpc-addr", c.GrpcAddr, "")
+	pflag.StringVar(&c.HttpAddr, "http-addr", c.HttpAddr, "")
+	pflag.StringVar(&c.DebugAddr, "debug-addr", c.DebugAddr, "")
+	pflag.StringVar(&c.SwaggerAddr, "swagger-addr", c.SwaggerAddr, "")
+	pflag.IntVar(&c.HistorySize, "history-size", c.HistorySize, "")
+	pflag.IntVar(&c.BlacklistBucketsCount, "blacklist-buckets-count", c.BlacklistBucketsCount, "")
+	pflag.StringSliceVar(&c.DNS.UDP, "dns-udp-addr", c.DNS.UDP, "UDP address to serve DNS on (repeatable, empty disables UDP)")
+	pflag.StringSliceVar(&c.DNS.TCP, "dns-tcp-addr", c.DNS.TCP, "TCP address to serve DNS on (repeatable, empty disables TCP)")
+	pflag.StringSliceVar(&c.DNS.TLS, "dns-tls-addr", c.DNS.TLS, "address to serve DNS-over-TLS on, e.g. 0.0.0.0:853 (repeatable)")
+	pflag.StringSliceVar(&c.DNS.HTTPS, "dns-https-addr", c.DNS.HTTPS, "address to serve DNS-over-HTTPS on, e.g. 0.0.0.0:443 (repeatable)")
+	pflag.StringSliceVar(&c.DNS.QUIC, "dns-quic-addr", c.DNS.QUIC, "address to serve DNS-over-QUIC on, e.g. 0.0.0.0:853 (repeatable)")
+	pflag.BoolVar(&c.DNS.Allow0RTT, "doq-0rtt", c.DNS.Allow0RTT, "accept DNS-over-QUIC queries sent in 0-RTT data (replayable)")
+	pflag.StringVar(&c.DNS.CertFile, "tls-cert", c.DNS.CertFile, "PEM certificate for encrypted DNS listeners, reloaded on change")
+	pflag.StringVar(&c.DNS.KeyFile, "tls-key", c.DNS.KeyFile, "PEM private key for encrypted DNS listeners, reloaded on change")
+	pflag.StringVar(&c.DNS.DoHPath, "doh-path", c.DNS.DoHPath, "URL path of DNS-over-HTTPS requests")
+	pflag.Float64Var(&c.RateLimit.Rate, "rate-limit", c.RateLimit.Rate, "queries per second allowed from a single client, 0 disables rate limiting")
+	pflag.IntVar(&c.RateLimit.Burst, "rate-limit-burst", c.RateLimit.Burst, "queries a client may send at once (defaults to the rate)")
+	pflag.IntVar(&c.RateLimit.IPv4Prefix, "rate-limit-ipv4-prefix", c.RateLimit.IPv4Prefix, "IPv4 prefix length treated as a single client")
+	pflag.IntVar(&c.RateLimit.IPv6Prefix, "rate-limit-ipv6-prefix", c.RateLimit.IPv6Prefix, "IPv6 prefix length treated as a single client")
+	pflag.IntVar(&c.RateLimit.Slip, "rate-limit-slip", c.RateLimit.Slip, "answer every n-th rate limited UDP query with a truncated response, 0 drops them all")
+	pflag.StringSliceVar(&c.ACL.Allow, "acl-allow", c.ACL.Allow, "address, CIDR or id:<client id> allowed to query the server (repeatable, empty allows everyone)")
+	pflag.StringSliceVar(&c.ACL.Deny, "acl-deny", c.ACL.Deny, "address, CIDR or id:<client id> denied to query the server (repeatable)")
+	pflag.StringSliceVar(&c.AllowedClients, "allowed-client", c.AllowedClients, "address or CIDR allowed to query the server (repeatable)")
+	_ = pflag.CommandLine.MarkDeprecated("allowed-client", "use --acl-allow instead")
+	pflag.StringVar(&c.ACL.Action, "acl-action", c.ACL.Action, "what to do with denied queries: refuse or drop")
+	pflag.StringVar(&c.ACL.ClientIDDomain, "client-id-domain", c.ACL.ClientIDDomain, "domain whose subdomains in the TLS SNI identify DoT and DoQ clients")
+	pflag.StringSliceVar(&c.MACForwarders, "mac-forwarder", c.MACForwarders, "address or CIDR of a forwarder trusted to pass client MAC addresses in EDNS (repeatable)")
+	pflag.StringSliceVar(&c.LocalRecords.Hosts, "hosts-file", c.LocalRecords.Hosts, "hosts file with local records (repeatable)")
+	pflag.StringVar(&c.ServicesCatalog, "services-catalog", c.ServicesCatalog, "JSON catalog of blockable services, used instead of the built-in one if its version is newer")
+	pflag.StringSliceVar(&zones, "zone-file", nil, "BIND zone file with local records, optionally prefixed with its origin: example.com=/etc/zones/example.com (repeatable, added to configured zones)")
+	pflag.DurationVar(&c.BlockTTL, "block-ttl", c.BlockTTL, "TTL of blocked answers")
+	pflag.DurationVar(&c.TTL.PositiveMin, "ttl-positive-min", c.TTL.PositiveMin, "minimum TTL of positive answers")
+	pflag.DurationVar(&c.TTL.PositiveMax, "ttl-positive-max", c.TTL.PositiveMax, "maximum TTL of positive answers")
+	pflag.DurationVar(&c.TTL.NegativeMin, "ttl-negative-min", c.TTL.NegativeMin, "minimum TTL of negative (NXDOMAIN/NODATA) answers")
+	pflag.DurationVar(&c.TTL.NegativeMax, "ttl-negative-max", c.TTL.NegativeMax, "maximum TTL of negative (NXDOMAIN/NODATA) answers")
+	pflag.DurationVar(&c.TTL.BlockedMin, "ttl-blocked-min", c.TTL.BlockedMin, "minimum TTL of blocked answers")
+	pflag.DurationVar(&c.TTL.BlockedMax, "ttl-blocked-max", c.TTL.BlockedMax, "maximum TTL of blocked answers")
+	pflag.StringSliceVar(&upstreams, "upstream", nil, "upstream DNS server, e.g. udp://8.8.8.8:53?timeout=2s&weight=10, tls://dns.google or https://dns.google/dns-query (repeatable, replaces configured upstreams)")
+	pflag.StringVar(&c.Resolver.Mode, "resolver-mode", c.Resolver.Mode, "resolution mode: forward (to upstreams) or recursive (from the root servers)")
+	pflag.StringSliceVar(&c.Resolver.Recursive.Roots, "root-hints", c.Resolver.Recursive.Roots, "root server addresses used in recursive mode (defaults to the built-in hints)")
+	pflag.DurationVar(&c.Resolver.Recursive.Timeout, "recursive-timeout", c.Resolver.Recursive.Timeout, "timeout of a single query to an authoritative server in recursive mode")
+	pflag.BoolVar(&c.Resolver.Recursive.QNameMinimisation, "qname-minimisation", c.Resolver.Recursive.QNameMinimisation, "send authoritative servers only the labels they need (RFC 9156)")
+	pflag.BoolVar(&c.Resolver.DNSSEC.Enabled, "dnssec", c.Resolver.DNSSEC.Enabled, "validate DNSSEC signatures, answering SERVFAIL to bogus data")
+	pflag.StringArrayVar(&c.Resolver.DNSSEC.TrustAnchors, "trust-anchor", c.Resolver.DNSSEC.TrustAnchors, "DS or DNSKEY record trusted by the validator (repeatable, defaults to the root zone keys)")
+	pflag.StringVar(&c.Resolver.Strategy, "upstream-strategy", c.Resolver.Strategy, "upstream selection strategy: fastest, round-robin, parallel or failover")
+	pflag.IntVar(&c.Resolver.Parallel, "upstream-parallel", c.Resolver.Parallel, "number of upstreams queried at once by the parallel strategy")
+	pflag.DurationVar(&c.Resolver.HealthCheck.Interval, "health-check-interval", c.Resolver.HealthCheck.Interval, "interval between upstream health checks, 0 disables them")
+	pflag.StringVar(&c.Resolver.HealthCheck.Domain, "health-check-domain", c.Resolver.HealthCheck.Domain, "domain queried (NS) by upstream health checks")
+	pflag.IntVar(&c.Resolver.HealthCheck.Failures, "health-check-failures", c.Resolver.HealthCheck.Failures, "consecutive failed checks before an upstream is marked down")
+	pflag.StringSliceVar(&c.Resolver.Bootstrap, "bootstrap", c.Resolver.Bootstrap, "plain DNS servers used to resolve upstream host names")
+	pflag.Parse()
+
+	switch c.Resolver.Mode {
+	case "forward", "recursive":
+	default:
+		return Config{}, fmt.Errorf("configuration: unknown resolver mode %q", c.Resolver.Mode)
+	}
+	if err := c.validateRecursive(); err != nil {
+		return Config{}, err
+	}
+
+	c.ACL.Allow = append(c.ACL.Allow, c.AllowedClients...)
+
+	switch c.ACL.Action {
+	case "refuse", "drop":
+	default:
+		return Config{}, fmt.Errorf("configuration: unknown acl action %q", c.ACL.Action)
+	}
+
+	if len(upstreams) > 0 {
+		c.Upstreams = c.Upstreams[:0]
+		for _, addr := range upstreams {
+			c.Upstreams = append(c.Upstreams, Upstream{Address: addr})
+		}
+	}
+
+	for _, zone := range zones {
+		origin, file, ok := strings.Cut(zone, "=")
+		if !ok {
+			origin, file = "", zone
+		}
+		c.LocalRecords.Zones = append(c.LocalRecords.Zones, ZoneFile{File: file, Origin: origin})
+	}
+
+	return c, nil
+}
+
+// validateRecursive запрещает в рекурсивном режиме пересылку
+// в группы серверов: она бы молча не выполнялась.
+func (c Config) validateRecursive() error {
+	if c.Resolver.Mode != "recursive" {
+		return nil
+	}
+	if len(c.Forwarding) > 0 {
+		return fmt.Errorf("configuration: forwarding rules are not supported in recursive mode")
+	}
+	for name, g := range c.Groups {
+		if g.Upstreams != "" {
+			return fmt.Errorf("configuration: upstreams of group %q are not supported in recursive mode", name)
+		}
+	}
+	return nil
+}
+
+// configPath достает путь к файлу конфигурации до основного разбора флагов,
+// чтобы значения из файла стали значениями флагов по умолчанию.
+func configPath(args []string) string {
+	fs := pflag.NewFlagSet("config", pflag.ContinueOnError)
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.Usage = func() {}
+	fs.SetOutput(io.Discard)
+
+	path := fs.String("config", "", "")
+	_ = fs.Parse(args)
+
+	return *path
+}
+
+func load(path string, c *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("configuration: unable to read %s: %v", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("configuration: unable to parse %s: %v", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRecursive(t *testing.T) {
+	c := Config{
+		Resolver:   Resolver{Mode: "forward"},
+		Forwarding: []ForwardingRule{{Zone: "corp.test", Group: "corp"}},
+		Groups:     map[string]ClientGroup{"kids": {Upstreams: "family"}},
+	}
+	require.NoError(t, c.validateRecursive())
+
+	c.Resolver.Mode = "recursive"
+	require.ErrorContains(t, c.validateRecursive(), "forwarding rules")
+
+	c.Forwarding = nil
+	require.ErrorContains(t, c.validateRecursive(), `group "kids"`)
+
+	c.Groups = map[string]ClientGroup{"kids": {}}
+	require.NoError(t, c.validateRecursive())
+}
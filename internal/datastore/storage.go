@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,19 +14,54 @@ import (
 
 type Storage interface {
 	Migrate(ctx context.Context) error
-	ForEachDomain(ctx context.Context, f func(d string)) error
+	// SourcesOutdated сообщает, удалила ли миграция домены без источника:
+	// их нужно заново загрузить из источников.
+	SourcesOutdated() bool
+	ForEachDomain(ctx context.Context, f func(domain, origin string)) error
 	ForEachSource(ctx context.Context, f func(d string)) error
 	AddSource(ctx context.Context, url string) error
-	AddDomains(ctx context.Context, domains []string) error
+	AddDomains(ctx context.Context, origin string, domains []string) error
 	AddHistoryRecords(ctx context.Context, records []HistoryRecord) error
 	Cleanup(ctx context.Context, skip int) error
 	RunPeriodicCleanup(ctx context.Context)
+	AccessRules(ctx context.Context) ([]AccessRule, error)
+	AddAccessRule(ctx context.Context, rule AccessRule) error
+	DeleteAccessRule(ctx context.Context, rule AccessRule) error
+	Schedules(ctx context.Context) ([]Schedule, error)
+	SetSchedule(ctx context.Context, schedule Schedule) error
+	DeleteSchedule(ctx context.Context, name string) error
+	ScheduleAttachments(ctx context.Context) ([]ScheduleAttachment, error)
+	AttachSchedule(ctx context.Context, attachment ScheduleAttachment) error
+	DetachSchedule(ctx context.Context, kind, target string) error
+	Pauses(ctx context.Context) ([]Pause, error)
+	SetPause(ctx context.Context, pause Pause) error
+	DeletePause(ctx context.Context, scope, target string) error
+	ExpiringRules(ctx context.Context) ([]ExpiringRule, error)
+	SetExpiringRule(ctx context.Context, rule ExpiringRule) error
+	DeleteExpiringRule(ctx context.Context, domain string) error
+	LocalRecords(ctx context.Context) ([]LocalRecord, error)
+	AddLocalRecord(ctx context.Context, record LocalRecord) (int64, error)
+	UpdateLocalRecord(ctx context.Context, record LocalRecord) error
+	DeleteLocalRecord(ctx context.Context, id int64) error
+	SafeSearch(ctx context.Context) ([]SafeSearch, error)
+	SetSafeSearch(ctx context.Context, setting SafeSearch) error
+	DeleteSafeSearch(ctx context.Context, group string) error
+	BlockedServices(ctx context.Context) ([]BlockedService, error)
+	SetBlockedService(ctx context.Context, setting BlockedService) error
+	DeleteBlockedService(ctx context.Context, service, group string) error
+	RegexRules(ctx context.Context) ([]RegexRule, error)
+	AddRegexRule(ctx context.Context, rule RegexRule) (int64, error)
+	DeleteRegexRule(ctx context.Context, id int64) error
+	DeleteDomains(ctx context.Context, origin string, domains []string) error
+	ListDomains(ctx context.Context, query DomainsQuery) ([]Domain, error)
 }
 
 type storage struct {
 	db          *sql.DB
 	historySize int
 	log         *zap.Logger
+
+	sourcesOutdated bool
 }
 
 func Open(path string) (*sql.DB, error) {
@@ -46,9 +82,6 @@ CREATE TABLE IF NOT EXISTS domains (
   domain TEXT
 );
 
-CREATE UNIQUE INDEX IF NOT EXISTS domains_domain_ux
-ON domains (domain);
-
 CREATE TABLE IF NOT EXISTS sources (
   id INTEGER PRIMARY KEY AUTOINCREMENT,
   url TEXT
@@ -65,15 +98,159 @@ CREATE TABLE IF NOT EXISTS history (
     client_addr TEXT,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
+
+CREATE TABLE IF NOT EXISTS access_rules (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  action TEXT NOT NULL,
+  subnet TEXT NOT NULL DEFAULT '',
+  client_id TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS access_rules_ux
+ON access_rules (action, subnet, client_id);
+
+CREATE TABLE IF NOT EXISTS schedules (
+  name TEXT PRIMARY KEY,
+  timezone TEXT NOT NULL DEFAULT '',
+  ranges TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS schedule_attachments (
+  kind TEXT NOT NULL,
+  target TEXT NOT NULL,
+  schedule TEXT NOT NULL,
+  PRIMARY KEY (kind, target)
+);
+
+CREATE TABLE IF NOT EXISTS pauses (
+  scope TEXT NOT NULL,
+  target TEXT NOT NULL DEFAULT '',
+  resume_at INTEGER NOT NULL,
+  PRIMARY KEY (scope, target)
+);
+
+CREATE TABLE IF NOT EXISTS expiring_rules (
+  domain TEXT PRIMARY KEY,
+  action TEXT NOT NULL,
+  origins TEXT NOT NULL DEFAULT '',
+  expires_at INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS local_records (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL,
+  type TEXT NOT NULL,
+  value TEXT NOT NULL,
+  ttl INTEGER NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS local_records_ux
+ON local_records (name, type, value);
+
+CREATE TABLE IF NOT EXISTS safe_search (
+  client_group TEXT PRIMARY KEY,
+  engines TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS blocked_services (
+  service TEXT NOT NULL,
+  client_group TEXT NOT NULL DEFAULT '',
+  blocked INTEGER NOT NULL,
+  PRIMARY KEY (service, client_group)
+);
+
+CREATE TABLE IF NOT EXISTS regex_rules (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  pattern TEXT NOT NULL UNIQUE,
+  comment TEXT NOT NULL DEFAULT ''
+);
+`
+
+// columns - колонки, добавленные в уже существующие таблицы.
+// SQLite не поддерживает ADD COLUMN IF NOT EXISTS, поэтому наличие
+// колонки проверяется отдельно.
+var columns = []struct {
+	table      string
+	name       string
+	definition string
+}{
+	{table: "history", name: "upstream_group", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "history", name: "validation", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "domains", name: "origin", definition: "TEXT NOT NULL DEFAULT ''"},
+}
+
+// indexes создаются после добавления колонок. Домен может встречаться
+// в нескольких источниках, поэтому уникальность по одному домену
+// заменена уникальностью пары домен-источник.
+const indexes = `
+DROP INDEX IF EXISTS domains_domain_ux;
+
+CREATE UNIQUE INDEX IF NOT EXISTS domains_domain_origin_ux
+ON domains (domain, origin);
+
+CREATE INDEX IF NOT EXISTS domains_origin_ix
+ON domains (origin, domain);
 `
 
 func (s *storage) Migrate(ctx context.Context) error {
 	if _, err := s.db.ExecContext(ctx, migration); err != nil {
 		return fmt.Errorf("storage: unable to perform migration: %v", err)
 	}
+
+	for _, c := range columns {
+		if err := s.addColumn(ctx, c.table, c.name, c.definition); err != nil {
+			return fmt.Errorf("storage: unable to perform migration: %v", err)
+		}
+	}
+
+	// домены, сохраненные до появления колонки origin, пришли из
+	// источников, но из какого - неизвестно. Без источника они не попадают
+	// под фильтр групп и дублируют строки после обновления, поэтому
+	// удаляются и загружаются из источников заново.
+	res, err := s.db.ExecContext(ctx, `DELETE FROM domains WHERE origin = '';`)
+	if err != nil {
+		return fmt.Errorf("storage: unable to perform migration: %v", err)
+	}
+	if removed, err := res.RowsAffected(); err == nil && removed > 0 {
+		s.log.Info("storage: removed domains without an origin", zap.Int64("count", removed))
+		s.sourcesOutdated = true
+	}
+
+	if _, err := s.db.ExecContext(ctx, indexes); err != nil {
+		return fmt.Errorf("storage: unable to perform migration: %v", err)
+	}
+
 	return nil
 }
 
+func (s *storage) SourcesOutdated() bool {
+	return s.sourcesOutdated
+}
+
+func (s *storage) addColumn(ctx context.Context, table, name, definition string) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return err
+		}
+		if column == name {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `ALTER TABLE `+table+` ADD COLUMN `+name+` `+definition)
+	return err
+}
+
 const idForRemovalQuery = `
 SELECT id
 FROM history
@@ -112,10 +289,12 @@ func (s *storage) Cleanup(ctx context.Context, skip int) error {
 }
 
 type HistoryRecord struct {
-	Type       string
-	Domain     string
-	Status     string
-	ClientAddr string
+	Type          string
+	Domain        string
+	Status        string
+	ClientAddr    string
+	UpstreamGroup string
+	Validation    string
 }
 
 func (s *storage) AddHistoryRecords(ctx context.Context, records []HistoryRecord) error {
@@ -123,13 +302,13 @@ func (s *storage) AddHistoryRecords(ctx context.Context, records []HistoryRecord
 		return nil
 	}
 
-	q := `INSERT INTO history (domain, type, status, client_addr) VALUES `
-	q += strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?),", len(records)), ",")
+	q := `INSERT INTO history (domain, type, status, client_addr, upstream_group, validation) VALUES `
+	q += strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?),", len(records)), ",")
 	q += ` ON CONFLICT DO NOTHING;`
 
-	args := make([]any, 0, len(records)*4)
+	args := make([]any, 0, len(records)*6)
 	for _, record := range records {
-		args = append(args, record.Domain, record.Type, record.Status, record.ClientAddr)
+		args = append(args, record.Domain, record.Type, record.Status, record.ClientAddr, record.UpstreamGroup, record.Validation)
 	}
 
 	if _, err := s.db.ExecContext(ctx, q, args...); err != nil {
@@ -139,18 +318,18 @@ func (s *storage) AddHistoryRecords(ctx context.Context, records []HistoryRecord
 	return nil
 }
 
-func (s *storage) AddDomains(ctx context.Context, domains []string) error {
+func (s *storage) AddDomains(ctx context.Context, origin string, domains []string) error {
 	if len(domains) == 0 {
 		return nil
 	}
 
-	q := `INSERT INTO domains (domain) VALUES `
-	q += strings.TrimSuffix(strings.Repeat("(?),", len(domains)), ",")
+	q := `INSERT INTO domains (domain, origin) VALUES `
+	q += strings.TrimSuffix(strings.Repeat("(?, ?),", len(domains)), ",")
 	q += ` ON CONFLICT DO NOTHING;`
 
-	args := make([]any, len(domains))
-	for i, domain := range domains {
-		args[i] = domain
+	args := make([]any, 0, len(domains)*2)
+	for _, domain := range domains {
+		args = append(args, domain, origin)
 	}
 
 	if _, err := s.db.ExecContext(ctx, q, args...); err != nil {
@@ -159,6 +338,129 @@ func (s *storage) AddDomains(ctx context.Context, domains []string) error {
 	return nil
 }
 
+func (s *storage) DeleteDomains(ctx context.Context, origin string, domains []string) error {
+	if len(domains) == 0 {
+		return nil
+	}
+
+	q := `DELETE FROM domains WHERE origin = ? AND domain IN (`
+	q += strings.TrimSuffix(strings.Repeat("?,", len(domains)), ",")
+	q += `);`
+
+	args := make([]any, 0, len(domains)+1)
+	args = append(args, origin)
+	for _, domain := range domains {
+		args = append(args, domain)
+	}
+
+	if _, err := s.db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("storage: unable to delete domains: %v", err)
+	}
+	return nil
+}
+
+// DomainsQuery - выборка заблокированных доменов, упорядоченных по имени.
+// After - домен, после которого начинается страница, Prefix и Search -
+// начало и часть имени, Origin и SourceID - источник домена.
+type DomainsQuery struct {
+	After    string
+	Prefix   string
+	Search   string
+	Origin   string
+	SourceID int64
+	Limit    int
+}
+
+// DomainOrigin - источник домена: URL списка с его номером или manual
+// (SourceID равен нулю).
+type DomainOrigin struct {
+	Origin   string
+	SourceID int64
+}
+
+// Domain - заблокированный домен и все его источники.
+type Domain struct {
+	Domain  string
+	Origins []DomainOrigin
+}
+
+// ListDomains возвращает страницу доменов. Условия на имя используют
+// индекс по домену, поиск по части имени просматривает таблицу целиком.
+func (s *storage) ListDomains(ctx context.Context, query DomainsQuery) ([]Domain, error) {
+	var conditions []string
+	var args []any
+
+	if query.After != "" {
+		conditions = append(conditions, `d.domain > ?`)
+		args = append(args, query.After)
+	}
+	if query.Prefix != "" {
+		conditions = append(conditions, `d.domain >= ? AND d.domain < ?`)
+		args = append(args, query.Prefix, prefixEnd(query.Prefix))
+	}
+	if query.Search != "" {
+		conditions = append(conditions, `instr(d.domain, ?) > 0`)
+		args = append(args, query.Search)
+	}
+	if query.Origin != "" {
+		conditions = append(conditions, `d.domain IN (SELECT domain FROM domains WHERE origin = ?)`)
+		args = append(args, query.Origin)
+	}
+	if query.SourceID != 0 {
+		conditions = append(conditions, `d.domain IN (SELECT domain FROM domains WHERE origin = (SELECT url FROM sources WHERE id = ?))`)
+		args = append(args, query.SourceID)
+	}
+
+	q := `SELECT d.domain, group_concat(coalesce(s.id, 0) || ' ' || d.origin, char(10))
+FROM domains d
+LEFT JOIN sources s ON s.url = d.origin`
+	if len(conditions) > 0 {
+		q += "\nWHERE " + strings.Join(conditions, "\n  AND ")
+	}
+	q += "\nGROUP BY d.domain\nORDER BY d.domain\nLIMIT ?;"
+	args = append(args, query.Limit)
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch domains: %v", err)
+	}
+	defer rows.Close()
+
+	var domains []Domain
+	for rows.Next() {
+		var domain Domain
+		var origins string
+		if err := rows.Scan(&domain.Domain, &origins); err != nil {
+			return nil, fmt.Errorf("storage: unable to scan domain: %v", err)
+		}
+		for _, o := range strings.Split(origins, "\n") {
+			id, origin, _ := strings.Cut(o, " ")
+			sourceID, _ := strconv.ParseInt(id, 10, 64)
+			domain.Origins = append(domain.Origins, DomainOrigin{Origin: origin, SourceID: sourceID})
+		}
+		domains = append(domains, domain)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch domains: %v", err)
+	}
+
+	return domains, nil
+}
+
+// prefixEnd возвращает первую строку после всех строк с префиксом
+// prefix.
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	// префикс из одних 0xff - верхней границы нет
+	return prefix + "\xff"
+}
+
 const addSourceQuery = `
 INSERT INTO sources (url)
 VALUES (?)
@@ -175,7 +477,8 @@ func (s *storage) AddSource(ctx context.Context, url string) error {
 const forEachDomainChunkQuery = `
 SELECT
 	id, 
-	domain
+	domain,
+	origin
 FROM domains 
 WHERE id > ?
 ORDER BY id
@@ -184,7 +487,7 @@ LIMIT ?
 
 const chunkSize = 100
 
-func (s *storage) ForEachDomain(ctx context.Context, f func(d string)) error {
+func (s *storage) ForEachDomain(ctx context.Context, f func(domain, origin string)) error {
 	var lastID int64
 	var handled int
 
@@ -199,14 +502,14 @@ func (s *storage) ForEachDomain(ctx context.Context, f func(d string)) error {
 			defer rows.Close()
 
 			var id int64
-			var domain string
+			var domain, origin string
 
 			for rows.Next() {
-				if err := rows.Scan(&id, &domain); err != nil {
+				if err := rows.Scan(&id, &domain, &origin); err != nil {
 					return fmt.Errorf("storage (ForEachDomain): unable to scan domain: %v", err)
 				}
 
-				f(domain)
+				f(domain, origin)
 
 				lastID = id
 				handled++
@@ -287,6 +590,622 @@ func (s *storage) ForEachSource(ctx context.Context, f func(d string)) error {
 	return nil
 }
 
+// AccessRule - правило доступа клиентов к DNS-серверу.
+type AccessRule struct {
+	Action   string
+	Subnet   string
+	ClientID string
+}
+
+const accessRulesQuery = `
+SELECT action, subnet, client_id
+FROM access_rules
+ORDER BY id;
+`
+
+func (s *storage) AccessRules(ctx context.Context) ([]AccessRule, error) {
+	rows, err := s.db.QueryContext(ctx, accessRulesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch access rules: %v", err)
+	}
+	defer rows.Close()
+
+	var rules []AccessRule
+	for rows.Next() {
+		var rule AccessRule
+		if err := rows.Scan(&rule.Action, &rule.Subnet, &rule.ClientID); err != nil {
+			return nil, fmt.Errorf("storage: unable to scan access rule: %v", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch access rules: %v", err)
+	}
+
+	return rules, nil
+}
+
+const addAccessRuleQuery = `
+INSERT INTO access_rules (action, subnet, client_id)
+VALUES (?, ?, ?)
+ON CONFLICT DO NOTHING;
+`
+
+func (s *storage) AddAccessRule(ctx context.Context, rule AccessRule) error {
+	if _, err := s.db.ExecContext(ctx, addAccessRuleQuery, rule.Action, rule.Subnet, rule.ClientID); err != nil {
+		return fmt.Errorf("storage: unable to add access rule: %v", err)
+	}
+	return nil
+}
+
+const deleteAccessRuleQuery = `
+DELETE
+FROM access_rules
+WHERE action = ? AND subnet = ? AND client_id = ?;
+`
+
+func (s *storage) DeleteAccessRule(ctx context.Context, rule AccessRule) error {
+	if _, err := s.db.ExecContext(ctx, deleteAccessRuleQuery, rule.Action, rule.Subnet, rule.ClientID); err != nil {
+		return fmt.Errorf("storage: unable to delete access rule: %v", err)
+	}
+	return nil
+}
+
+// Schedule - расписание, Ranges - интервалы вида "mon-fri 09:00-18:00".
+type Schedule struct {
+	Name     string
+	Timezone string
+	Ranges   []string
+}
+
+const schedulesQuery = `
+SELECT name, timezone, ranges
+FROM schedules
+ORDER BY name;
+`
+
+func (s *storage) Schedules(ctx context.Context) ([]Schedule, error) {
+	rows, err := s.db.QueryContext(ctx, schedulesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch schedules: %v", err)
+	}
+	defer rows.Close()
+
+	var schedules []Schedule
+	for rows.Next() {
+		var schedule Schedule
+		var ranges string
+		if err := rows.Scan(&schedule.Name, &schedule.Timezone, &ranges); err != nil {
+			return nil, fmt.Errorf("storage: unable to scan schedule: %v", err)
+		}
+		schedule.Ranges = strings.Split(ranges, "\n")
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch schedules: %v", err)
+	}
+
+	return schedules, nil
+}
+
+const setScheduleQuery = `
+INSERT INTO schedules (name, timezone, ranges)
+VALUES (?, ?, ?)
+ON CONFLICT (name) DO UPDATE SET timezone = excluded.timezone, ranges = excluded.ranges;
+`
+
+func (s *storage) SetSchedule(ctx context.Context, schedule Schedule) error {
+	ranges := strings.Join(schedule.Ranges, "\n")
+	if _, err := s.db.ExecContext(ctx, setScheduleQuery, schedule.Name, schedule.Timezone, ranges); err != nil {
+		return fmt.Errorf("storage: unable to save schedule: %v", err)
+	}
+	return nil
+}
+
+const deleteScheduleAttachmentsQuery = `
+DELETE
+FROM schedule_attachments
+WHERE schedule = ?;
+`
+
+const deleteScheduleQuery = `
+DELETE
+FROM schedules
+WHERE name = ?;
+`
+
+// DeleteSchedule удаляет расписание вместе с его привязками.
+func (s *storage) DeleteSchedule(ctx context.Context, name string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("storage: unable to delete schedule: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, deleteScheduleAttachmentsQuery, name); err != nil {
+		return fmt.Errorf("storage: unable to delete schedule: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteScheduleQuery, name); err != nil {
+		return fmt.Errorf("storage: unable to delete schedule: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("storage: unable to delete schedule: %v", err)
+	}
+	return nil
+}
+
+// ScheduleAttachment привязывает расписание к домену, источнику или
+// группе клиентов.
+type ScheduleAttachment struct {
+	Kind     string
+	Target   string
+	Schedule string
+}
+
+const scheduleAttachmentsQuery = `
+SELECT kind, target, schedule
+FROM schedule_attachments
+ORDER BY kind, target;
+`
+
+func (s *storage) ScheduleAttachments(ctx context.Context) ([]ScheduleAttachment, error) {
+	rows, err := s.db.QueryContext(ctx, scheduleAttachmentsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch schedule attachments: %v", err)
+	}
+	defer rows.Close()
+
+	var attachments []ScheduleAttachment
+	for rows.Next() {
+		var a ScheduleAttachment
+		if err := rows.Scan(&a.Kind, &a.Target, &a.Schedule); err != nil {
+			return nil, fmt.Errorf("storage: unable to scan schedule attachment: %v", err)
+		}
+		attachments = append(attachments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch schedule attachments: %v", err)
+	}
+
+	return attachments, nil
+}
+
+const attachScheduleQuery = `
+INSERT INTO schedule_attachments (kind, target, schedule)
+VALUES (?, ?, ?)
+ON CONFLICT (kind, target) DO UPDATE SET schedule = excluded.schedule;
+`
+
+func (s *storage) AttachSchedule(ctx context.Context, a ScheduleAttachment) error {
+	if _, err := s.db.ExecContext(ctx, attachScheduleQuery, a.Kind, a.Target, a.Schedule); err != nil {
+		return fmt.Errorf("storage: unable to attach schedule: %v", err)
+	}
+	return nil
+}
+
+const detachScheduleQuery = `
+DELETE
+FROM schedule_attachments
+WHERE kind = ? AND target = ?;
+`
+
+func (s *storage) DetachSchedule(ctx context.Context, kind, target string) error {
+	if _, err := s.db.ExecContext(ctx, detachScheduleQuery, kind, target); err != nil {
+		return fmt.Errorf("storage: unable to detach schedule: %v", err)
+	}
+	return nil
+}
+
+// Pause - приостановка защиты, Until - момент ее возобновления.
+type Pause struct {
+	Scope  string
+	Target string
+	Until  time.Time
+}
+
+const pausesQuery = `
+SELECT scope, target, resume_at
+FROM pauses
+ORDER BY scope, target;
+`
+
+func (s *storage) Pauses(ctx context.Context) ([]Pause, error) {
+	rows, err := s.db.QueryContext(ctx, pausesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch pauses: %v", err)
+	}
+	defer rows.Close()
+
+	var pauses []Pause
+	for rows.Next() {
+		var pause Pause
+		var resumeAt int64
+		if err := rows.Scan(&pause.Scope, &pause.Target, &resumeAt); err != nil {
+			return nil, fmt.Errorf("storage: unable to scan pause: %v", err)
+		}
+		pause.Until = time.Unix(resumeAt, 0)
+		pauses = append(pauses, pause)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch pauses: %v", err)
+	}
+
+	return pauses, nil
+}
+
+const setPauseQuery = `
+INSERT INTO pauses (scope, target, resume_at)
+VALUES (?, ?, ?)
+ON CONFLICT (scope, target) DO UPDATE SET resume_at = excluded.resume_at;
+`
+
+func (s *storage) SetPause(ctx context.Context, pause Pause) error {
+	if _, err := s.db.ExecContext(ctx, setPauseQuery, pause.Scope, pause.Target, pause.Until.Unix()); err != nil {
+		return fmt.Errorf("storage: unable to save pause: %v", err)
+	}
+	return nil
+}
+
+const deletePauseQuery = `
+DELETE
+FROM pauses
+WHERE scope = ? AND target = ?;
+`
+
+func (s *storage) DeletePause(ctx context.Context, scope, target string) error {
+	if _, err := s.db.ExecContext(ctx, deletePauseQuery, scope, target); err != nil {
+		return fmt.Errorf("storage: unable to delete pause: %v", err)
+	}
+	return nil
+}
+
+// ExpiringRule - временная блокировка или разблокировка домена.
+// Origins - источники домена до применения правила.
+type ExpiringRule struct {
+	Domain  string
+	Action  string
+	Expires time.Time
+	Origins []string
+}
+
+const expiringRulesQuery = `
+SELECT domain, action, origins, expires_at
+FROM expiring_rules
+ORDER BY expires_at, domain;
+`
+
+func (s *storage) ExpiringRules(ctx context.Context) ([]ExpiringRule, error) {
+	rows, err := s.db.QueryContext(ctx, expiringRulesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch expiring rules: %v", err)
+	}
+	defer rows.Close()
+
+	var rules []ExpiringRule
+	for rows.Next() {
+		var rule ExpiringRule
+		var origins string
+		var expiresAt int64
+		if err := rows.Scan(&rule.Domain, &rule.Action, &origins, &expiresAt); err != nil {
+			return nil, fmt.Errorf("storage: unable to scan expiring rule: %v", err)
+		}
+		if origins != "" {
+			rule.Origins = strings.Split(origins, "\n")
+		}
+		rule.Expires = time.Unix(expiresAt, 0)
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch expiring rules: %v", err)
+	}
+
+	return rules, nil
+}
+
+const setExpiringRuleQuery = `
+INSERT INTO expiring_rules (domain, action, origins, expires_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (domain) DO UPDATE SET
+  action = excluded.action,
+  origins = excluded.origins,
+  expires_at = excluded.expires_at;
+`
+
+func (s *storage) SetExpiringRule(ctx context.Context, rule ExpiringRule) error {
+	origins := strings.Join(rule.Origins, "\n")
+	_, err := s.db.ExecContext(ctx, setExpiringRuleQuery, rule.Domain, rule.Action, origins, rule.Expires.Unix())
+	if err != nil {
+		return fmt.Errorf("storage: unable to save expiring rule: %v", err)
+	}
+	return nil
+}
+
+const deleteExpiringRuleQuery = `
+DELETE
+FROM expiring_rules
+WHERE domain = ?;
+`
+
+func (s *storage) DeleteExpiringRule(ctx context.Context, domain string) error {
+	if _, err := s.db.ExecContext(ctx, deleteExpiringRuleQuery, domain); err != nil {
+		return fmt.Errorf("storage: unable to delete expiring rule: %v", err)
+	}
+	return nil
+}
+
+// LocalRecord - локальная запись DNS, управляемая через API.
+type LocalRecord struct {
+	ID    int64
+	Name  string
+	Type  string
+	Value string
+	TTL   uint32
+}
+
+const localRecordsQuery = `
+SELECT id, name, type, value, ttl
+FROM local_records
+ORDER BY id;
+`
+
+func (s *storage) LocalRecords(ctx context.Context) ([]LocalRecord, error) {
+	rows, err := s.db.QueryContext(ctx, localRecordsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch local records: %v", err)
+	}
+	defer rows.Close()
+
+	var records []LocalRecord
+	for rows.Next() {
+		var r LocalRecord
+		if err := rows.Scan(&r.ID, &r.Name, &r.Type, &r.Value, &r.TTL); err != nil {
+			return nil, fmt.Errorf("storage: unable to scan local record: %v", err)
+		}
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch local records: %v", err)
+	}
+
+	return records, nil
+}
+
+const addLocalRecordQuery = `
+INSERT INTO local_records (name, type, value, ttl)
+VALUES (?, ?, ?, ?);
+`
+
+func (s *storage) AddLocalRecord(ctx context.Context, r LocalRecord) (int64, error) {
+	res, err := s.db.ExecContext(ctx, addLocalRecordQuery, r.Name, r.Type, r.Value, r.TTL)
+	if err != nil {
+		return 0, fmt.Errorf("storage: unable to add local record: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("storage: unable to add local record: %v", err)
+	}
+	return id, nil
+}
+
+const updateLocalRecordQuery = `
+UPDATE local_records
+SET name = ?, type = ?, value = ?, ttl = ?
+WHERE id = ?;
+`
+
+func (s *storage) UpdateLocalRecord(ctx context.Context, r LocalRecord) error {
+	if _, err := s.db.ExecContext(ctx, updateLocalRecordQuery, r.Name, r.Type, r.Value, r.TTL, r.ID); err != nil {
+		return fmt.Errorf("storage: unable to update local record: %v", err)
+	}
+	return nil
+}
+
+const deleteLocalRecordQuery = `
+DELETE
+FROM local_records
+WHERE id = ?;
+`
+
+func (s *storage) DeleteLocalRecord(ctx context.Context, id int64) error {
+	if _, err := s.db.ExecContext(ctx, deleteLocalRecordQuery, id); err != nil {
+		return fmt.Errorf("storage: unable to delete local record: %v", err)
+	}
+	return nil
+}
+
+// SafeSearch - сервисы с принудительным безопасным режимом для группы
+// клиентов, пустая группа - глобальные настройки.
+type SafeSearch struct {
+	Group   string
+	Engines []string
+}
+
+const safeSearchQuery = `
+SELECT client_group, engines
+FROM safe_search
+ORDER BY client_group;
+`
+
+func (s *storage) SafeSearch(ctx context.Context) ([]SafeSearch, error) {
+	rows, err := s.db.QueryContext(ctx, safeSearchQuery)
+	if err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch safe search settings: %v", err)
+	}
+	defer rows.Close()
+
+	var settings []SafeSearch
+	for rows.Next() {
+		var setting SafeSearch
+		var engines string
+		if err := rows.Scan(&setting.Group, &engines); err != nil {
+			return nil, fmt.Errorf("storage: unable to scan safe search setting: %v", err)
+		}
+		if engines != "" {
+			setting.Engines = strings.Split(engines, "\n")
+		}
+		settings = append(settings, setting)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch safe search settings: %v", err)
+	}
+
+	return settings, nil
+}
+
+const setSafeSearchQuery = `
+INSERT INTO safe_search (client_group, engines)
+VALUES (?, ?)
+ON CONFLICT (client_group) DO UPDATE SET engines = excluded.engines;
+`
+
+func (s *storage) SetSafeSearch(ctx context.Context, setting SafeSearch) error {
+	engines := strings.Join(setting.Engines, "\n")
+	if _, err := s.db.ExecContext(ctx, setSafeSearchQuery, setting.Group, engines); err != nil {
+		return fmt.Errorf("storage: unable to save safe search setting: %v", err)
+	}
+	return nil
+}
+
+const deleteSafeSearchQuery = `
+DELETE
+FROM safe_search
+WHERE client_group = ?;
+`
+
+func (s *storage) DeleteSafeSearch(ctx context.Context, group string) error {
+	if _, err := s.db.ExecContext(ctx, deleteSafeSearchQuery, group); err != nil {
+		return fmt.Errorf("storage: unable to delete safe search setting: %v", err)
+	}
+	return nil
+}
+
+// BlockedService - блокировка сервиса из каталога для группы клиентов,
+// пустая группа - глобальная настройка.
+type BlockedService struct {
+	Service string
+	Group   string
+	Blocked bool
+}
+
+const blockedServicesQuery = `
+SELECT service, client_group, blocked
+FROM blocked_services
+ORDER BY service, client_group;
+`
+
+func (s *storage) BlockedServices(ctx context.Context) ([]BlockedService, error) {
+	rows, err := s.db.QueryContext(ctx, blockedServicesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch blocked services: %v", err)
+	}
+	defer rows.Close()
+
+	var settings []BlockedService
+	for rows.Next() {
+		var setting BlockedService
+		if err := rows.Scan(&setting.Service, &setting.Group, &setting.Blocked); err != nil {
+			return nil, fmt.Errorf("storage: unable to scan blocked service: %v", err)
+		}
+		settings = append(settings, setting)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch blocked services: %v", err)
+	}
+
+	return settings, nil
+}
+
+const setBlockedServiceQuery = `
+INSERT INTO blocked_services (service, client_group, blocked)
+VALUES (?, ?, ?)
+ON CONFLICT (service, client_group) DO UPDATE SET blocked = excluded.blocked;
+`
+
+func (s *storage) SetBlockedService(ctx context.Context, setting BlockedService) error {
+	if _, err := s.db.ExecContext(ctx, setBlockedServiceQuery, setting.Service, setting.Group, setting.Blocked); err != nil {
+		return fmt.Errorf("storage: unable to save blocked service: %v", err)
+	}
+	return nil
+}
+
+const deleteBlockedServiceQuery = `
+DELETE
+FROM blocked_services
+WHERE service = ? AND client_group = ?;
+`
+
+func (s *storage) DeleteBlockedService(ctx context.Context, service, group string) error {
+	if _, err := s.db.ExecContext(ctx, deleteBlockedServiceQuery, service, group); err != nil {
+		return fmt.Errorf("storage: unable to delete blocked service: %v", err)
+	}
+	return nil
+}
+
+// RegexRule - правило блокировки по регулярному выражению.
+type RegexRule struct {
+	ID      int64
+	Pattern string
+	Comment string
+}
+
+const regexRulesQuery = `
+SELECT id, pattern, comment
+FROM regex_rules
+ORDER BY id;
+`
+
+func (s *storage) RegexRules(ctx context.Context) ([]RegexRule, error) {
+	rows, err := s.db.QueryContext(ctx, regexRulesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch regex rules: %v", err)
+	}
+	defer rows.Close()
+
+	var rules []RegexRule
+	for rows.Next() {
+		var rule RegexRule
+		if err := rows.Scan(&rule.ID, &rule.Pattern, &rule.Comment); err != nil {
+			return nil, fmt.Errorf("storage: unable to scan regex rule: %v", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: unable to fetch regex rules: %v", err)
+	}
+
+	return rules, nil
+}
+
+const addRegexRuleQuery = `
+INSERT INTO regex_rules (pattern, comment)
+VALUES (?, ?);
+`
+
+func (s *storage) AddRegexRule(ctx context.Context, rule RegexRule) (int64, error) {
+	res, err := s.db.ExecContext(ctx, addRegexRuleQuery, rule.Pattern, rule.Comment)
+	if err != nil {
+		return 0, fmt.Errorf("storage: unable to add regex rule: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("storage: unable to add regex rule: %v", err)
+	}
+	return id, nil
+}
+
+const deleteRegexRuleQuery = `
+DELETE
+FROM regex_rules
+WHERE id = ?;
+`
+
+func (s *storage) DeleteRegexRule(ctx context.Context, id int64) error {
+	if _, err := s.db.ExecContext(ctx, deleteRegexRuleQuery, id); err != nil {
+		return fmt.Errorf("storage: unable to delete regex rule: %v", err)
+	}
+	return nil
+}
+
 func (s *storage) RunPeriodicCleanup(ctx context.Context) {
 	ticker := time.NewTimer(5 * time.Minute)
 
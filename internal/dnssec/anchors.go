@@ -0,0 +1,76 @@
+package dnssec
+
+import (
+	"fmt"
+
+	"github.com/miekg/dns"
+)
+
+// DefaultTrustAnchors - записи DS ключей KSK корневой зоны
+// (https://data.iana.org/root-anchors/root-anchors.xml).
+var DefaultTrustAnchors = []string{
+	". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D",
+	". IN DS 38696 8 2 683D2D0ACB8C9B712A1948B27F741219298D0A450D612C483AF444A4C0FB2B16",
+}
+
+// anchors - доверенные записи DS или DNSKEY, сгруппированные по зонам.
+type anchors map[string][]dns.RR
+
+func parseAnchors(records []string) (anchors, error) {
+	a := make(anchors)
+
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		if err != nil {
+			return nil, fmt.Errorf("dnssec: invalid trust anchor %q: %v", record, err)
+		}
+		if rr == nil {
+			continue
+		}
+
+		switch rr.(type) {
+		case *dns.DS, *dns.DNSKEY:
+		default:
+			return nil, fmt.Errorf("dnssec: trust anchor must be a DS or DNSKEY record: %q", record)
+		}
+
+		zone := dns.CanonicalName(rr.Header().Name)
+		a[zone] = append(a[zone], rr)
+	}
+
+	if len(a) == 0 {
+		return nil, fmt.Errorf("dnssec: no trust anchors configured")
+	}
+
+	return a, nil
+}
+
+// matches сообщает, подтверждается ли ключ одной из доверенных записей.
+func (a anchors) matches(zone string, key *dns.DNSKEY) bool {
+	for _, rr := range a[zone] {
+		switch anchor := rr.(type) {
+		case *dns.DS:
+			if dsMatches(anchor, key) {
+				return true
+			}
+		case *dns.DNSKEY:
+			if anchor.Algorithm == key.Algorithm && anchor.PublicKey == key.PublicKey {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// closest возвращает ближайшую к имени зону с доверенным ключом.
+func (a anchors) closest(name string) (string, bool) {
+	for off, end := 0, false; !end; off, end = dns.NextLabel(name, off) {
+		if _, ok := a[name[off:]]; ok {
+			return name[off:], true
+		}
+	}
+	if _, ok := a["."]; ok {
+		return ".", true
+	}
+	return "", false
+}
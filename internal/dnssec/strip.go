@@ -0,0 +1,25 @@
+package dnssec
+
+import "github.com/miekg/dns"
+
+// Strip удаляет из ответа DNSSEC-записи, которые клиент не запрашивал
+// (RFC 4035, раздел 3.2.1): без бита DO они только раздувают ответ.
+func Strip(msg *dns.Msg, qtype uint16) {
+	msg.Answer = strip(msg.Answer, qtype)
+	msg.Ns = strip(msg.Ns, qtype)
+	msg.Extra = strip(msg.Extra, qtype)
+}
+
+func strip(section []dns.RR, qtype uint16) []dns.RR {
+	filtered := section[:0]
+	for _, rr := range section {
+		switch t := rr.Header().Rrtype; t {
+		case dns.TypeRRSIG, dns.TypeNSEC, dns.TypeNSEC3:
+			if t != qtype {
+				continue
+			}
+		}
+		filtered = append(filtered, rr)
+	}
+	return filtered
+}
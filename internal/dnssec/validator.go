@@ -0,0 +1,591 @@
+package dnssec
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/denisdubovitskiy/blackhole/internal/resolver"
+	"github.com/miekg/dns"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxZoneTTL ограничивает время жизни проверенных ключей в кеше.
+	maxZoneTTL = time.Hour
+	// bogusZoneTTL - время, на которое запоминается ошибка проверки зоны.
+	bogusZoneTTL = 30 * time.Second
+)
+
+var errNoSignature = errors.New("no valid signature")
+
+type Config struct {
+	// TrustAnchors - доверенные записи DS или DNSKEY в текстовом виде,
+	// по умолчанию ключи корневой зоны.
+	TrustAnchors []string
+	Logger       *zap.Logger
+}
+
+// Validator проверяет подписи DNSSEC в ответах вышестоящих серверов,
+// выстраивая цепочку доверия от ответа до доверенного ключа. Ответы
+// с поддельными подписями заменяются на SERVFAIL, проверенные ответы
+// помечаются битом AD.
+type Validator struct {
+	next    resolver.Lookuper
+	anchors anchors
+	logger  *zap.Logger
+	now     func() time.Time
+
+	mu    sync.Mutex
+	zones map[string]*zoneState
+
+	secure   *atomic.Int32
+	insecure *atomic.Int32
+	bogus    *atomic.Int32
+}
+
+type zoneKind uint8
+
+const (
+	// zoneSecure - вершина подписанной зоны с проверенными ключами.
+	zoneSecure zoneKind = iota
+	// zoneInsecure - доказано, что зона (или ее предок) не подписана.
+	zoneInsecure
+	// zoneNotCut - имя не является вершиной зоны.
+	zoneNotCut
+)
+
+type zoneState struct {
+	kind zoneKind
+	keys []*dns.DNSKEY
+	err  error
+	die  time.Time
+}
+
+func New(next resolver.Lookuper, config Config) (*Validator, error) {
+	if len(config.TrustAnchors) == 0 {
+		config.TrustAnchors = DefaultTrustAnchors
+	}
+	if config.Logger == nil {
+		config.Logger = zap.NewNop()
+	}
+
+	a, err := parseAnchors(config.TrustAnchors)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Validator{
+		next:     next,
+		anchors:  a,
+		logger:   config.Logger,
+		now:      time.Now,
+		zones:    make(map[string]*zoneState),
+		secure:   atomic.NewInt32(0),
+		insecure: atomic.NewInt32(0),
+		bogus:    atomic.NewInt32(0),
+	}
+
+	if expvar.Get("blackhole_dnssec") == nil {
+		expvar.Publish("blackhole_dnssec", expvar.Func(func() any {
+			return v.dumpStats()
+		}))
+	}
+
+	return v, nil
+}
+
+func (v *Validator) Lookup(req *dns.Msg) (*resolver.Answer, error) {
+	answer, err := v.next.Lookup(withDO(req))
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := v.validate(answer.Msg)
+	switch state {
+	case resolver.ValidationBogus:
+		v.bogus.Inc()
+		v.logger.Warn(
+			"dnssec: bogus answer",
+			zap.String("domain", req.Question[0].Name),
+			zap.String("type", dns.TypeToString[req.Question[0].Qtype]),
+			zap.Error(err),
+		)
+
+		resp := &dns.Msg{}
+		resp.SetRcode(req, dns.RcodeServerFailure)
+		resp.RecursionAvailable = true
+
+		return &resolver.Answer{
+			Msg:        resp,
+			Group:      answer.Group,
+			Upstream:   answer.Upstream,
+			Validation: state,
+		}, nil
+	case resolver.ValidationSecure:
+		v.secure.Inc()
+	case resolver.ValidationInsecure:
+		v.insecure.Inc()
+	}
+
+	answer.Msg.AuthenticatedData = state == resolver.ValidationSecure
+	answer.Validation = state
+
+	return answer, nil
+}
+
+// withDO запрашивает у вышестоящего сервера подписи и отключает его
+// собственную проверку: она выполняется здесь.
+func withDO(req *dns.Msg) *dns.Msg {
+	r := req.Copy()
+	r.CheckingDisabled = true
+
+	if opt := r.IsEdns0(); opt != nil {
+		opt.SetDo()
+	} else {
+		r.SetEdns0(dns.DefaultMsgSize, true)
+	}
+
+	return r
+}
+
+// validate определяет состояние ответа. Ответы с ошибками сервера
+// не проверяются.
+func (v *Validator) validate(msg *dns.Msg) (resolver.Validation, error) {
+	if msg.Rcode != dns.RcodeSuccess && msg.Rcode != dns.RcodeNameError {
+		return "", nil
+	}
+
+	q := msg.Question[0]
+	name := dns.CanonicalName(q.Name)
+	result := resolver.ValidationSecure
+
+	answers := groupRRsets(msg.Answer)
+	for _, set := range answers {
+		state, err := v.verifyRRset(set, set.name)
+		if err != nil {
+			return resolver.ValidationBogus, err
+		}
+		if state == resolver.ValidationInsecure {
+			result = state
+			continue
+		}
+		if err := v.verifyWildcard(msg, set); err != nil {
+			return resolver.ValidationBogus, err
+		}
+	}
+
+	target := followCNAMEs(name, answers)
+	if msg.Rcode == dns.RcodeSuccess && (q.Qtype == dns.TypeCNAME || hasRRset(answers, target, q.Qtype)) {
+		return result, nil
+	}
+
+	// отрицательный ответ должен сопровождаться доказательством
+	// отсутствия имени или типа
+	state, err := v.verifyDenial(msg, target, q.Qtype)
+	if err != nil {
+		return resolver.ValidationBogus, err
+	}
+	if state == resolver.ValidationInsecure {
+		result = state
+	}
+
+	return result, nil
+}
+
+func followCNAMEs(name string, sets []*rrset) string {
+	for i := 0; i < len(sets); i++ {
+		for _, set := range sets {
+			if set.rrtype == dns.TypeCNAME && set.name == name {
+				name = dns.CanonicalName(set.rrs[0].(*dns.CNAME).Target)
+			}
+		}
+	}
+	return name
+}
+
+func hasRRset(sets []*rrset, name string, rrtype uint16) bool {
+	for _, set := range sets {
+		if set.name == name && (set.rrtype == rrtype || rrtype == dns.TypeANY) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyRRset проверяет подпись набора записей. Подписавшая зона должна
+// быть предком как самого набора, так и scope; неподписанный набор
+// допустим только если доказано, что scope находится в неподписанной зоне.
+func (v *Validator) verifyRRset(set *rrset, scope string) (resolver.Validation, error) {
+	if len(set.sigs) == 0 {
+		insecure, err := v.provablyInsecure(scope)
+		if err != nil {
+			return resolver.ValidationBogus, err
+		}
+		if insecure {
+			return resolver.ValidationInsecure, nil
+		}
+		return resolver.ValidationBogus, fmt.Errorf("%s %s is not signed", set.name, dns.TypeToString[set.rrtype])
+	}
+
+	var lastErr error = errNoSignature
+	for _, sig := range set.sigs {
+		signer := dns.CanonicalName(sig.SignerName)
+		if !dns.IsSubDomain(signer, set.name) || !dns.IsSubDomain(signer, scope) {
+			continue
+		}
+
+		zone, err := v.zone(signer)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		switch zone.kind {
+		case zoneInsecure:
+			return resolver.ValidationInsecure, nil
+		case zoneNotCut:
+			lastErr = fmt.Errorf("signer %s is not a zone apex", signer)
+			continue
+		}
+
+		if verifySignature(set, sig, zone.keys, v.now()) {
+			return resolver.ValidationSecure, nil
+		}
+	}
+
+	return resolver.ValidationBogus, fmt.Errorf("%s %s: %v", set.name, dns.TypeToString[set.rrtype], lastErr)
+}
+
+// verifyWildcard проверяет набор, полученный раскрытием шаблона: секция
+// authority должна доказывать, что более точного совпадения для имени
+// нет (RFC 4035, раздел 5.3.4; RFC 5155, раздел 8.8). Иначе подписанный
+// шаблон мог бы подменить существующее имя.
+func (v *Validator) verifyWildcard(msg *dns.Msg, set *rrset) error {
+	labels, ok := wildcardLabels(set)
+	if !ok {
+		return nil
+	}
+
+	sets := groupRRsets(msg.Ns)
+	for _, ns := range sets {
+		state, err := v.verifyRRset(ns, set.name)
+		if err != nil {
+			return err
+		}
+		if state != resolver.ValidationSecure {
+			return fmt.Errorf("proof of wildcard expansion for %s is not secure", set.name)
+		}
+	}
+
+	if !newDenial(sets).noCloserMatch(set.name, labels) {
+		return fmt.Errorf("no proof that %s has no closer match than the wildcard", set.name)
+	}
+	return nil
+}
+
+// verifyDenial проверяет подписи секции authority отрицательного ответа
+// и наличие в ней доказательства отсутствия (NSEC/NSEC3).
+func (v *Validator) verifyDenial(msg *dns.Msg, name string, qtype uint16) (resolver.Validation, error) {
+	sets := groupRRsets(msg.Ns)
+
+	signed := false
+	for _, set := range sets {
+		if len(set.sigs) > 0 {
+			signed = true
+		}
+	}
+	if !signed {
+		insecure, err := v.provablyInsecure(name)
+		if err != nil {
+			return resolver.ValidationBogus, err
+		}
+		if insecure {
+			return resolver.ValidationInsecure, nil
+		}
+		return resolver.ValidationBogus, fmt.Errorf("negative answer for %s is not signed", name)
+	}
+
+	for _, set := range sets {
+		state, err := v.verifyRRset(set, name)
+		if err != nil {
+			return resolver.ValidationBogus, err
+		}
+		if state == resolver.ValidationInsecure {
+			return state, nil
+		}
+	}
+
+	d := newDenial(sets)
+	if msg.Rcode == dns.RcodeNameError {
+		if proven, optOut := d.nameError(name); proven {
+			if optOut {
+				return resolver.ValidationInsecure, nil
+			}
+			return resolver.ValidationSecure, nil
+		}
+		return resolver.ValidationBogus, fmt.Errorf("no proof that %s does not exist", name)
+	}
+
+	if d.noData(name, qtype) {
+		return resolver.ValidationSecure, nil
+	}
+	return resolver.ValidationBogus, fmt.Errorf("no proof that %s has no %s records", name, dns.TypeToString[qtype])
+}
+
+// provablyInsecure проходит по предкам имени от доверенного ключа вниз
+// и сообщает, встретилось ли доказанно неподписанное делегирование.
+func (v *Validator) provablyInsecure(name string) (bool, error) {
+	anchor, ok := v.anchors.closest(name)
+	if !ok {
+		return true, nil
+	}
+
+	labels := dns.SplitDomainName(name)
+	for i := len(labels) - dns.CountLabel(anchor) - 1; i >= 0; i-- {
+		zone, err := v.zone(dns.Fqdn(strings.Join(labels[i:], ".")))
+		if err != nil {
+			return false, err
+		}
+		if zone.kind == zoneInsecure {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// zone возвращает закешированное или заново проверенное состояние имени
+// как возможной вершины зоны.
+func (v *Validator) zone(name string) (*zoneState, error) {
+	now := v.now()
+
+	v.mu.Lock()
+	cached, ok := v.zones[name]
+	v.mu.Unlock()
+	if ok && cached.die.After(now) {
+		return cached, cached.err
+	}
+
+	state, ttl, err := v.loadZone(name)
+	if err != nil {
+		state, ttl = &zoneState{err: fmt.Errorf("%s: %v", name, err)}, bogusZoneTTL
+	} else if ttl > maxZoneTTL {
+		ttl = maxZoneTTL
+	}
+	state.die = now.Add(ttl)
+
+	v.mu.Lock()
+	v.zones[name] = state
+	v.mu.Unlock()
+
+	return state, state.err
+}
+
+func (v *Validator) loadZone(name string) (*zoneState, time.Duration, error) {
+	if _, ok := v.anchors[name]; ok {
+		return v.loadKeys(name, func(key *dns.DNSKEY) bool {
+			return v.anchors.matches(name, key)
+		})
+	}
+
+	resp, err := v.fetch(name, dns.TypeDS)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	parent := "."
+	if _, rest := splitFirst(name); rest != "" {
+		parent = rest
+	}
+
+	for _, set := range groupRRsets(resp.Answer) {
+		if set.rrtype != dns.TypeDS || set.name != name {
+			continue
+		}
+
+		// DS подписывается родительской зоной
+		state, err := v.verifyRRset(set, parent)
+		if err != nil {
+			return nil, 0, err
+		}
+		if state == resolver.ValidationInsecure {
+			return &zoneState{kind: zoneInsecure}, ttlOf(set), nil
+		}
+
+		var ds []*dns.DS
+		for _, rr := range set.rrs {
+			if d := rr.(*dns.DS); supportedDS(d) {
+				ds = append(ds, d)
+			}
+		}
+		if len(ds) == 0 {
+			return &zoneState{kind: zoneInsecure}, ttlOf(set), nil
+		}
+
+		return v.loadKeys(name, func(key *dns.DNSKEY) bool {
+			for _, d := range ds {
+				if dsMatches(d, key) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	return v.loadNoDS(resp, name, parent)
+}
+
+// loadNoDS разбирает ответ без записей DS: имя либо неподписанное
+// делегирование, либо не является вершиной зоны.
+func (v *Validator) loadNoDS(resp *dns.Msg, name, parent string) (*zoneState, time.Duration, error) {
+	sets := groupRRsets(resp.Ns)
+	ttl := bogusZoneTTL
+	for _, set := range sets {
+		if t := ttlOf(set); t > ttl {
+			ttl = t
+		}
+	}
+
+	signed := false
+	for _, set := range sets {
+		if len(set.sigs) > 0 {
+			signed = true
+		}
+	}
+	if !signed {
+		insecure, err := v.provablyInsecure(parent)
+		if err != nil {
+			return nil, 0, err
+		}
+		if insecure {
+			return &zoneState{kind: zoneInsecure}, ttl, nil
+		}
+		return nil, 0, fmt.Errorf("absence of DS is not signed")
+	}
+
+	for _, set := range sets {
+		state, err := v.verifyRRset(set, parent)
+		if err != nil {
+			return nil, 0, err
+		}
+		if state == resolver.ValidationInsecure {
+			return &zoneState{kind: zoneInsecure}, ttl, nil
+		}
+	}
+
+	d := newDenial(sets)
+	if bitmap, ok := d.bitmap(name); ok {
+		switch {
+		case hasType(bitmap, dns.TypeDS):
+			return nil, 0, fmt.Errorf("denial of DS lists DS")
+		case hasType(bitmap, dns.TypeNS) && !hasType(bitmap, dns.TypeSOA):
+			return &zoneState{kind: zoneInsecure}, ttl, nil
+		default:
+			return &zoneState{kind: zoneNotCut}, ttl, nil
+		}
+	}
+
+	if proven, optOut := d.nameError(name); proven {
+		if optOut {
+			// opt-out: неподписанные делегирования не имеют записей NSEC3
+			return &zoneState{kind: zoneInsecure}, ttl, nil
+		}
+		return &zoneState{kind: zoneNotCut}, ttl, nil
+	}
+
+	return nil, 0, fmt.Errorf("no proof of DS absence")
+}
+
+// loadKeys загружает DNSKEY зоны и проверяет, что набор подписан
+// ключом, которому доверяет trusted.
+func (v *Validator) loadKeys(zone string, trusted func(key *dns.DNSKEY) bool) (*zoneState, time.Duration, error) {
+	resp, err := v.fetch(zone, dns.TypeDNSKEY)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, set := range groupRRsets(resp.Answer) {
+		if set.rrtype != dns.TypeDNSKEY || set.name != zone {
+			continue
+		}
+
+		var keys, entry []*dns.DNSKEY
+		for _, rr := range set.rrs {
+			key := rr.(*dns.DNSKEY)
+			keys = append(keys, key)
+			if trusted(key) {
+				entry = append(entry, key)
+			}
+		}
+		if len(entry) == 0 {
+			return nil, 0, fmt.Errorf("no DNSKEY matches the trusted DS")
+		}
+
+		for _, sig := range set.sigs {
+			if verifySignature(set, sig, entry, v.now()) {
+				return &zoneState{kind: zoneSecure, keys: keys}, ttlOf(set), nil
+			}
+		}
+
+		return nil, 0, fmt.Errorf("DNSKEY is not signed by a trusted key")
+	}
+
+	return nil, 0, fmt.Errorf("no DNSKEY records")
+}
+
+func (v *Validator) fetch(name string, qtype uint16) (*dns.Msg, error) {
+	req := &dns.Msg{}
+	req.SetQuestion(name, qtype)
+	req.SetEdns0(dns.DefaultMsgSize, true)
+	req.CheckingDisabled = true
+
+	answer, err := v.next.Lookup(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch %s %s: %v", name, dns.TypeToString[qtype], err)
+	}
+
+	switch answer.Msg.Rcode {
+	case dns.RcodeSuccess, dns.RcodeNameError:
+		return answer.Msg, nil
+	default:
+		return nil, fmt.Errorf("unable to fetch %s %s: %s", name, dns.TypeToString[qtype], dns.RcodeToString[answer.Msg.Rcode])
+	}
+}
+
+func ttlOf(set *rrset) time.Duration {
+	return time.Duration(set.minTTL()) * time.Second
+}
+
+// splitFirst отделяет первую метку имени.
+func splitFirst(name string) (string, string) {
+	next, end := dns.NextLabel(name, 0)
+	if end {
+		return name, ""
+	}
+	return name[:next], name[next:]
+}
+
+type stats struct {
+	Secure   int32 `json:"secure"`
+	Insecure int32 `json:"insecure"`
+	Bogus    int32 `json:"bogus"`
+	Zones    int   `json:"zones"`
+}
+
+func (v *Validator) dumpStats() stats {
+	v.mu.Lock()
+	zones := len(v.zones)
+	v.mu.Unlock()
+
+	return stats{
+		Secure:   v.secure.Load(),
+		Insecure: v.insecure.Load(),
+		Bogus:    v.bogus.Load(),
+		Zones:    zones,
+	}
+}
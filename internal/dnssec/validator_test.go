@@ -0,0 +1,260 @@
+package dnssec
+
+import (
+	"crypto"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/denisdubovitskiy/blackhole/internal/resolver"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+type signedZone struct {
+	name string
+	key  *dns.DNSKEY
+	priv crypto.Signer
+}
+
+func newSignedZone(t *testing.T, name string) *signedZone {
+	t.Helper()
+
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: name, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     dns.ZONE | dns.SEP,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	priv, err := key.Generate(256)
+	require.NoError(t, err)
+
+	return &signedZone{name: name, key: key, priv: priv.(crypto.Signer)}
+}
+
+func (z *signedZone) sign(t *testing.T, rrs ...dns.RR) []dns.RR {
+	t.Helper()
+
+	now := time.Now()
+	sig := &dns.RRSIG{
+		Hdr:        dns.RR_Header{Name: rrs[0].Header().Name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: rrs[0].Header().Ttl},
+		KeyTag:     z.key.KeyTag(),
+		SignerName: z.name,
+		Algorithm:  z.key.Algorithm,
+		Inception:  uint32(now.Add(-time.Hour).Unix()),
+		Expiration: uint32(now.Add(time.Hour).Unix()),
+	}
+	require.NoError(t, sig.Sign(z.priv, rrs))
+
+	return append(rrs, sig)
+}
+
+func (z *signedZone) ds() *dns.DS {
+	return z.key.ToDS(dns.SHA256)
+}
+
+func (z *signedZone) dnskey(t *testing.T) []dns.RR {
+	return z.sign(t, z.key)
+}
+
+func rr(s string) dns.RR {
+	r, err := dns.NewRR(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// fakeUpstream отдает заранее подготовленные ответы.
+type fakeUpstream struct {
+	t       *testing.T
+	answers map[string]*dns.Msg
+}
+
+func (f *fakeUpstream) set(name string, qtype uint16, rcode int, answer, ns []dns.RR) {
+	msg := &dns.Msg{}
+	msg.SetQuestion(name, qtype)
+	msg.Response = true
+	msg.Rcode = rcode
+	msg.Answer = answer
+	msg.Ns = ns
+	f.answers[fmt.Sprintf("%s %d", name, qtype)] = msg
+}
+
+func (f *fakeUpstream) Lookup(req *dns.Msg) (*resolver.Answer, error) {
+	require.True(f.t, req.CheckingDisabled)
+	require.True(f.t, req.IsEdns0().Do())
+
+	q := req.Question[0]
+	msg, ok := f.answers[fmt.Sprintf("%s %d", q.Name, q.Qtype)]
+	if !ok {
+		return nil, fmt.Errorf("unexpected query %s %s", q.Name, dns.TypeToString[q.Qtype])
+	}
+
+	resp := msg.Copy()
+	resp.Id = req.Id
+	return &resolver.Answer{Msg: resp, Group: "default", Upstream: "fake"}, nil
+}
+
+type testHierarchy struct {
+	upstream *fakeUpstream
+	root     *signedZone
+}
+
+func newTestHierarchy(t *testing.T) *testHierarchy {
+	f := &fakeUpstream{t: t, answers: make(map[string]*dns.Msg)}
+
+	root := newSignedZone(t, ".")
+	tld := newSignedZone(t, "test.")
+	example := newSignedZone(t, "example.test.")
+
+	f.set(".", dns.TypeDNSKEY, dns.RcodeSuccess, root.dnskey(t), nil)
+	f.set("test.", dns.TypeDNSKEY, dns.RcodeSuccess, tld.dnskey(t), nil)
+	f.set("example.test.", dns.TypeDNSKEY, dns.RcodeSuccess, example.dnskey(t), nil)
+
+	f.set("test.", dns.TypeDS, dns.RcodeSuccess, root.sign(t, tld.ds()), nil)
+	f.set("example.test.", dns.TypeDS, dns.RcodeSuccess, tld.sign(t, example.ds()), nil)
+	// неподписанное делегирование: NSEC с битом NS и без DS
+	f.set("insecure.test.", dns.TypeDS, dns.RcodeSuccess, nil, tld.sign(t,
+		rr("insecure.test. 3600 IN NSEC zzz.test. NS RRSIG NSEC"),
+	))
+
+	f.set("www.example.test.", dns.TypeA, dns.RcodeSuccess, example.sign(t,
+		rr("www.example.test. 300 IN A 192.0.2.1"),
+	), nil)
+	f.set("alias.example.test.", dns.TypeA, dns.RcodeSuccess, append(
+		example.sign(t, rr("alias.example.test. 300 IN CNAME www.example.test.")),
+		example.sign(t, rr("www.example.test. 300 IN A 192.0.2.1"))...,
+	), nil)
+	f.set("www.example.test.", dns.TypeAAAA, dns.RcodeSuccess, nil, append(
+		example.sign(t, rr("example.test. 300 IN SOA ns.example.test. admin.example.test. 1 3600 600 86400 300")),
+		example.sign(t, rr("www.example.test. 300 IN NSEC zzz.example.test. A RRSIG NSEC"))...,
+	))
+	f.set("missing.example.test.", dns.TypeA, dns.RcodeNameError, nil, append(
+		example.sign(t, rr("example.test. 300 IN SOA ns.example.test. admin.example.test. 1 3600 600 86400 300")),
+		example.sign(t, rr("example.test. 300 IN NSEC www.example.test. NS SOA RRSIG NSEC DNSKEY"))...,
+	))
+
+	// ответы, раскрытые из шаблона *.example.test.
+	f.set("any.example.test.", dns.TypeA, dns.RcodeSuccess,
+		expand(example.sign(t, rr("*.example.test. 300 IN A 192.0.2.3")), "any.example.test."),
+		example.sign(t, rr("example.test. 300 IN NSEC www.example.test. NS SOA RRSIG NSEC DNSKEY")),
+	)
+	// шаблон подменяет существующее имя: доказательства отсутствия нет
+	f.set("zzz.example.test.", dns.TypeA, dns.RcodeSuccess,
+		expand(example.sign(t, rr("*.example.test. 300 IN A 192.0.2.3")), "zzz.example.test."),
+		nil,
+	)
+
+	// подпись от другого содержимого
+	tampered := example.sign(t, rr("bad.example.test. 300 IN A 192.0.2.9"))
+	tampered[0].(*dns.A).A = rr("x. IN A 192.0.2.66").(*dns.A).A
+	f.set("bad.example.test.", dns.TypeA, dns.RcodeSuccess, tampered, nil)
+
+	// подписанная зона без подписи у ответа
+	f.set("nosig.example.test.", dns.TypeA, dns.RcodeSuccess, []dns.RR{rr("nosig.example.test. 300 IN A 192.0.2.7")}, nil)
+	f.set("nosig.example.test.", dns.TypeDS, dns.RcodeSuccess, nil, example.sign(t,
+		rr("nosig.example.test. 300 IN NSEC www.example.test. A RRSIG NSEC"),
+	))
+
+	// отрицательный ответ без доказательства
+	f.set("unproven.example.test.", dns.TypeA, dns.RcodeNameError, nil, example.sign(t,
+		rr("example.test. 300 IN SOA ns.example.test. admin.example.test. 1 3600 600 86400 300"),
+	))
+
+	f.set("www.insecure.test.", dns.TypeA, dns.RcodeSuccess, []dns.RR{rr("www.insecure.test. 300 IN A 192.0.2.2")}, nil)
+	f.set("www.insecure.test.", dns.TypeDS, dns.RcodeSuccess, nil, nil)
+
+	return &testHierarchy{upstream: f, root: root}
+}
+
+// expand переименовывает подписанные записи шаблона в name, как это
+// делает сервер при раскрытии шаблона.
+func expand(rrs []dns.RR, name string) []dns.RR {
+	for _, r := range rrs {
+		r.Header().Name = name
+	}
+	return rrs
+}
+
+func (h *testHierarchy) validator(t *testing.T, anchor *dns.DS) *Validator {
+	v, err := New(h.upstream, Config{TrustAnchors: []string{anchor.String()}})
+	require.NoError(t, err)
+	return v
+}
+
+func query(t *testing.T, v *Validator, name string, qtype uint16) *resolver.Answer {
+	t.Helper()
+
+	req := &dns.Msg{}
+	req.SetQuestion(name, qtype)
+
+	answer, err := v.Lookup(req)
+	require.NoError(t, err)
+	require.Equal(t, req.Id, answer.Msg.Id)
+
+	return answer
+}
+
+func TestValidatorStates(t *testing.T) {
+	h := newTestHierarchy(t)
+	v := h.validator(t, h.root.ds())
+
+	tests := []struct {
+		name       string
+		qtype      uint16
+		validation resolver.Validation
+		rcode      int
+	}{
+		{name: "www.example.test.", qtype: dns.TypeA, validation: resolver.ValidationSecure, rcode: dns.RcodeSuccess},
+		{name: "alias.example.test.", qtype: dns.TypeA, validation: resolver.ValidationSecure, rcode: dns.RcodeSuccess},
+		{name: "www.example.test.", qtype: dns.TypeAAAA, validation: resolver.ValidationSecure, rcode: dns.RcodeSuccess},
+		{name: "missing.example.test.", qtype: dns.TypeA, validation: resolver.ValidationSecure, rcode: dns.RcodeNameError},
+		{name: "any.example.test.", qtype: dns.TypeA, validation: resolver.ValidationSecure, rcode: dns.RcodeSuccess},
+		{name: "zzz.example.test.", qtype: dns.TypeA, validation: resolver.ValidationBogus, rcode: dns.RcodeServerFailure},
+		{name: "www.insecure.test.", qtype: dns.TypeA, validation: resolver.ValidationInsecure, rcode: dns.RcodeSuccess},
+		{name: "bad.example.test.", qtype: dns.TypeA, validation: resolver.ValidationBogus, rcode: dns.RcodeServerFailure},
+		{name: "nosig.example.test.", qtype: dns.TypeA, validation: resolver.ValidationBogus, rcode: dns.RcodeServerFailure},
+		{name: "unproven.example.test.", qtype: dns.TypeA, validation: resolver.ValidationBogus, rcode: dns.RcodeServerFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+dns.TypeToString[tt.qtype], func(t *testing.T) {
+			answer := query(t, v, tt.name, tt.qtype)
+			require.Equal(t, tt.validation, answer.Validation)
+			require.Equal(t, tt.rcode, answer.Msg.Rcode)
+			require.Equal(t, tt.validation == resolver.ValidationSecure, answer.Msg.AuthenticatedData)
+		})
+	}
+}
+
+func TestValidatorWrongTrustAnchor(t *testing.T) {
+	h := newTestHierarchy(t)
+	v := h.validator(t, newSignedZone(t, ".").ds())
+
+	answer := query(t, v, "www.example.test.", dns.TypeA)
+	require.Equal(t, resolver.ValidationBogus, answer.Validation)
+	require.Equal(t, dns.RcodeServerFailure, answer.Msg.Rcode)
+}
+
+func TestValidatorCachesKeys(t *testing.T) {
+	h := newTestHierarchy(t)
+	v := h.validator(t, h.root.ds())
+
+	query(t, v, "www.example.test.", dns.TypeA)
+
+	// ключи и DS уже проверены и повторно не запрашиваются
+	delete(h.upstream.answers, fmt.Sprintf("%s %d", "example.test.", dns.TypeDNSKEY))
+	delete(h.upstream.answers, fmt.Sprintf("%s %d", "example.test.", dns.TypeDS))
+
+	answer := query(t, v, "www.example.test.", dns.TypeA)
+	require.Equal(t, resolver.ValidationSecure, answer.Validation)
+}
+
+func TestCanonicalCompare(t *testing.T) {
+	require.Negative(t, canonicalCompare("example.test.", "a.example.test."))
+	require.Negative(t, canonicalCompare("a.example.test.", "Z.example.test."))
+	require.Negative(t, canonicalCompare("z.example.test.", "a.z.example.test."))
+	require.Positive(t, canonicalCompare("b.z.example.test.", "a.example.test."))
+	require.Zero(t, canonicalCompare("Example.Test.", "example.test."))
+}
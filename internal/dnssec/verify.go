@@ -0,0 +1,272 @@
+package dnssec
+
+import (
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// supportedAlgorithms - алгоритмы подписи, которые умеет проверять miekg/dns.
+// Зоны, подписанные только другими алгоритмами, считаются неподписанными
+// (RFC 4035, раздел 5.2).
+var supportedAlgorithms = map[uint8]bool{
+	dns.RSASHA1:          true,
+	dns.RSASHA1NSEC3SHA1: true,
+	dns.RSASHA256:        true,
+	dns.RSASHA512:        true,
+	dns.ECDSAP256SHA256:  true,
+	dns.ECDSAP384SHA384:  true,
+	dns.ED25519:          true,
+}
+
+var supportedDigests = map[uint8]bool{
+	dns.SHA1:   true,
+	dns.SHA256: true,
+	dns.SHA384: true,
+}
+
+func supportedDS(ds *dns.DS) bool {
+	return supportedAlgorithms[ds.Algorithm] && supportedDigests[ds.DigestType]
+}
+
+func dsMatches(ds *dns.DS, key *dns.DNSKEY) bool {
+	if ds.KeyTag != key.KeyTag() || ds.Algorithm != key.Algorithm {
+		return false
+	}
+	digest := key.ToDS(ds.DigestType)
+	return digest != nil && strings.EqualFold(digest.Digest, ds.Digest)
+}
+
+// rrset - записи одного имени и типа вместе с их подписями.
+type rrset struct {
+	name   string
+	rrtype uint16
+	rrs    []dns.RR
+	sigs   []*dns.RRSIG
+}
+
+// groupRRsets разбивает секцию сообщения на наборы записей.
+func groupRRsets(section []dns.RR) []*rrset {
+	type key struct {
+		name   string
+		rrtype uint16
+	}
+
+	var sets []*rrset
+	index := make(map[key]*rrset)
+
+	get := func(name string, rrtype uint16) *rrset {
+		k := key{name: dns.CanonicalName(name), rrtype: rrtype}
+		set, ok := index[k]
+		if !ok {
+			set = &rrset{name: k.name, rrtype: rrtype}
+			index[k] = set
+			sets = append(sets, set)
+		}
+		return set
+	}
+
+	for _, rr := range section {
+		switch rr := rr.(type) {
+		case *dns.OPT:
+		case *dns.RRSIG:
+			set := get(rr.Hdr.Name, rr.TypeCovered)
+			set.sigs = append(set.sigs, rr)
+		default:
+			set := get(rr.Header().Name, rr.Header().Rrtype)
+			set.rrs = append(set.rrs, rr)
+		}
+	}
+
+	// подписи без записей (например, от отброшенных наборов) не проверяются
+	filtered := sets[:0]
+	for _, set := range sets {
+		if len(set.rrs) > 0 {
+			filtered = append(filtered, set)
+		}
+	}
+
+	return filtered
+}
+
+func (s *rrset) minTTL() uint32 {
+	ttl := s.rrs[0].Header().Ttl
+	for _, rr := range s.rrs[1:] {
+		if rr.Header().Ttl < ttl {
+			ttl = rr.Header().Ttl
+		}
+	}
+	return ttl
+}
+
+// verifySignature проверяет подпись набора одним из ключей.
+func verifySignature(set *rrset, sig *dns.RRSIG, keys []*dns.DNSKEY, now time.Time) bool {
+	if !sig.ValidityPeriod(now) {
+		return false
+	}
+
+	for _, key := range keys {
+		if key.Flags&dns.ZONE == 0 || key.KeyTag() != sig.KeyTag || key.Algorithm != sig.Algorithm {
+			continue
+		}
+		if !strings.EqualFold(key.Hdr.Name, sig.SignerName) {
+			continue
+		}
+		if sig.Verify(key, set.rrs) == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasType(bitmap []uint16, rrtype uint16) bool {
+	for _, t := range bitmap {
+		if t == rrtype {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalCompare сравнивает имена в каноническом порядке DNSSEC
+// (RFC 4034, раздел 6.1): по меткам справа налево.
+func canonicalCompare(a, b string) int {
+	la := dns.SplitDomainName(strings.ToLower(a))
+	lb := dns.SplitDomainName(strings.ToLower(b))
+
+	for i := 1; i <= len(la) && i <= len(lb); i++ {
+		if c := strings.Compare(la[len(la)-i], lb[len(lb)-i]); c != 0 {
+			return c
+		}
+	}
+
+	return len(la) - len(lb)
+}
+
+// nsecCovers сообщает, попадает ли имя строго между владельцем записи NSEC
+// и следующим именем, то есть доказано ли его отсутствие.
+func nsecCovers(nsec *dns.NSEC, name string) bool {
+	owner, next := nsec.Hdr.Name, nsec.NextDomain
+
+	if canonicalCompare(owner, next) < 0 {
+		return canonicalCompare(owner, name) < 0 && canonicalCompare(name, next) < 0
+	}
+	// последняя запись зоны указывает на ее вершину
+	return canonicalCompare(owner, name) < 0 || canonicalCompare(name, next) < 0
+}
+
+// denial - записи NSEC и NSEC3 из отрицательного ответа.
+type denial struct {
+	nsec  []*dns.NSEC
+	nsec3 []*dns.NSEC3
+}
+
+func newDenial(sets []*rrset) denial {
+	var d denial
+	for _, set := range sets {
+		for _, rr := range set.rrs {
+			switch rr := rr.(type) {
+			case *dns.NSEC:
+				d.nsec = append(d.nsec, rr)
+			case *dns.NSEC3:
+				d.nsec3 = append(d.nsec3, rr)
+			}
+		}
+	}
+	return d
+}
+
+// bitmap возвращает список типов, существующих у имени.
+func (d denial) bitmap(name string) ([]uint16, bool) {
+	for _, nsec := range d.nsec {
+		if strings.EqualFold(nsec.Hdr.Name, name) {
+			return nsec.TypeBitMap, true
+		}
+	}
+	for _, nsec3 := range d.nsec3 {
+		if nsec3.Match(name) {
+			return nsec3.TypeBitMap, true
+		}
+	}
+	return nil, false
+}
+
+// nameError проверяет доказательство отсутствия имени. Для NSEC3
+// ищется ближайший существующий предок и запись, покрывающая имя на
+// одну метку длиннее него (RFC 5155, раздел 8.4).
+func (d denial) nameError(name string) (proven, optOut bool) {
+	for _, nsec := range d.nsec {
+		if nsecCovers(nsec, name) {
+			return true, false
+		}
+	}
+
+	nextCloser := name
+	for off, end := dns.NextLabel(name, 0); !end; off, end = dns.NextLabel(name, off) {
+		encloser := name[off:]
+		if !d.matches3(encloser) {
+			nextCloser = encloser
+			continue
+		}
+		for _, nsec3 := range d.nsec3 {
+			if nsec3.Cover(nextCloser) {
+				return true, nsec3.Flags&1 == 1
+			}
+		}
+		return false, false
+	}
+
+	return false, false
+}
+
+func (d denial) matches3(name string) bool {
+	for _, nsec3 := range d.nsec3 {
+		if nsec3.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardLabels сообщает, получен ли набор раскрытием шаблона, и число
+// меток шаблона без "*" из поля Labels подписи.
+func wildcardLabels(set *rrset) (int, bool) {
+	count := dns.CountLabel(set.name)
+	if strings.HasPrefix(set.name, "*.") {
+		count--
+	}
+	for _, sig := range set.sigs {
+		if int(sig.Labels) < count {
+			return int(sig.Labels), true
+		}
+	}
+	return 0, false
+}
+
+// noCloserMatch проверяет доказательство того, что имя не существует
+// и раскрыто из шаблона с labels метками: NSEC должна покрывать само
+// имя, NSEC3 - имя на одну метку длиннее ближайшего предка.
+func (d denial) noCloserMatch(name string, labels int) bool {
+	for _, nsec := range d.nsec {
+		if nsecCovers(nsec, name) {
+			return true
+		}
+	}
+
+	split := dns.Split(name)
+	nextCloser := name[split[len(split)-labels-1]:]
+	for _, nsec3 := range d.nsec3 {
+		if nsec3.Cover(nextCloser) {
+			return true
+		}
+	}
+	return false
+}
+
+// noData проверяет доказательство отсутствия записей типа у имени.
+func (d denial) noData(name string, qtype uint16) bool {
+	bitmap, ok := d.bitmap(name)
+	return ok && !hasType(bitmap, qtype) && !hasType(bitmap, dns.TypeCNAME)
+}
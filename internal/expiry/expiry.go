@@ -0,0 +1,323 @@
+package expiry
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/denisdubovitskiy/blackhole/internal/schedule"
+	"github.com/miekg/dns"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// Action - временное правило: блокировка или разблокировка.
+type Action string
+
+const (
+	ActionBlock   Action = "block"
+	ActionUnblock Action = "unblock"
+)
+
+// reapInterval - как часто отменяются истекшие правила.
+const reapInterval = 5 * time.Second
+
+// Rule - временное правило для домена. Origins - источники правила для
+// самого домена до применения временного правила: по ним оно
+// и отменяется. Шаблоны родительских доменов не учитываются: временная
+// разблокировка снимает и их действие исключением, а временная
+// блокировка их не меняет.
+type Rule struct {
+	Domain  string
+	Action  Action
+	Expires time.Time
+	Origins []string
+}
+
+type Blacklist interface {
+	AddFrom(ctx context.Context, origin string, domains ...string) (count int)
+	Remove(ctx context.Context, domains ...string) (count int)
+	RemoveFrom(ctx context.Context, origin string, domains ...string) (count int)
+	ExactOrigins(ctx context.Context, domain string) []string
+	Except(ctx context.Context, domains ...string)
+	Unexcept(ctx context.Context, domains ...string)
+}
+
+type Storage interface {
+	ExpiringRules(ctx context.Context) ([]datastore.ExpiringRule, error)
+	SetExpiringRule(ctx context.Context, rule datastore.ExpiringRule) error
+	DeleteExpiringRule(ctx context.Context, domain string) error
+}
+
+// Expiries применяет временные правила и отменяет их по истечении срока.
+type Expiries struct {
+	blacklist Blacklist
+	storage   Storage
+	clock     schedule.Clock
+	log       *zap.Logger
+
+	mu    sync.Mutex
+	rules map[string]Rule
+
+	reverted *atomic.Int64
+}
+
+func New(blacklist Blacklist, storage Storage, clock schedule.Clock, log *zap.Logger) *Expiries {
+	if clock == nil {
+		clock = schedule.SystemClock{}
+	}
+	e := &Expiries{
+		blacklist: blacklist,
+		storage:   storage,
+		clock:     clock,
+		log:       log,
+		rules:     make(map[string]Rule),
+		reverted:  atomic.NewInt64(0),
+	}
+
+	if expvar.Get("blackhole_expiries") == nil {
+		expvar.Publish("blackhole_expiries", expvar.Func(func() any {
+			return e.dumpStats()
+		}))
+	}
+
+	return e
+}
+
+func domainKey(domain string) string {
+	domain = strings.TrimSpace(domain)
+	if domain == "" {
+		return ""
+	}
+	return dns.Fqdn(domain)
+}
+
+// Load восстанавливает временные правила после перезапуска. Вызывается
+// после загрузки черного списка из базы данных: временные блокировки
+// в базе не хранятся, а разблокированные домены источников и постоянные
+// ручные блокировки загружаются заново.
+func (e *Expiries) Load(ctx context.Context) error {
+	stored, err := e.storage.ExpiringRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.clock.Now()
+	for _, s := range stored {
+		rule := Rule{Domain: s.Domain, Action: Action(s.Action), Expires: s.Expires, Origins: s.Origins}
+		if !now.Before(rule.Expires) {
+			// состояние до правила уже восстановлено загрузкой
+			if err := e.storage.DeleteExpiringRule(ctx, rule.Domain); err != nil {
+				return err
+			}
+			continue
+		}
+		switch rule.Action {
+		case ActionBlock:
+			e.blacklist.AddFrom(ctx, blacklist.OriginManual, rule.Domain)
+		case ActionUnblock:
+			e.blacklist.Remove(ctx, rule.Domain)
+			e.blacklist.Except(ctx, rule.Domain)
+		default:
+			return fmt.Errorf("expiry: unknown action %q", rule.Action)
+		}
+		e.rules[rule.Domain] = rule
+	}
+	return nil
+}
+
+// Block блокирует домены на время d.
+func (e *Expiries) Block(ctx context.Context, d time.Duration, domains ...string) error {
+	return e.apply(ctx, ActionBlock, d, domains)
+}
+
+// Unblock разблокирует домены на время d.
+func (e *Expiries) Unblock(ctx context.Context, d time.Duration, domains ...string) error {
+	return e.apply(ctx, ActionUnblock, d, domains)
+}
+
+func (e *Expiries) apply(ctx context.Context, action Action, d time.Duration, domains []string) error {
+	if d <= 0 {
+		return fmt.Errorf("expiry: duration must be positive")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	expires := e.clock.Now().Add(d).Truncate(time.Second)
+	for _, domain := range domains {
+		domain = domainKey(domain)
+		if domain == "" {
+			continue
+		}
+
+		rule, ok := e.rules[domain]
+		if ok && rule.Action != action {
+			// противоположное правило сначала отменяется
+			if err := e.revert(ctx, rule); err != nil {
+				return err
+			}
+			ok = false
+		}
+		if !ok {
+			rule = Rule{Domain: domain, Action: action, Origins: e.blacklist.ExactOrigins(ctx, domain)}
+		}
+		// повторное правило продлевает срок, но помнит исходное состояние
+		rule.Expires = expires
+
+		err := e.storage.SetExpiringRule(ctx, datastore.ExpiringRule{
+			Domain:  rule.Domain,
+			Action:  string(rule.Action),
+			Expires: rule.Expires,
+			Origins: rule.Origins,
+		})
+		if err != nil {
+			return err
+		}
+
+		switch action {
+		case ActionBlock:
+			e.blacklist.AddFrom(ctx, blacklist.OriginManual, domain)
+		case ActionUnblock:
+			e.blacklist.Remove(ctx, domain)
+			e.blacklist.Except(ctx, domain)
+		}
+		e.rules[domain] = rule
+	}
+	return nil
+}
+
+// Cancel забывает временные правила доменов перед постоянной блокировкой
+// или разблокировкой. Ручная блокировка временного правила остается
+// до постоянного правила, а временная разблокировка снимается:
+// исключение убирается и источники домена возвращаются, как при отмене
+// по сроку.
+func (e *Expiries) Cancel(ctx context.Context, domains ...string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, domain := range domains {
+		domain = domainKey(domain)
+		rule, ok := e.rules[domain]
+		if !ok {
+			continue
+		}
+		if err := e.storage.DeleteExpiringRule(ctx, domain); err != nil {
+			return err
+		}
+		if rule.Action == ActionUnblock {
+			e.restore(ctx, rule)
+		}
+		delete(e.rules, domain)
+	}
+	return nil
+}
+
+// Pending возвращает правила, ожидающие отмены, в порядке истечения.
+func (e *Expiries) Pending() []Rule {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	list := make([]Rule, 0, len(e.rules))
+	for _, rule := range e.rules {
+		list = append(list, rule)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Expires.Equal(list[j].Expires) {
+			return list[i].Expires.Before(list[j].Expires)
+		}
+		return list[i].Domain < list[j].Domain
+	})
+	return list
+}
+
+// RunPeriodicReaper отменяет истекшие правила.
+func (e *Expiries) RunPeriodicReaper(ctx context.Context) {
+	ticker := time.NewTicker(reapInterval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				e.reap(ctx)
+			}
+		}
+	}()
+}
+
+func (e *Expiries) reap(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.clock.Now()
+	for _, rule := range e.rules {
+		if now.Before(rule.Expires) {
+			continue
+		}
+		if err := e.revert(ctx, rule); err != nil {
+			e.log.Error("expiry: unable to revert rule", zap.String("domain", rule.Domain), zap.Error(err))
+			continue
+		}
+		e.reverted.Inc()
+		e.log.Info("expiry: rule reverted",
+			zap.String("domain", rule.Domain),
+			zap.String("action", string(rule.Action)),
+		)
+	}
+}
+
+// revert возвращает домену источники, которые были до правила.
+func (e *Expiries) revert(ctx context.Context, rule Rule) error {
+	if err := e.storage.DeleteExpiringRule(ctx, rule.Domain); err != nil {
+		return err
+	}
+	delete(e.rules, rule.Domain)
+
+	switch rule.Action {
+	case ActionBlock:
+		for _, origin := range rule.Origins {
+			if origin == blacklist.OriginManual {
+				return nil
+			}
+		}
+		e.blacklist.RemoveFrom(ctx, blacklist.OriginManual, rule.Domain)
+	case ActionUnblock:
+		e.restore(ctx, rule)
+	}
+	return nil
+}
+
+// restore снимает временную разблокировку: возвращает домену источники
+// и убирает исключение.
+func (e *Expiries) restore(ctx context.Context, rule Rule) {
+	e.blacklist.Unexcept(ctx, rule.Domain)
+	for _, origin := range rule.Origins {
+		e.blacklist.AddFrom(ctx, origin, rule.Domain)
+	}
+}
+
+type stats struct {
+	Pending  int   `json:"pending"`
+	Reverted int64 `json:"reverted"`
+}
+
+func (e *Expiries) dumpStats() stats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return stats{Pending: len(e.rules), Reverted: e.reverted.Load()}
+}
@@ -0,0 +1,178 @@
+package expiry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+type fakeStorage map[string]datastore.ExpiringRule
+
+func (s fakeStorage) ExpiringRules(context.Context) ([]datastore.ExpiringRule, error) {
+	var list []datastore.ExpiringRule
+	for _, rule := range s {
+		list = append(list, rule)
+	}
+	return list, nil
+}
+
+func (s fakeStorage) SetExpiringRule(_ context.Context, rule datastore.ExpiringRule) error {
+	s[rule.Domain] = rule
+	return nil
+}
+
+func (s fakeStorage) DeleteExpiringRule(_ context.Context, domain string) error {
+	delete(s, domain)
+	return nil
+}
+
+const ads = "https://lists.test/ads"
+
+func TestExpiries(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	storage := fakeStorage{}
+	bl := blacklist.New(4)
+	bl.AddFrom(ctx, ads, "ads.test", "tracker.test")
+	e := New(bl, storage, clock, zap.NewNop())
+
+	require.Error(t, e.Block(ctx, 0, "incident.test"))
+
+	require.NoError(t, e.Block(ctx, 2*time.Hour, "incident.test", "ads.test"))
+	require.NoError(t, e.Unblock(ctx, 30*time.Minute, "tracker.test"))
+	require.True(t, bl.Has(ctx, "incident.test"))
+	require.False(t, bl.Has(ctx, "tracker.test"))
+
+	pending := e.Pending()
+	require.Len(t, pending, 3)
+	require.Equal(t, Rule{
+		Domain:  "tracker.test.",
+		Action:  ActionUnblock,
+		Expires: clock.now.Add(30 * time.Minute),
+		Origins: []string{ads},
+	}, pending[0])
+
+	// разблокировка истекла, домен снова блокируется своим источником
+	clock.now = clock.now.Add(time.Hour)
+	e.reap(ctx)
+	require.Equal(t, []string{ads}, bl.Origins(ctx, "tracker.test"))
+	require.Len(t, e.Pending(), 2)
+	require.Len(t, storage, 2)
+
+	// перезапуск: ручных блокировок в памяти больше нет
+	restarted := blacklist.New(4)
+	restarted.AddFrom(ctx, ads, "ads.test", "tracker.test")
+	restored := New(restarted, storage, clock, zap.NewNop())
+	require.NoError(t, restored.Load(ctx))
+	require.True(t, restarted.Has(ctx, "incident.test"))
+	require.Equal(t, e.Pending(), restored.Pending())
+
+	// блокировка истекла, домен из источника остается заблокированным
+	clock.now = clock.now.Add(2 * time.Hour)
+	e.reap(ctx)
+	require.False(t, bl.Has(ctx, "incident.test"))
+	require.Equal(t, []string{ads}, bl.Origins(ctx, "ads.test"))
+	require.Empty(t, e.Pending())
+	require.Empty(t, storage)
+	require.Equal(t, int64(3), e.dumpStats().Reverted)
+}
+
+func TestExpiriesReplace(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	storage := fakeStorage{}
+	bl := blacklist.New(4)
+	bl.AddFrom(ctx, ads, "ads.test")
+	e := New(bl, storage, clock, zap.NewNop())
+
+	// временная блокировка вместо временной разблокировки отменяет ее
+	require.NoError(t, e.Unblock(ctx, time.Hour, "ads.test"))
+	require.NoError(t, e.Block(ctx, time.Hour, "ads.test"))
+	require.ElementsMatch(t, []string{ads, blacklist.OriginManual}, bl.Origins(ctx, "ads.test"))
+	require.Equal(t, []string{ads}, e.Pending()[0].Origins)
+
+	// постоянное правило заменяет временное
+	require.NoError(t, e.Cancel(ctx, "ads.test"))
+	clock.now = clock.now.Add(2 * time.Hour)
+	e.reap(ctx)
+	require.ElementsMatch(t, []string{ads, blacklist.OriginManual}, bl.Origins(ctx, "ads.test"))
+	require.Empty(t, storage)
+}
+
+func TestExpiriesWildcard(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	storage := fakeStorage{}
+	bl := blacklist.New(4)
+	bl.AddFrom(ctx, ads, "*.ads.test")
+	bl.Add(ctx, "*.social.test")
+	e := New(bl, storage, clock, zap.NewNop())
+
+	// разблокировка имени, заблокированного только шаблоном
+	require.NoError(t, e.Unblock(ctx, time.Hour, "cdn.ads.test"))
+	require.False(t, bl.Has(ctx, "cdn.ads.test"))
+	require.True(t, bl.Has(ctx, "img.ads.test"))
+	require.Empty(t, e.Pending()[0].Origins)
+
+	// блокировка имени под ручным шаблоном
+	require.NoError(t, e.Block(ctx, time.Hour, "chat.social.test"))
+	require.Equal(t, []string{blacklist.OriginManual}, bl.ExactOrigins(ctx, "chat.social.test"))
+
+	// исключение переживает перезапуск
+	restarted := blacklist.New(4)
+	restarted.AddFrom(ctx, ads, "*.ads.test")
+	require.NoError(t, New(restarted, storage, clock, zap.NewNop()).Load(ctx))
+	require.False(t, restarted.Has(ctx, "cdn.ads.test"))
+
+	// после отмены точных правил не появляется, шаблоны снова действуют
+	clock.now = clock.now.Add(2 * time.Hour)
+	e.reap(ctx)
+	require.Empty(t, e.Pending())
+	require.True(t, bl.Has(ctx, "cdn.ads.test"))
+	require.Nil(t, bl.ExactOrigins(ctx, "cdn.ads.test"))
+	require.Nil(t, bl.ExactOrigins(ctx, "chat.social.test"))
+	require.True(t, bl.Has(ctx, "chat.social.test"))
+
+	// постоянная блокировка заменяет временную разблокировку
+	require.NoError(t, e.Unblock(ctx, time.Hour, "cdn.ads.test"))
+	require.NoError(t, e.Cancel(ctx, "cdn.ads.test"))
+	require.True(t, bl.Has(ctx, "cdn.ads.test"))
+}
+
+func TestExpiriesCancelUnblock(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	storage := fakeStorage{}
+	bl := blacklist.New(4)
+	bl.AddFrom(ctx, ads, "ads.test", "tracker.test")
+	e := New(bl, storage, clock, zap.NewNop())
+
+	// постоянная разблокировка снимает только ручную блокировку,
+	// домен источника остается заблокированным
+	require.NoError(t, e.Unblock(ctx, time.Hour, "ads.test"))
+	require.NoError(t, e.Cancel(ctx, "ads.test"))
+	bl.RemoveFrom(ctx, blacklist.OriginManual, "ads.test")
+	require.Equal(t, []string{ads}, bl.Origins(ctx, "ads.test"))
+
+	// постоянная блокировка добавляет ручную к источникам домена
+	require.NoError(t, e.Unblock(ctx, time.Hour, "tracker.test"))
+	require.NoError(t, e.Cancel(ctx, "tracker.test"))
+	bl.Add(ctx, "tracker.test")
+	require.ElementsMatch(t, []string{ads, blacklist.OriginManual}, bl.Origins(ctx, "tracker.test"))
+
+	require.Empty(t, e.Pending())
+	require.Empty(t, storage)
+}
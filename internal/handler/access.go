@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/denisdubovitskiy/blackhole/internal/acl"
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+)
+
+type AccessList interface {
+	Rules() []acl.Rule
+	Add(ctx context.Context, rule acl.Rule) error
+	Delete(ctx context.Context, rule acl.Rule) error
+}
+
+func (h Handler) ListAccessRules(_ context.Context, _ *emptypb.Empty) (*pb.AccessRulesResponse, error) {
+	rules := h.access.Rules()
+
+	res := &pb.AccessRulesResponse{Rules: make([]*pb.AccessRule, len(rules))}
+	for i, rule := range rules {
+		res.Rules[i] = &pb.AccessRule{
+			Action:   rule.Action,
+			ClientId: rule.ClientID,
+			Static:   rule.Static,
+		}
+		if rule.Subnet.IsValid() {
+			res.Rules[i].Subnet = rule.Subnet.String()
+		}
+	}
+
+	return res, nil
+}
+
+func (h Handler) AddAccessRule(ctx context.Context, request *pb.AccessRule) (*emptypb.Empty, error) {
+	rule, err := fromAccessRule(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.access.Add(ctx, rule); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to add access rule: %v", err)
+	}
+	return ok, nil
+}
+
+func (h Handler) DeleteAccessRule(ctx context.Context, request *pb.AccessRule) (*emptypb.Empty, error) {
+	rule, err := fromAccessRule(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.access.Delete(ctx, rule); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "unable to delete access rule: %v", err)
+	}
+	return ok, nil
+}
+
+func fromAccessRule(rule *pb.AccessRule) (acl.Rule, error) {
+	res := acl.Rule{Action: rule.GetAction(), ClientID: rule.GetClientId()}
+	if rule.GetSubnet() != "" {
+		subnet, err := acl.ParseSubnet(rule.GetSubnet())
+		if err != nil {
+			return acl.Rule{}, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		res.Subnet = subnet
+	}
+	return res, nil
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/listeners/dnsserver"
+	"github.com/denisdubovitskiy/blackhole/internal/policy"
+)
+
+type Checker interface {
+	Check(name string, qtype uint16, identity policy.Identity) dnsserver.Verdict
+}
+
+func (h Handler) CheckDomain(_ context.Context, request *pb.CheckDomainRequest) (*pb.CheckDomainResponse, error) {
+	name := strings.TrimSpace(request.GetDomain())
+	if _, ok := dns.IsDomainName(name); !ok || name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid domain %q", request.GetDomain())
+	}
+
+	qtype := dns.TypeA
+	if t := request.GetType(); t != "" {
+		var ok bool
+		if qtype, ok = dns.StringToType[strings.ToUpper(t)]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown query type %q", t)
+		}
+	}
+
+	identity := policy.Identity{ID: request.GetClientId()}
+	if ip := request.GetClientIp(); ip != "" {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid client address %q", ip)
+		}
+		identity.IP = addr
+	}
+	if mac := request.GetClientMac(); mac != "" {
+		hw, err := net.ParseMAC(mac)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid client MAC address %q", mac)
+		}
+		identity.MAC = hw
+	}
+
+	v := h.checker.Check(dns.Fqdn(name), qtype, identity)
+
+	res := &pb.CheckDomainResponse{
+		Decision: string(v.Decision),
+		Client:   v.Client,
+		Group:    v.Group,
+		Reason:   v.Reason,
+		Rules:    make([]*pb.RuleMatch, len(v.Rules)),
+		Target:   v.Target,
+	}
+	for i, r := range v.Rules {
+		res.Rules[i] = &pb.RuleMatch{Kind: string(r.Kind), Rule: r.Rule, Sources: r.Origins, Active: r.Active}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+type Domains interface {
+	AddDomains(ctx context.Context, origin string, domains []string) error
+	DeleteDomains(ctx context.Context, origin string, domains []string) error
+	ListDomains(ctx context.Context, query datastore.DomainsQuery) ([]datastore.Domain, error)
+}
+
+// manualDomains приводит домены к виду, в котором их хранят источники:
+// в нижнем регистре и без завершающей точки.
+func manualDomains(domains []string) []string {
+	stored := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain != "" {
+			stored = append(stored, domain)
+		}
+	}
+	return stored
+}
+
+func (h Handler) ListDomains(ctx context.Context, request *pb.ListDomainsRequest) (*pb.ListDomainsResponse, error) {
+	size := int(request.GetPageSize())
+	switch {
+	case size < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must not be negative")
+	case size == 0:
+		size = defaultPageSize
+	case size > maxPageSize:
+		size = maxPageSize
+	}
+	if request.GetOrigin() != "" && request.GetSourceId() != 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "origin and source_id are mutually exclusive")
+	}
+
+	after, err := base64.RawURLEncoding.DecodeString(request.GetPageToken())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page_token")
+	}
+
+	origin := request.GetOrigin()
+	if strings.EqualFold(origin, blacklist.OriginManual) {
+		origin = blacklist.OriginManual
+	}
+
+	// лишний домен сообщает, есть ли следующая страница
+	domains, err := h.domains.ListDomains(ctx, datastore.DomainsQuery{
+		After:    string(after),
+		Prefix:   strings.ToLower(request.GetPrefix()),
+		Search:   strings.ToLower(request.GetSearch()),
+		Origin:   origin,
+		SourceID: request.GetSourceId(),
+		Limit:    size + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to list domains: %v", err)
+	}
+
+	res := &pb.ListDomainsResponse{}
+	if len(domains) > size {
+		domains = domains[:size]
+		res.NextPageToken = base64.RawURLEncoding.EncodeToString([]byte(domains[size-1].Domain))
+	}
+	res.Domains = make([]*pb.BlockedDomain, len(domains))
+	for i, d := range domains {
+		domain := &pb.BlockedDomain{Domain: d.Domain, Origins: make([]*pb.DomainOrigin, len(d.Origins))}
+		for j, o := range d.Origins {
+			domain.Origins[j] = &pb.DomainOrigin{Origin: o.Origin, SourceId: o.SourceID}
+		}
+		res.Domains[i] = domain
+	}
+
+	return res, nil
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/denisdubovitskiy/blackhole/internal/expiry"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const ads = "https://lists.test/ads"
+
+func newStorage(t *testing.T) datastore.Storage {
+	t.Helper()
+
+	db, err := datastore.Open(":memory:")
+	require.NoError(t, err)
+	// у каждого соединения с :memory: своя база
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	storage := datastore.New(db, 10, zap.NewNop())
+	require.NoError(t, storage.Migrate(context.Background()))
+	return storage
+}
+
+// load заполняет черный список из базы, как при запуске.
+func load(t *testing.T, storage datastore.Storage) *blacklist.BlackList {
+	t.Helper()
+
+	ctx := context.Background()
+	bl := blacklist.New(4)
+	require.NoError(t, storage.ForEachDomain(ctx, func(domain, origin string) {
+		bl.AddFrom(ctx, origin, domain)
+	}))
+	return bl
+}
+
+func newDomainsHandler(storage datastore.Storage, bl *blacklist.BlackList) pb.BlackholeServer {
+	return New(Config{
+		Blacklist: bl,
+		Expiries:  expiry.New(bl, storage, nil, zap.NewNop()),
+		Domains:   storage,
+	})
+}
+
+func TestUnblockSourceDomain(t *testing.T) {
+	ctx := context.Background()
+	storage := newStorage(t)
+	require.NoError(t, storage.AddSource(ctx, ads))
+	require.NoError(t, storage.AddDomains(ctx, ads, []string{"ads.example.com"}))
+
+	bl := load(t, storage)
+	h := newDomainsHandler(storage, bl)
+
+	_, err := h.Block(ctx, &pb.DomainsRequest{Domains: []string{"Ads.Example.com.", "Manual.TEST."}})
+	require.NoError(t, err)
+	// в памяти домен хранится в том же написании, что и в базе
+	require.True(t, bl.Has(ctx, "manual.test"))
+	_, err = h.Unblock(ctx, &pb.DomainsRequest{Domains: []string{"ads.example.com", "manual.test"}})
+	require.NoError(t, err)
+
+	// разблокировка снимает только ручную блокировку, домен списка
+	// остается заблокированным в памяти и в базе
+	require.Equal(t, []string{ads}, bl.Origins(ctx, "ads.example.com"))
+	require.False(t, bl.Has(ctx, "manual.test"))
+
+	res, err := h.ListDomains(ctx, &pb.ListDomainsRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.GetDomains(), 1)
+	require.Equal(t, "ads.example.com", res.GetDomains()[0].GetDomain())
+	require.Equal(t, ads, res.GetDomains()[0].GetOrigins()[0].GetOrigin())
+	require.Equal(t, int64(1), res.GetDomains()[0].GetOrigins()[0].GetSourceId())
+
+	// после перезапуска состояние то же
+	reloaded := load(t, storage)
+	require.Equal(t, []string{ads}, reloaded.Origins(ctx, "ads.example.com"))
+	require.False(t, reloaded.Has(ctx, "manual.test"))
+}
+
+func TestListDomains(t *testing.T) {
+	ctx := context.Background()
+	storage := newStorage(t)
+	require.NoError(t, storage.AddSource(ctx, ads))
+	require.NoError(t, storage.AddDomains(ctx, ads, []string{"ads.example.com", "ads.other.test"}))
+
+	h := newDomainsHandler(storage, load(t, storage))
+	_, err := h.Block(ctx, &pb.DomainsRequest{Domains: []string{"ads.example.com", "my.test"}})
+	require.NoError(t, err)
+
+	names := func(res *pb.ListDomainsResponse) []string {
+		var names []string
+		for _, d := range res.GetDomains() {
+			names = append(names, d.GetDomain())
+		}
+		return names
+	}
+
+	page, err := h.ListDomains(ctx, &pb.ListDomainsRequest{PageSize: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ads.example.com", "ads.other.test"}, names(page))
+	require.Len(t, page.GetDomains()[0].GetOrigins(), 2)
+
+	page, err = h.ListDomains(ctx, &pb.ListDomainsRequest{PageSize: 2, PageToken: page.GetNextPageToken()})
+	require.NoError(t, err)
+	require.Equal(t, []string{"my.test"}, names(page))
+	require.Empty(t, page.GetNextPageToken())
+
+	for _, tt := range []struct {
+		request *pb.ListDomainsRequest
+		want    []string
+	}{
+		{request: &pb.ListDomainsRequest{Prefix: "ADS."}, want: []string{"ads.example.com", "ads.other.test"}},
+		{request: &pb.ListDomainsRequest{Search: "example"}, want: []string{"ads.example.com"}},
+		{request: &pb.ListDomainsRequest{Origin: "manual"}, want: []string{"ads.example.com", "my.test"}},
+		{request: &pb.ListDomainsRequest{SourceId: 1, Search: "other"}, want: []string{"ads.other.test"}},
+		{request: &pb.ListDomainsRequest{SourceId: 2}},
+	} {
+		res, err := h.ListDomains(ctx, tt.request)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, names(res), tt.request.String())
+	}
+
+	_, err = h.ListDomains(ctx, &pb.ListDomainsRequest{Origin: "manual", SourceId: 1})
+	require.Error(t, err)
+	_, err = h.ListDomains(ctx, &pb.ListDomainsRequest{PageToken: "!"})
+	require.Error(t, err)
+}
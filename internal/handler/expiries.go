@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/expiry"
+)
+
+type Expiries interface {
+	Block(ctx context.Context, d time.Duration, domains ...string) error
+	Unblock(ctx context.Context, d time.Duration, domains ...string) error
+	Cancel(ctx context.Context, domains ...string) error
+	Pending() []expiry.Rule
+}
+
+func (h Handler) ListExpiries(_ context.Context, _ *emptypb.Empty) (*pb.ExpiriesResponse, error) {
+	rules := h.expiries.Pending()
+
+	res := &pb.ExpiriesResponse{Expiries: make([]*pb.Expiry, len(rules))}
+	for i, rule := range rules {
+		res.Expiries[i] = &pb.Expiry{
+			Domain:    rule.Domain,
+			Action:    string(rule.Action),
+			ExpiresAt: timestamppb.New(rule.Expires),
+			Origins:   rule.Origins,
+		}
+	}
+
+	return res, nil
+}
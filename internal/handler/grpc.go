@@ -7,6 +7,8 @@ import (
 	"google.golang.org/grpc/status"
 
 	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/denisdubovitskiy/blackhole/internal/resolver"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -17,13 +19,55 @@ type SourcesProvider interface {
 
 type Blacklist interface {
 	Add(ctx context.Context, domains ...string) (count int)
-	Remove(ctx context.Context, domains ...string) (count int)
+	RemoveFrom(ctx context.Context, origin string, domains ...string) (count int)
 }
 
-func New(blacklist Blacklist, sourcesProvider SourcesProvider) pb.BlackholeServer {
+type Upstreams interface {
+	Upstreams() []resolver.Upstream
+	Groups() []resolver.Group
+	SetGroup(group resolver.Group) error
+	DeleteGroup(name string) error
+	ForwardingRules() []resolver.ForwardingRule
+	SetForwardingRule(rule resolver.ForwardingRule) error
+	DeleteForwardingRule(zone string) error
+}
+
+// Config - зависимости обработчика API.
+type Config struct {
+	Blacklist       Blacklist
+	SourcesProvider SourcesProvider
+	Upstreams       Upstreams
+	Access          AccessList
+	Schedules       Schedules
+	Pauses          Pauses
+	Expiries        Expiries
+	Records         LocalRecords
+	SafeSearch      SafeSearch
+	Services        Services
+	Patterns        Patterns
+	Checker         Checker
+	Domains         Domains
+	// Recursive - сервер разрешает имена сам, вышестоящие серверы
+	// и правила пересылки не используются.
+	Recursive bool
+}
+
+func New(config Config) pb.BlackholeServer {
 	return &Handler{
-		blacklist:       blacklist,
-		sourcesProvider: sourcesProvider,
+		blacklist:       config.Blacklist,
+		sourcesProvider: config.SourcesProvider,
+		upstreams:       config.Upstreams,
+		access:          config.Access,
+		schedules:       config.Schedules,
+		pauses:          config.Pauses,
+		expiries:        config.Expiries,
+		records:         config.Records,
+		safeSearch:      config.SafeSearch,
+		services:        config.Services,
+		patterns:        config.Patterns,
+		checker:         config.Checker,
+		domains:         config.Domains,
+		recursive:       config.Recursive,
 	}
 }
 
@@ -31,17 +75,71 @@ type Handler struct {
 	pb.UnimplementedBlackholeServer
 	blacklist       Blacklist
 	sourcesProvider SourcesProvider
+	upstreams       Upstreams
+	access          AccessList
+	schedules       Schedules
+	pauses          Pauses
+	expiries        Expiries
+	records         LocalRecords
+	safeSearch      SafeSearch
+	services        Services
+	patterns        Patterns
+	checker         Checker
+	domains         Domains
+	recursive       bool
 }
 
 var ok = &emptypb.Empty{}
 
 func (h Handler) Block(ctx context.Context, request *pb.DomainsRequest) (*emptypb.Empty, error) {
-	h.blacklist.Add(ctx, request.GetDomains()...)
+	// в базе и в памяти домены хранятся в одном написании
+	domains := manualDomains(request.GetDomains())
+
+	if request.GetExpiresIn() != nil {
+		d := request.GetExpiresIn().AsDuration()
+		if d <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "expires_in must be positive")
+		}
+		if err := h.expiries.Block(ctx, d, domains...); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to block domains: %v", err)
+		}
+		return ok, nil
+	}
+
+	// постоянная блокировка заменяет временное правило
+	if err := h.expiries.Cancel(ctx, domains...); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to block domains: %v", err)
+	}
+	// постоянные ручные блокировки загружаются из базы при запуске
+	if err := h.domains.AddDomains(ctx, blacklist.OriginManual, domains); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to block domains: %v", err)
+	}
+	h.blacklist.Add(ctx, domains...)
 	return ok, nil
 }
 
 func (h Handler) Unblock(ctx context.Context, request *pb.DomainsRequest) (*emptypb.Empty, error) {
-	h.blacklist.Remove(ctx, request.GetDomains()...)
+	domains := manualDomains(request.GetDomains())
+
+	if request.GetExpiresIn() != nil {
+		d := request.GetExpiresIn().AsDuration()
+		if d <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "expires_in must be positive")
+		}
+		if err := h.expiries.Unblock(ctx, d, domains...); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to unblock domains: %v", err)
+		}
+		return ok, nil
+	}
+
+	if err := h.expiries.Cancel(ctx, domains...); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to unblock domains: %v", err)
+	}
+	if err := h.domains.DeleteDomains(ctx, blacklist.OriginManual, domains); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to unblock domains: %v", err)
+	}
+	// домены источников остаются заблокированными и в памяти, и в базе
+	h.blacklist.RemoveFrom(ctx, blacklist.OriginManual, domains...)
 	return ok, nil
 }
 
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/patterns"
+)
+
+type Patterns interface {
+	List() []patterns.Rule
+	Add(ctx context.Context, pattern, comment string) (patterns.Rule, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+func (h Handler) ListRegexRules(_ context.Context, _ *emptypb.Empty) (*pb.RegexRulesResponse, error) {
+	rules := h.patterns.List()
+
+	res := &pb.RegexRulesResponse{Rules: make([]*pb.RegexRule, len(rules))}
+	for i, r := range rules {
+		res.Rules[i] = toRegexRule(r)
+	}
+
+	return res, nil
+}
+
+func (h Handler) AddRegexRule(ctx context.Context, request *pb.RegexRule) (*pb.RegexRule, error) {
+	r, err := h.patterns.Add(ctx, request.GetPattern(), request.GetComment())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to add a regex rule: %v", err)
+	}
+	return toRegexRule(r), nil
+}
+
+func (h Handler) DeleteRegexRule(ctx context.Context, request *pb.DeleteRegexRuleRequest) (*emptypb.Empty, error) {
+	if err := h.patterns.Delete(ctx, request.GetId()); err != nil {
+		return nil, status.Errorf(codes.NotFound, "unable to delete a regex rule: %v", err)
+	}
+	return ok, nil
+}
+
+func toRegexRule(r patterns.Rule) *pb.RegexRule {
+	rule := &pb.RegexRule{
+		Id:          r.ID,
+		Pattern:     r.Pattern,
+		Comment:     r.Comment,
+		Evaluations: r.Evaluations,
+		Hits:        r.Hits,
+		Spent:       durationpb.New(r.Spent),
+	}
+	if !r.LastHit.IsZero() {
+		rule.LastHit = timestamppb.New(r.LastHit)
+	}
+	return rule
+}
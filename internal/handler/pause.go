@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/pause"
+)
+
+type Pauses interface {
+	List() []pause.Pause
+	Pause(ctx context.Context, scope pause.Scope, target string, d time.Duration) (pause.Pause, error)
+	Resume(ctx context.Context, scope pause.Scope, target string) error
+}
+
+func (h Handler) ListPauses(_ context.Context, _ *emptypb.Empty) (*pb.PausesResponse, error) {
+	pauses := h.pauses.List()
+
+	res := &pb.PausesResponse{Pauses: make([]*pb.Pause, len(pauses))}
+	for i, p := range pauses {
+		res.Pauses[i] = toPause(p)
+	}
+
+	return res, nil
+}
+
+func (h Handler) PauseProtection(ctx context.Context, request *pb.PauseRequest) (*pb.Pause, error) {
+	if request.GetDuration() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "duration is required")
+	}
+
+	p, err := h.pauses.Pause(ctx, scope(request.GetScope()), request.GetTarget(), request.GetDuration().AsDuration())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to pause protection: %v", err)
+	}
+	return toPause(p), nil
+}
+
+func (h Handler) ResumeProtection(ctx context.Context, request *pb.ResumeRequest) (*emptypb.Empty, error) {
+	if err := h.pauses.Resume(ctx, scope(request.GetScope()), request.GetTarget()); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "unable to resume protection: %v", err)
+	}
+	return ok, nil
+}
+
+// scope по умолчанию приостанавливает защиту для всех.
+func scope(s string) pause.Scope {
+	if s == "" {
+		return pause.ScopeAll
+	}
+	return pause.Scope(s)
+}
+
+func toPause(p pause.Pause) *pb.Pause {
+	return &pb.Pause{
+		Scope:    string(p.Scope),
+		Target:   p.Target,
+		ResumeAt: timestamppb.New(p.Until),
+	}
+}
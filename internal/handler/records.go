@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/records"
+)
+
+type LocalRecords interface {
+	List() []records.Record
+	Add(ctx context.Context, r records.Record) (records.Record, error)
+	Update(ctx context.Context, r records.Record) (records.Record, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+func (h Handler) ListLocalRecords(_ context.Context, _ *emptypb.Empty) (*pb.LocalRecordsResponse, error) {
+	list := h.records.List()
+
+	res := &pb.LocalRecordsResponse{Records: make([]*pb.LocalRecord, len(list))}
+	for i, r := range list {
+		res.Records[i] = toLocalRecord(r)
+	}
+
+	return res, nil
+}
+
+func (h Handler) AddLocalRecord(ctx context.Context, request *pb.LocalRecord) (*pb.LocalRecord, error) {
+	r, err := h.records.Add(ctx, fromLocalRecord(request))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to add record: %v", err)
+	}
+	return toLocalRecord(r), nil
+}
+
+func (h Handler) UpdateLocalRecord(ctx context.Context, request *pb.LocalRecord) (*pb.LocalRecord, error) {
+	r, err := h.records.Update(ctx, fromLocalRecord(request))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to update record: %v", err)
+	}
+	return toLocalRecord(r), nil
+}
+
+func (h Handler) DeleteLocalRecord(ctx context.Context, request *pb.DeleteLocalRecordRequest) (*emptypb.Empty, error) {
+	if err := h.records.Delete(ctx, request.GetId()); err != nil {
+		return nil, status.Errorf(codes.NotFound, "unable to delete record: %v", err)
+	}
+	return ok, nil
+}
+
+func toLocalRecord(r records.Record) *pb.LocalRecord {
+	return &pb.LocalRecord{
+		Id:       r.ID,
+		Name:     r.Name,
+		Type:     r.Type,
+		Value:    r.Value,
+		Ttl:      r.TTL,
+		Source:   r.Source,
+		Shadowed: r.Shadowed,
+	}
+}
+
+func fromLocalRecord(r *pb.LocalRecord) records.Record {
+	return records.Record{
+		ID:    r.GetId(),
+		Name:  r.GetName(),
+		Type:  r.GetType(),
+		Value: r.GetValue(),
+		TTL:   r.GetTtl(),
+	}
+}
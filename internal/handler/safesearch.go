@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/safesearch"
+)
+
+type SafeSearch interface {
+	Settings() []safesearch.Setting
+	Set(ctx context.Context, group string, engines []safesearch.Engine) error
+	Reset(ctx context.Context, group string) error
+}
+
+func (h Handler) GetSafeSearch(_ context.Context, _ *emptypb.Empty) (*pb.SafeSearchResponse, error) {
+	settings := h.safeSearch.Settings()
+
+	res := &pb.SafeSearchResponse{Settings: make([]*pb.SafeSearchSetting, len(settings))}
+	for i, s := range settings {
+		res.Settings[i] = &pb.SafeSearchSetting{Group: s.Group, Engines: fromEngines(s.Engines)}
+	}
+	res.Engines = fromEngines(safesearch.Engines())
+
+	return res, nil
+}
+
+func (h Handler) SetSafeSearch(ctx context.Context, request *pb.SafeSearchSetting) (*emptypb.Empty, error) {
+	engines := make([]safesearch.Engine, len(request.GetEngines()))
+	for i, e := range request.GetEngines() {
+		engines[i] = safesearch.Engine(e)
+	}
+
+	if err := h.safeSearch.Set(ctx, request.GetGroup(), engines); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to set safe search: %v", err)
+	}
+	return ok, nil
+}
+
+func (h Handler) ResetSafeSearch(ctx context.Context, request *pb.ResetSafeSearchRequest) (*emptypb.Empty, error) {
+	if err := h.safeSearch.Reset(ctx, request.GetGroup()); err != nil {
+		return nil, status.Errorf(codes.NotFound, "unable to reset safe search: %v", err)
+	}
+	return ok, nil
+}
+
+func fromEngines(engines []safesearch.Engine) []string {
+	list := make([]string, len(engines))
+	for i, e := range engines {
+		list[i] = string(e)
+	}
+	return list
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/schedule"
+)
+
+type Schedules interface {
+	List() []schedule.Schedule
+	Attachments() []schedule.Attachment
+	Active(name string) bool
+	Set(ctx context.Context, s schedule.Schedule) error
+	Delete(ctx context.Context, name string) error
+	Attach(ctx context.Context, attachment schedule.Attachment) error
+	Detach(ctx context.Context, kind schedule.Kind, target string) error
+}
+
+func (h Handler) ListSchedules(_ context.Context, _ *emptypb.Empty) (*pb.SchedulesResponse, error) {
+	schedules := h.schedules.List()
+	attachments := h.schedules.Attachments()
+
+	res := &pb.SchedulesResponse{
+		Schedules:   make([]*pb.Schedule, len(schedules)),
+		Attachments: make([]*pb.ScheduleAttachment, len(attachments)),
+	}
+	for i, s := range schedules {
+		res.Schedules[i] = &pb.Schedule{
+			Name:     s.Name,
+			Timezone: s.Timezone,
+			Ranges:   s.RangeStrings(),
+			Active:   h.schedules.Active(s.Name),
+		}
+	}
+	for i, a := range attachments {
+		res.Attachments[i] = &pb.ScheduleAttachment{
+			Kind:     string(a.Kind),
+			Target:   a.Target,
+			Schedule: a.Schedule,
+		}
+	}
+
+	return res, nil
+}
+
+func (h Handler) SetSchedule(ctx context.Context, request *pb.Schedule) (*emptypb.Empty, error) {
+	s, err := schedule.Parse(request.GetName(), request.GetTimezone(), request.GetRanges())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := h.schedules.Set(ctx, s); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to save schedule: %v", err)
+	}
+	return ok, nil
+}
+
+func (h Handler) DeleteSchedule(ctx context.Context, request *pb.DeleteScheduleRequest) (*emptypb.Empty, error) {
+	if err := h.schedules.Delete(ctx, request.GetName()); err != nil {
+		return nil, status.Errorf(codes.NotFound, "unable to delete schedule: %v", err)
+	}
+	return ok, nil
+}
+
+func (h Handler) AttachSchedule(ctx context.Context, request *pb.ScheduleAttachment) (*emptypb.Empty, error) {
+	err := h.schedules.Attach(ctx, schedule.Attachment{
+		Kind:     schedule.Kind(request.GetKind()),
+		Target:   request.GetTarget(),
+		Schedule: request.GetSchedule(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to attach schedule: %v", err)
+	}
+	return ok, nil
+}
+
+func (h Handler) DetachSchedule(ctx context.Context, request *pb.DetachScheduleRequest) (*emptypb.Empty, error) {
+	if err := h.schedules.Detach(ctx, schedule.Kind(request.GetKind()), request.GetTarget()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to detach schedule: %v", err)
+	}
+	return ok, nil
+}
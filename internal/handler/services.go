@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/services"
+)
+
+type Services interface {
+	Version() int
+	List() []services.Status
+	Set(ctx context.Context, id, group string, blocked bool) error
+	Reset(ctx context.Context, id, group string) error
+}
+
+func (h Handler) ListServices(_ context.Context, _ *emptypb.Empty) (*pb.ServicesResponse, error) {
+	list := h.services.List()
+
+	res := &pb.ServicesResponse{
+		Version:  int32(h.services.Version()),
+		Services: make([]*pb.Service, len(list)),
+	}
+	for i, s := range list {
+		res.Services[i] = &pb.Service{
+			Id:      s.ID,
+			Name:    s.Name,
+			Rules:   s.Rules,
+			Blocked: s.Blocked,
+			Groups:  s.Groups,
+		}
+	}
+
+	return res, nil
+}
+
+func (h Handler) SetService(ctx context.Context, request *pb.SetServiceRequest) (*emptypb.Empty, error) {
+	if err := h.services.Set(ctx, request.GetId(), request.GetGroup(), request.GetBlocked()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to set service: %v", err)
+	}
+	return ok, nil
+}
+
+func (h Handler) ResetService(ctx context.Context, request *pb.ResetServiceRequest) (*emptypb.Empty, error) {
+	if err := h.services.Reset(ctx, request.GetId(), request.GetGroup()); err != nil {
+		return nil, status.Errorf(codes.NotFound, "unable to reset service: %v", err)
+	}
+	return ok, nil
+}
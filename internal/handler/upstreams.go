@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"context"
+	"math"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/resolver"
+)
+
+func (h Handler) ListUpstreams(_ context.Context, _ *emptypb.Empty) (*pb.UpstreamsResponse, error) {
+	return &pb.UpstreamsResponse{Upstreams: toUpstreams(h.upstreams.Upstreams())}, nil
+}
+
+func (h Handler) SetUpstreams(_ context.Context, request *pb.SetUpstreamsRequest) (*emptypb.Empty, error) {
+	if h.recursive {
+		return nil, status.Errorf(codes.FailedPrecondition, "upstreams are not used in recursive mode")
+	}
+	upstreams, err := fromUpstreams(request.GetUpstreams())
+	if err != nil {
+		return nil, err
+	}
+
+	group := resolver.Group{
+		Name:      request.GetGroup(),
+		Strategy:  request.GetStrategy(),
+		Parallel:  int(request.GetParallel()),
+		Upstreams: upstreams,
+	}
+	if group.Name == "" {
+		group.Name = resolver.DefaultGroup
+	}
+
+	// параметры, не переданные в запросе, сохраняются
+	for _, existing := range h.upstreams.Groups() {
+		if existing.Name != group.Name {
+			continue
+		}
+		if group.Strategy == "" {
+			group.Strategy = existing.Strategy
+		}
+		if group.Parallel == 0 {
+			group.Parallel = existing.Parallel
+		}
+	}
+
+	if err := h.upstreams.SetGroup(group); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to set upstreams: %v", err)
+	}
+
+	return ok, nil
+}
+
+func (h Handler) ListUpstreamGroups(_ context.Context, _ *emptypb.Empty) (*pb.UpstreamGroupsResponse, error) {
+	groups := h.upstreams.Groups()
+
+	res := &pb.UpstreamGroupsResponse{Groups: make([]*pb.UpstreamGroup, len(groups))}
+	for i, g := range groups {
+		res.Groups[i] = &pb.UpstreamGroup{
+			Name:      g.Name,
+			Strategy:  g.Strategy,
+			Parallel:  int32(g.Parallel),
+			Upstreams: toUpstreams(g.Upstreams),
+		}
+	}
+
+	return res, nil
+}
+
+func (h Handler) DeleteUpstreamGroup(_ context.Context, request *pb.DeleteUpstreamGroupRequest) (*emptypb.Empty, error) {
+	if err := h.upstreams.DeleteGroup(request.GetName()); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "unable to delete upstream group: %v", err)
+	}
+	return ok, nil
+}
+
+func (h Handler) ListForwardingRules(_ context.Context, _ *emptypb.Empty) (*pb.ForwardingRulesResponse, error) {
+	rules := h.upstreams.ForwardingRules()
+
+	res := &pb.ForwardingRulesResponse{Rules: make([]*pb.ForwardingRule, len(rules))}
+	for i, rule := range rules {
+		res.Rules[i] = &pb.ForwardingRule{Zone: rule.Zone, Group: rule.Group}
+	}
+
+	return res, nil
+}
+
+func (h Handler) SetForwardingRule(_ context.Context, request *pb.ForwardingRule) (*emptypb.Empty, error) {
+	if h.recursive {
+		return nil, status.Errorf(codes.FailedPrecondition, "forwarding rules are not supported in recursive mode")
+	}
+	rule := resolver.ForwardingRule{Zone: request.GetZone(), Group: request.GetGroup()}
+
+	if err := h.upstreams.SetForwardingRule(rule); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to set forwarding rule: %v", err)
+	}
+	return ok, nil
+}
+
+func (h Handler) DeleteForwardingRule(_ context.Context, request *pb.DeleteForwardingRuleRequest) (*emptypb.Empty, error) {
+	if err := h.upstreams.DeleteForwardingRule(request.GetZone()); err != nil {
+		return nil, status.Errorf(codes.NotFound, "unable to delete forwarding rule: %v", err)
+	}
+	return ok, nil
+}
+
+func toUpstreams(upstreams []resolver.Upstream) []*pb.Upstream {
+	res := make([]*pb.Upstream, len(upstreams))
+	for i, u := range upstreams {
+		res[i] = &pb.Upstream{
+			Address:    u.Address,
+			Protocol:   u.Protocol,
+			Timeout:    durationpb.New(u.Timeout),
+			Weight:     int32(u.Weight),
+			ServerName: u.ServerName,
+			Pins:       u.Pins,
+			Method:     u.Method,
+			UdpSize:    uint32(u.UDPSize),
+			CaFile:     u.CAFile,
+		}
+	}
+	return res
+}
+
+func fromUpstreams(upstreams []*pb.Upstream) ([]resolver.Upstream, error) {
+	res := make([]resolver.Upstream, len(upstreams))
+
+	for i, u := range upstreams {
+		if u.GetUdpSize() > math.MaxUint16 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid upstream: udp_size %d is too large", u.GetUdpSize())
+		}
+		upstream, err := resolver.NewUpstream(u.GetAddress(), resolver.Upstream{
+			Protocol:   u.GetProtocol(),
+			Timeout:    u.GetTimeout().AsDuration(),
+			Weight:     int(u.GetWeight()),
+			ServerName: u.GetServerName(),
+			Pins:       u.GetPins(),
+			Method:     u.GetMethod(),
+			UDPSize:    uint16(u.GetUdpSize()),
+			CAFile:     u.GetCaFile(),
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid upstream: %v", err)
+		}
+		res[i] = upstream
+	}
+
+	return res, nil
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/denisdubovitskiy/blackhole/internal/api"
+	"github.com/denisdubovitskiy/blackhole/internal/resolver"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpstreamsRoundTrip(t *testing.T) {
+	u, err := resolver.NewUpstream("tls://dns.test:853", resolver.Upstream{
+		ServerName: "dns.test",
+		CAFile:     "/etc/blackhole/ca.pem",
+	})
+	require.NoError(t, err)
+
+	// List и Set через API не теряют параметры сервера из конфигурации
+	res, err := fromUpstreams(toUpstreams([]resolver.Upstream{u}))
+	require.NoError(t, err)
+	require.Equal(t, []resolver.Upstream{u}, res)
+}
+
+func TestUpstreamsRecursive(t *testing.T) {
+	ctx := context.Background()
+	h := New(Config{Recursive: true})
+
+	// в рекурсивном режиме API отклоняет то же, что и конфигурация
+	_, err := h.SetUpstreams(ctx, &pb.SetUpstreamsRequest{Upstreams: []*pb.Upstream{{Address: "1.1.1.1"}}})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = h.SetForwardingRule(ctx, &pb.ForwardingRule{Zone: "corp.test", Group: "corp"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
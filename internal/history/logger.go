@@ -20,6 +20,10 @@ const (
 	StatusBlocked  Status = "blocked"
 	StatusFailed   Status = "failed"
 	StatusResolved Status = "resolved"
+	// StatusLocal - ответ из локальных записей.
+	StatusLocal Status = "local"
+	// StatusRewritten - ответ CNAME на безопасный адрес поисковика.
+	StatusRewritten Status = "rewritten"
 )
 
 type Record struct {
@@ -27,6 +31,10 @@ type Record struct {
 	Name       string
 	Status     Status
 	ClientAddr string
+	// UpstreamGroup - группа вышестоящих серверов, ответившая на запрос.
+	UpstreamGroup string
+	// Validation - результат проверки DNSSEC (secure, insecure, bogus).
+	Validation string
 }
 
 func NewRecord(remoteAddr net.Addr, question dns.Question, status Status) Record {
@@ -53,12 +61,22 @@ func NewBlocked(remoteAddr net.Addr, question dns.Question) Record {
 	return NewRecord(remoteAddr, question, StatusBlocked)
 }
 
+func NewLocal(remoteAddr net.Addr, question dns.Question) Record {
+	return NewRecord(remoteAddr, question, StatusLocal)
+}
+
+func NewRewritten(remoteAddr net.Addr, question dns.Question) Record {
+	return NewRecord(remoteAddr, question, StatusRewritten)
+}
+
 func NewFailed(remoteAddr net.Addr, question dns.Question) Record {
 	return NewRecord(remoteAddr, question, StatusFailed)
 }
 
-func NewResolved(remoteAddr net.Addr, question dns.Question) Record {
-	return NewRecord(remoteAddr, question, StatusResolved)
+func NewResolved(remoteAddr net.Addr, question dns.Question, upstreamGroup string) Record {
+	r := NewRecord(remoteAddr, question, StatusResolved)
+	r.UpstreamGroup = upstreamGroup
+	return r
 }
 
 type Logger interface {
@@ -149,10 +167,12 @@ func (l *logger) saveChunk(ctx context.Context, chunk []Record) error {
 
 	for i, rec := range chunk {
 		records[i] = datastore.HistoryRecord{
-			Type:       rec.Qtype,
-			Domain:     rec.Name,
-			Status:     string(rec.Status),
-			ClientAddr: rec.ClientAddr,
+			Type:          rec.Qtype,
+			Domain:        rec.Name,
+			Status:        string(rec.Status),
+			ClientAddr:    rec.ClientAddr,
+			UpstreamGroup: rec.UpstreamGroup,
+			Validation:    rec.Validation,
 		}
 	}
 
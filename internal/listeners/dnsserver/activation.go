@@ -0,0 +1,63 @@
+package dnsserver
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFdsStart - первый дескриптор, передаваемый systemd (sd_listen_fds).
+const listenFdsStart = 3
+
+// ActivationSockets возвращает сокеты, открытые и переданные процессу
+// менеджером служб (systemd socket activation): потоковые сокеты - как
+// Listeners, датаграммные - как PacketConns. Так сервер может работать
+// без прав на открытие порта 53.
+func ActivationSockets() ([]net.PacketConn, []net.Listener, error) {
+	packetConns, listeners, err := activationSockets(os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS"), listenFdsStart)
+
+	// дочерние процессы не должны получить те же сокеты
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	return packetConns, listeners, err
+}
+
+func activationSockets(pid, fds string, start int) ([]net.PacketConn, []net.Listener, error) {
+	if fds == "" || pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil, nil
+	}
+
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 0 {
+		return nil, nil, fmt.Errorf("dnsserver: invalid LISTEN_FDS %q", fds)
+	}
+
+	var (
+		packetConns []net.PacketConn
+		listeners   []net.Listener
+	)
+
+	for fd := start; fd < start+n; fd++ {
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+
+		// net.File* дублируют дескриптор, исходный файл можно закрыть
+		if l, err := net.FileListener(f); err == nil {
+			listeners = append(listeners, l)
+			f.Close()
+			continue
+		}
+		if pc, err := net.FilePacketConn(f); err == nil {
+			packetConns = append(packetConns, pc)
+			f.Close()
+			continue
+		}
+
+		f.Close()
+		return nil, nil, fmt.Errorf("dnsserver: descriptor %d is neither a stream nor a datagram socket", fd)
+	}
+
+	return packetConns, listeners, nil
+}
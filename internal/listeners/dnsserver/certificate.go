@@ -0,0 +1,113 @@
+package dnsserver
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// certificateCheckInterval - как часто проверяются файлы сертификата.
+const certificateCheckInterval = time.Second
+
+// certificate отдает сертификат для TLS-рукопожатий и перечитывает его,
+// когда меняются файлы, поэтому обновленный сертификат подхватывается
+// без перезапуска.
+type certificate struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+func newCertificate(certFile, keyFile string, logger *zap.Logger) (*certificate, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("dnsserver: TLS certificate and key files are required for encrypted listeners")
+	}
+
+	c := &certificate{
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: certificateCheckInterval,
+		logger:   logger,
+	}
+
+	modTime, err := c.lastModified()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.load(modTime); err != nil {
+		return nil, err
+	}
+	c.checked = time.Now()
+
+	return c, nil
+}
+
+func (c *certificate) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: c.get,
+	}
+}
+
+func (c *certificate) get(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now := time.Now(); now.Sub(c.checked) >= c.interval {
+		c.checked = now
+		c.reload()
+	}
+
+	return c.cert, nil
+}
+
+// reload перечитывает сертификат, если файлы изменились. При ошибке
+// продолжает использоваться прежний сертификат.
+func (c *certificate) reload() {
+	modTime, err := c.lastModified()
+	if err == nil && modTime.Equal(c.modTime) {
+		return
+	}
+	if err == nil {
+		err = c.load(modTime)
+	}
+	if err != nil {
+		c.logger.Error("dnsserver: unable to reload TLS certificate", zap.Error(err))
+		return
+	}
+	c.logger.Info("dnsserver: TLS certificate is reloaded", zap.String("file", c.certFile))
+}
+
+func (c *certificate) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("dnsserver: unable to load TLS certificate: %v", err)
+	}
+	c.cert = &cert
+	c.modTime = modTime
+	return nil
+}
+
+func (c *certificate) lastModified() (time.Time, error) {
+	var last time.Time
+	for _, name := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("dnsserver: unable to stat TLS certificate: %v", err)
+		}
+		if info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return last, nil
+}
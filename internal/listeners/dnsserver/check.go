@@ -0,0 +1,118 @@
+package dnsserver
+
+import (
+	"github.com/denisdubovitskiy/blackhole/internal/policy"
+	"github.com/miekg/dns"
+)
+
+// Decision - чем сервер ответил бы на запрос.
+type Decision string
+
+const (
+	DecisionAllowed   Decision = "allowed"
+	DecisionBlocked   Decision = "blocked"
+	DecisionLocal     Decision = "local"
+	DecisionRewritten Decision = "rewritten"
+	DecisionRefused   Decision = "refused"
+)
+
+// RuleKind - вид правила, под которое попал домен.
+type RuleKind string
+
+const (
+	RuleExact      RuleKind = "exact"
+	RuleWildcard   RuleKind = "wildcard"
+	RuleRegex      RuleKind = "regex"
+	RuleGroupBlock RuleKind = "group_block"
+	RuleGroupAllow RuleKind = "group_allow"
+	RuleLocal      RuleKind = "local"
+	RuleSafeSearch RuleKind = "safe_search"
+)
+
+// Rule - правило, под которое попал домен. Origins - источники правила,
+// Active сообщает, действует ли правило для клиента сейчас: с учетом
+// источников группы, расписаний, сервисов и приостановки защиты.
+type Rule struct {
+	Kind    RuleKind
+	Rule    string
+	Origins []string
+	Active  bool
+}
+
+// Verdict - решение по домену для клиента и его причины. Target - цель
+// CNAME локальной записи или безопасного поиска.
+type Verdict struct {
+	Decision Decision
+	Client   string
+	Group    string
+	Reason   string
+	Rules    []Rule
+	Target   string
+}
+
+// Check проводит домен через те же проверки, что и обработчик запросов,
+// но не выполняет запрос и не меняет статистику. CNAME проверяются
+// только в локальных записях и безопасном поиске: ответы вышестоящих
+// серверов без запроса неизвестны. Ограничение частоты запросов
+// не проверяется: оно зависит от предыдущих запросов клиента.
+func (s *Server) Check(name string, qtype uint16, identity policy.Identity) Verdict {
+	name = dns.CanonicalName(name)
+	pol := s.policy(identity)
+	v := Verdict{Decision: DecisionAllowed, Client: pol.Client, Group: pol.Group}
+
+	if !s.accessAllowed(identity) {
+		v.Decision = DecisionRefused
+		v.Reason = "client is not allowed by the access list"
+		return v
+	}
+
+	if s.local != nil {
+		if answer, found := s.local.Lookup(name, qtype); found {
+			v.Decision = DecisionLocal
+			v.Reason = "answered by local records"
+			for _, rr := range answer {
+				v.Rules = append(v.Rules, Rule{Kind: RuleLocal, Rule: rr.String(), Active: true})
+				if cname, ok := rr.(*dns.CNAME); ok {
+					v.Target = cname.Target
+				}
+			}
+			// как и в ответе, проверяется только внешняя цель
+			if n := len(answer); n > 0 && qtype != dns.TypeCNAME {
+				if _, ok := answer[n-1].(*dns.CNAME); ok {
+					s.checkTarget(&v, pol, identity, qtype)
+				}
+			}
+			return v
+		}
+	}
+
+	d := s.decide(pol, identity, name, qtype, true)
+	v.Reason = d.reason
+	v.Rules = d.rules
+	if d.blocked {
+		v.Decision = DecisionBlocked
+		return v
+	}
+
+	if s.safeSearch != nil && !s.pausedFor(pol, identity) {
+		if target, ok := s.safeSearch.Rewrite(pol.Group, name); ok {
+			v.Decision = DecisionRewritten
+			v.Reason = "rewritten to the safe search endpoint"
+			v.Target = target
+			v.Rules = append(v.Rules, Rule{Kind: RuleSafeSearch, Rule: name, Active: true})
+			s.checkTarget(&v, pol, identity, qtype)
+		}
+	}
+	return v
+}
+
+// checkTarget проверяет цель CNAME правилами фильтрации: заблокированная
+// цель блокируется и в ответе.
+func (s *Server) checkTarget(v *Verdict, pol policy.Policy, identity policy.Identity, qtype uint16) {
+	d := s.decide(pol, identity, dns.CanonicalName(v.Target), qtype, true)
+	v.Rules = append(v.Rules, d.rules...)
+	if d.blocked {
+		v.Decision = DecisionBlocked
+		v.Reason = "CNAME target is " + d.reason
+	}
+}
@@ -0,0 +1,260 @@
+package dnsserver
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+// DefaultDoHPath - путь, по которому принимаются запросы DNS-over-HTTPS.
+const DefaultDoHPath = "/dns-query"
+
+const (
+	dnsMessageType = "application/dns-message"
+	dnsJSONType    = "application/dns-json"
+)
+
+func (s *Server) dohHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.dohPath, s.serveDoH)
+	// путь вида /dns-query/<id> передает идентификатор клиента
+	mux.HandleFunc(strings.TrimSuffix(s.dohPath, "/")+"/", s.serveDoH)
+	return mux
+}
+
+// serveDoH принимает запросы в формате RFC 8484 (GET с параметром dns
+// или POST с телом application/dns-message) и запросы JSON API
+// (GET с параметрами name и type) и передает их общему обработчику.
+func (s *Server) serveDoH(w http.ResponseWriter, r *http.Request) {
+	var (
+		req     *dns.Msg
+		asJSON  bool
+		err     error
+		query   = r.URL.Query()
+		message []byte
+	)
+
+	switch {
+	case r.Method == http.MethodGet && query.Get("dns") != "":
+		message, err = base64.RawURLEncoding.DecodeString(query.Get("dns"))
+	case r.Method == http.MethodGet && query.Get("name") != "":
+		asJSON = true
+		req, err = jsonRequest(query)
+	case r.Method == http.MethodPost:
+		if r.Header.Get("Content-Type") != dnsMessageType {
+			http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+			return
+		}
+		message, err = io.ReadAll(io.LimitReader(r.Body, dns.MaxMsgSize))
+	case r.Method == http.MethodGet:
+		http.Error(w, "dns or name parameter is required", http.StatusBadRequest)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err == nil && req == nil {
+		req = &dns.Msg{}
+		err = req.Unpack(message)
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	rw := &httpResponseWriter{
+		remote:   remoteAddr(r),
+		local:    localAddr(r),
+		clientID: strings.Trim(strings.TrimPrefix(r.URL.Path, s.dohPath), "/"),
+		tls:      r.TLS,
+	}
+	s.mux.ServeDNS(rw, req)
+	if rw.msg == nil {
+		http.Error(w, "no response", http.StatusInternalServerError)
+		return
+	}
+
+	if asJSON {
+		s.writeJSON(w, rw.msg)
+		return
+	}
+
+	packed, err := rw.msg.Pack()
+	if err != nil {
+		http.Error(w, "unable to pack a response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", dnsMessageType)
+	setCacheControl(w, rw.msg)
+	w.Write(packed)
+}
+
+// jsonRequest составляет запрос из параметров JSON API: name, type
+// (имя или номер, по умолчанию A), do и cd.
+func jsonRequest(query map[string][]string) (*dns.Msg, error) {
+	get := func(key string) string {
+		if v := query[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	qtype := dns.TypeA
+	if t := get("type"); t != "" {
+		if n, err := strconv.ParseUint(t, 10, 16); err == nil {
+			qtype = uint16(n)
+		} else if n, ok := dns.StringToType[strings.ToUpper(t)]; ok {
+			qtype = n
+		} else {
+			return nil, fmt.Errorf("unknown type %s", t)
+		}
+	}
+
+	name := get("name")
+	if _, ok := dns.IsDomainName(name); !ok {
+		return nil, fmt.Errorf("invalid name %s", name)
+	}
+
+	req := &dns.Msg{}
+	req.SetQuestion(dns.Fqdn(name), qtype)
+	req.CheckingDisabled = isTrue(get("cd"))
+	if isTrue(get("do")) {
+		req.SetEdns0(dns.DefaultMsgSize, true)
+	}
+	return req, nil
+}
+
+func isTrue(v string) bool {
+	return v == "1" || strings.EqualFold(v, "true")
+}
+
+type jsonQuestion struct {
+	Name string `json:"name"`
+	Type uint16 `json:"type"`
+}
+
+type jsonRecord struct {
+	Name string `json:"name"`
+	Type uint16 `json:"type"`
+	TTL  uint32 `json:"TTL"`
+	Data string `json:"data"`
+}
+
+type jsonResponse struct {
+	Status    int            `json:"Status"`
+	TC        bool           `json:"TC"`
+	RD        bool           `json:"RD"`
+	RA        bool           `json:"RA"`
+	AD        bool           `json:"AD"`
+	CD        bool           `json:"CD"`
+	Question  []jsonQuestion `json:"Question"`
+	Answer    []jsonRecord   `json:"Answer,omitempty"`
+	Authority []jsonRecord   `json:"Authority,omitempty"`
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, msg *dns.Msg) {
+	resp := jsonResponse{
+		Status:    msg.Rcode,
+		TC:        msg.Truncated,
+		RD:        msg.RecursionDesired,
+		RA:        msg.RecursionAvailable,
+		AD:        msg.AuthenticatedData,
+		CD:        msg.CheckingDisabled,
+		Answer:    jsonRecords(msg.Answer),
+		Authority: jsonRecords(msg.Ns),
+	}
+	for _, q := range msg.Question {
+		resp.Question = append(resp.Question, jsonQuestion{Name: q.Name, Type: q.Qtype})
+	}
+
+	w.Header().Set("Content-Type", dnsJSONType)
+	setCacheControl(w, msg)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("dnsserver: unable to write a JSON response", zap.Error(err))
+	}
+}
+
+func jsonRecords(rrs []dns.RR) []jsonRecord {
+	var records []jsonRecord
+	for _, rr := range rrs {
+		h := rr.Header()
+		records = append(records, jsonRecord{
+			Name: h.Name,
+			Type: h.Rrtype,
+			TTL:  h.Ttl,
+			// данные записи без заголовка
+			Data: strings.TrimPrefix(rr.String(), h.String()),
+		})
+	}
+	return records
+}
+
+// setCacheControl разрешает кешировать ответ на минимальный TTL его записей.
+func setCacheControl(w http.ResponseWriter, msg *dns.Msg) {
+	var (
+		min   uint32
+		found bool
+	)
+	for _, section := range [][]dns.RR{msg.Answer, msg.Ns} {
+		for _, rr := range section {
+			if rr.Header().Rrtype == dns.TypeOPT {
+				continue
+			}
+			if ttl := rr.Header().Ttl; !found || ttl < min {
+				min, found = ttl, true
+			}
+		}
+	}
+	if found {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", min))
+	}
+}
+
+func remoteAddr(r *http.Request) net.Addr {
+	addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+	if err != nil {
+		return &net.TCPAddr{}
+	}
+	return addr
+}
+
+func localAddr(r *http.Request) net.Addr {
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		return addr
+	}
+	return &net.TCPAddr{}
+}
+
+// httpResponseWriter перехватывает ответ обработчика, чтобы отдать его
+// по HTTP.
+type httpResponseWriter struct {
+	remote   net.Addr
+	local    net.Addr
+	clientID string
+	tls      *tls.ConnectionState
+	msg      *dns.Msg
+}
+
+func (w *httpResponseWriter) ConnectionState() *tls.ConnectionState { return w.tls }
+
+func (w *httpResponseWriter) LocalAddr() net.Addr       { return w.local }
+func (w *httpResponseWriter) RemoteAddr() net.Addr      { return w.remote }
+func (w *httpResponseWriter) WriteMsg(m *dns.Msg) error { w.msg = m; return nil }
+func (w *httpResponseWriter) Write([]byte) (int, error) {
+	return 0, fmt.Errorf("dnsserver: raw writes are not supported")
+}
+func (w *httpResponseWriter) Close() error        { return nil }
+func (w *httpResponseWriter) TsigStatus() error   { return nil }
+func (w *httpResponseWriter) TsigTimersOnly(bool) {}
+func (w *httpResponseWriter) Hijack()             {}
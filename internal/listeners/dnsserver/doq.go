@@ -0,0 +1,197 @@
+package dnsserver
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/quic-go/quic-go"
+	"go.uber.org/zap"
+)
+
+// коды ошибок DNS-over-QUIC (RFC 9250, раздел 4.3)
+const (
+	doqNoError       = 0x0
+	doqInternalError = 0x1
+	doqProtocolError = 0x2
+)
+
+// doqALPN - идентификатор протокола DNS-over-QUIC в TLS.
+const doqALPN = "doq"
+
+// quicListener - общее у quic.Listener и quic.EarlyListener.
+type quicListener interface {
+	Accept(ctx context.Context) (*quic.Conn, error)
+	Close() error
+	Addr() net.Addr
+}
+
+type quicService struct {
+	listener quicListener
+	conn     net.PacketConn
+	handler  dns.Handler
+	logger   *zap.Logger
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// listenQUIC принимает соединения DNS-over-QUIC на сокете pc. 0-RTT
+// разрешается только явно: данные из него могут быть повторены атакующим.
+func (s *Server) listenQUIC(pc net.PacketConn, config *tls.Config) (service, error) {
+	config = config.Clone()
+	config.MinVersion = tls.VersionTLS13
+	config.NextProtos = []string{doqALPN}
+
+	quicConfig := &quic.Config{
+		MaxIdleTimeout: 30 * time.Second,
+		Allow0RTT:      s.allow0RTT,
+	}
+
+	var (
+		l   quicListener
+		err error
+	)
+	if s.allow0RTT {
+		l, err = quic.ListenEarly(pc, config, quicConfig)
+	} else {
+		l, err = quic.Listen(pc, config, quicConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &quicService{
+		listener: l,
+		conn:     pc,
+		handler:  s.mux,
+		logger:   s.logger,
+		ctx:      ctx,
+		cancel:   cancel,
+	}, nil
+}
+
+func (q *quicService) serve() error {
+	for {
+		conn, err := q.listener.Accept(q.ctx)
+		if err != nil {
+			if errors.Is(err, quic.ErrServerClosed) || q.ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go q.serveConn(conn)
+	}
+}
+
+// serveConn обрабатывает каждый поток соединения как отдельный запрос.
+func (q *quicService) serveConn(conn *quic.Conn) {
+	for {
+		stream, err := conn.AcceptStream(q.ctx)
+		if err != nil {
+			return
+		}
+		go q.serveStream(conn, stream)
+	}
+}
+
+func (q *quicService) serveStream(conn *quic.Conn, stream *quic.Stream) {
+	_ = stream.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, err := readQUICMsg(stream)
+	if err != nil {
+		q.logger.Debug(
+			"dnsserver: invalid DNS-over-QUIC request",
+			zap.String("client", conn.RemoteAddr().String()),
+			zap.Error(err),
+		)
+		_ = conn.CloseWithError(doqProtocolError, err.Error())
+		return
+	}
+
+	q.handler.ServeDNS(&quicResponseWriter{conn: conn, stream: stream}, req)
+}
+
+// readQUICMsg читает запрос с двухбайтовым префиксом длины. Идентификатор
+// запроса по RFC 9250 должен быть нулевым.
+func readQUICMsg(r io.Reader) (*dns.Msg, error) {
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, fmt.Errorf("unable to read a message length: %v", err)
+	}
+
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf("unable to read a message: %v", err)
+	}
+
+	req := &dns.Msg{}
+	if err := req.Unpack(buf); err != nil {
+		return nil, err
+	}
+	if req.Id != 0 {
+		return nil, fmt.Errorf("message id must be zero, got %d", req.Id)
+	}
+	return req, nil
+}
+
+func (q *quicService) shutdown(context.Context) {
+	q.cancel()
+	// закрытие слушателя закрывает и установленные соединения
+	_ = q.listener.Close()
+	_ = q.conn.Close()
+}
+
+func (q *quicService) network() string {
+	return "quic"
+}
+
+func (q *quicService) addr() string {
+	return q.listener.Addr().String()
+}
+
+// quicResponseWriter отправляет ответ в поток запроса и закрывает его.
+type quicResponseWriter struct {
+	conn   *quic.Conn
+	stream *quic.Stream
+}
+
+func (w *quicResponseWriter) ConnectionState() *tls.ConnectionState {
+	state := w.conn.ConnectionState().TLS
+	return &state
+}
+
+func (w *quicResponseWriter) LocalAddr() net.Addr  { return w.conn.LocalAddr() }
+func (w *quicResponseWriter) RemoteAddr() net.Addr { return w.conn.RemoteAddr() }
+
+func (w *quicResponseWriter) WriteMsg(m *dns.Msg) error {
+	packed, err := m.Pack()
+	if err != nil {
+		w.stream.CancelWrite(doqInternalError)
+		return err
+	}
+	_, err = w.Write(packed)
+	return err
+}
+
+func (w *quicResponseWriter) Write(msg []byte) (int, error) {
+	buf := make([]byte, 2+len(msg))
+	binary.BigEndian.PutUint16(buf, uint16(len(msg)))
+	copy(buf[2:], msg)
+
+	if _, err := w.stream.Write(buf); err != nil {
+		return 0, err
+	}
+	return len(msg), nil
+}
+
+func (w *quicResponseWriter) Close() error        { return w.stream.Close() }
+func (w *quicResponseWriter) TsigStatus() error   { return nil }
+func (w *quicResponseWriter) TsigTimersOnly(bool) {}
+func (w *quicResponseWriter) Hijack()             {}
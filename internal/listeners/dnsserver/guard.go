@@ -0,0 +1,119 @@
+package dnsserver
+
+import (
+	"net"
+	"net/netip"
+
+	"github.com/denisdubovitskiy/blackhole/internal/policy"
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+// Access решает, обслуживать ли клиента с адресом ip. clientID - его
+// идентификатор на зашифрованных транспортах, может быть пустым.
+type Access interface {
+	Allowed(ip netip.Addr, clientID string) bool
+}
+
+// Limiter ограничивает частоту запросов одного клиента.
+type Limiter interface {
+	Allow(ip netip.Addr) bool
+}
+
+// admit решает, обслуживать ли запрос. Клиентам, которым доступ
+// запрещен, отвечает REFUSED или молча отбрасывает запрос. Превысившим
+// лимит по UDP каждый slip-й ответ отправляется пустым с флагом TC, чтобы
+// настоящий клиент повторил запрос по TCP, остальные запросы
+// отбрасываются, и ответ не усиливает атаку с подменой адреса. По
+// остальным транспортам превышение лимита получает REFUSED.
+func (s *Server) admit(w dns.ResponseWriter, req *dns.Msg, identity policy.Identity) bool {
+	ip := identity.IP
+
+	if !s.accessAllowed(identity) {
+		if s.dropDenied {
+			s.dropped.Inc()
+		} else {
+			s.refuse(w, req)
+		}
+		s.logger.Debug("client is not allowed", zap.String("client", ip.String()))
+		return false
+	}
+
+	if s.limiter == nil || s.limiter.Allow(ip) {
+		return true
+	}
+
+	switch {
+	case !isUDP(w):
+		s.refuse(w, req)
+	case s.slip > 0 && s.slipped.Inc()%int64(s.slip) == 0:
+		resp := &dns.Msg{}
+		resp.SetReply(req)
+		resp.Truncated = true
+		s.writeMsg(w, resp)
+		s.truncated.Inc()
+	default:
+		s.dropped.Inc()
+	}
+	s.logger.Debug("client is rate limited", zap.String("client", ip.String()))
+
+	return false
+}
+
+// accessAllowed сообщает, разрешен ли клиенту доступ списком доступа.
+func (s *Server) accessAllowed(identity policy.Identity) bool {
+	return s.access == nil || s.access.Allowed(identity.IP, identity.ID)
+}
+
+func (s *Server) refuse(w dns.ResponseWriter, req *dns.Msg) {
+	resp := &dns.Msg{}
+	resp.SetRcode(req, dns.RcodeRefused)
+	s.writeMsg(w, resp)
+	s.refused.Inc()
+}
+
+// isUDP сообщает, пришел ли запрос по обычному UDP. У DNS-over-QUIC
+// адрес тоже UDP, но он подтвержден рукопожатием.
+func isUDP(w dns.ResponseWriter) bool {
+	if _, ok := w.(*quicResponseWriter); ok {
+		return false
+	}
+	_, ok := w.RemoteAddr().(*net.UDPAddr)
+	return ok
+}
+
+// clientID возвращает идентификатор клиента зашифрованного транспорта:
+// токен из пути DoH или первую метку имени из SNI вида
+// <id>.<clientIDDomain>.
+func (s *Server) clientID(w dns.ResponseWriter) string {
+	if hw, ok := w.(*httpResponseWriter); ok && hw.clientID != "" {
+		return hw.clientID
+	}
+
+	stater, ok := w.(dns.ConnectionStater)
+	if !ok || s.clientIDDomain == "." {
+		return ""
+	}
+	state := stater.ConnectionState()
+	if state == nil {
+		return ""
+	}
+
+	name := dns.CanonicalName(state.ServerName)
+	if !dns.IsSubDomain(s.clientIDDomain, name) || name == s.clientIDDomain {
+		return ""
+	}
+	return dns.SplitDomainName(name)[0]
+}
+
+func clientIP(addr net.Addr) netip.Addr {
+	var ip net.IP
+	switch addr := addr.(type) {
+	case *net.UDPAddr:
+		ip = addr.IP
+	case *net.TCPAddr:
+		ip = addr.IP
+	}
+	parsed, _ := netip.AddrFromSlice(ip)
+	return parsed.Unmap()
+}
@@ -0,0 +1,273 @@
+package dnsserver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+// service - транспорт, принимающий запросы на открытом сокете.
+type service interface {
+	serve() error
+	shutdown(ctx context.Context)
+	network() string
+	addr() string
+}
+
+type dnsService struct {
+	srv *dns.Server
+}
+
+func (d dnsService) serve() error {
+	return d.srv.ActivateAndServe()
+}
+
+func (d dnsService) shutdown(ctx context.Context) {
+	_ = d.srv.ShutdownContext(ctx)
+}
+
+func (d dnsService) network() string {
+	return d.srv.Net
+}
+
+func (d dnsService) addr() string {
+	if d.srv.PacketConn != nil {
+		return d.srv.PacketConn.LocalAddr().String()
+	}
+	return d.srv.Listener.Addr().String()
+}
+
+type httpService struct {
+	srv      *http.Server
+	listener net.Listener
+}
+
+func (h httpService) serve() error {
+	// сертификат берется из TLSConfig.GetCertificate
+	err := h.srv.ServeTLS(h.listener, "", "")
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (h httpService) shutdown(ctx context.Context) {
+	_ = h.srv.Shutdown(ctx)
+}
+
+func (h httpService) network() string {
+	return "https"
+}
+
+func (h httpService) addr() string {
+	return h.listener.Addr().String()
+}
+
+// Run открывает все сокеты и обслуживает запросы до отмены контекста.
+// Ошибка открытия любого из сокетов возвращается сразу, ошибка одного
+// из работающих серверов останавливает остальные.
+func (s *Server) Run(ctx context.Context) error {
+	services, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	return serve(ctx, services)
+}
+
+func serve(ctx context.Context, services []service) error {
+	errch := make(chan error, len(services))
+
+	for _, svc := range services {
+		svc := svc
+		go func() {
+			if err := svc.serve(); err != nil {
+				errch <- fmt.Errorf("unable to serve %s on %s: %v", svc.network(), svc.addr(), err)
+			}
+		}()
+	}
+
+	select {
+	case err := <-errch:
+		shutdown(services)
+		return err
+	case <-ctx.Done():
+		shutdown(services)
+		return nil
+	}
+}
+
+// listener открывает сокет, который затем обслуживается сервисом.
+type listener struct {
+	network string
+	addrs   []string
+	open    func(addr string) (service, error)
+}
+
+// listen открывает сокеты на настроенных адресах и добавляет к ним
+// заранее открытые. Если открыть хотя бы один не удалось, уже открытые
+// закрываются.
+func (s *Server) listen() ([]service, error) {
+	var services []service
+
+	var tlsConfig *tls.Config
+	if len(s.tlsAddrs) > 0 || len(s.httpsAddrs) > 0 || len(s.quicAddrs) > 0 {
+		cert, err := newCertificate(s.certFile, s.keyFile, s.logger)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = cert.tlsConfig()
+	}
+
+	listeners := []listener{
+		{network: "udp", addrs: s.udpAddrs, open: func(addr string) (service, error) {
+			pc, err := net.ListenPacket("udp", addr)
+			if err != nil {
+				return nil, err
+			}
+			return s.newUDPService(pc), nil
+		}},
+		{network: "tcp", addrs: s.tcpAddrs, open: func(addr string) (service, error) {
+			l, err := net.Listen("tcp", addr)
+			if err != nil {
+				return nil, err
+			}
+			return s.newTCPService(l), nil
+		}},
+		{network: "tls", addrs: s.tlsAddrs, open: func(addr string) (service, error) {
+			l, err := net.Listen("tcp", addr)
+			if err != nil {
+				return nil, err
+			}
+			return s.newTLSService(tls.NewListener(l, tlsConfig)), nil
+		}},
+		{network: "https", addrs: s.httpsAddrs, open: func(addr string) (service, error) {
+			l, err := net.Listen("tcp", addr)
+			if err != nil {
+				return nil, err
+			}
+			return s.newHTTPSService(l, tlsConfig), nil
+		}},
+		{network: "quic", addrs: s.quicAddrs, open: func(addr string) (service, error) {
+			pc, err := net.ListenPacket("udp", addr)
+			if err != nil {
+				return nil, err
+			}
+			svc, err := s.listenQUIC(pc, tlsConfig)
+			if err != nil {
+				pc.Close()
+				return nil, err
+			}
+			return svc, nil
+		}},
+	}
+
+	for _, l := range listeners {
+		for _, addr := range l.addrs {
+			svc, err := l.open(addr)
+			if err != nil {
+				closeAll(services)
+				return nil, fmt.Errorf("dnsserver: unable to listen on %s %s: %v", l.network, addr, err)
+			}
+			services = append(services, svc)
+		}
+	}
+
+	for _, pc := range s.packetConns {
+		services = append(services, s.newUDPService(pc))
+	}
+	for _, l := range s.listeners {
+		services = append(services, s.newTCPService(l))
+	}
+
+	if len(services) == 0 {
+		return nil, fmt.Errorf("dnsserver: no listen addresses configured")
+	}
+
+	for _, svc := range services {
+		s.logger.Info("dnsserver: listening", zap.String("net", svc.network()), zap.String("addr", svc.addr()))
+	}
+
+	return services, nil
+}
+
+func (s *Server) newUDPService(pc net.PacketConn) service {
+	return dnsService{srv: &dns.Server{
+		PacketConn:   pc,
+		Net:          "udp",
+		Handler:      s.mux,
+		UDPSize:      65535,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+	}}
+}
+
+func (s *Server) newTCPService(l net.Listener) service {
+	return dnsService{srv: &dns.Server{
+		Listener:     l,
+		Net:          "tcp",
+		Handler:      s.mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+	}}
+}
+
+func (s *Server) newTLSService(l net.Listener) service {
+	return dnsService{srv: &dns.Server{
+		Listener:     l,
+		Net:          "tcp-tls",
+		Handler:      s.mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+	}}
+}
+
+func (s *Server) newHTTPSService(l net.Listener, config *tls.Config) service {
+	return httpService{
+		srv: &http.Server{
+			Handler:           s.dohHandler(),
+			TLSConfig:         config,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       5 * time.Second,
+			WriteTimeout:      5 * time.Second,
+			IdleTimeout:       time.Minute,
+			ErrorLog:          zap.NewStdLog(s.logger),
+		},
+		listener: l,
+	}
+}
+
+// closeAll закрывает сокеты сервисов, которые еще не запущены.
+func closeAll(services []service) {
+	for _, svc := range services {
+		switch svc := svc.(type) {
+		case dnsService:
+			if svc.srv.PacketConn != nil {
+				svc.srv.PacketConn.Close()
+			}
+			if svc.srv.Listener != nil {
+				svc.srv.Listener.Close()
+			}
+		case httpService:
+			svc.listener.Close()
+		case *quicService:
+			svc.shutdown(context.Background())
+		}
+	}
+}
+
+func shutdown(services []service) {
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+
+	for _, svc := range services {
+		svc.shutdown(shutdownCtx)
+	}
+}
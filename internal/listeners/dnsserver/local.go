@@ -0,0 +1,73 @@
+package dnsserver
+
+import (
+	"github.com/denisdubovitskiy/blackhole/internal/history"
+	"github.com/denisdubovitskiy/blackhole/internal/policy"
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+// LocalRecords - записи, на которые сервер отвечает сам.
+type LocalRecords interface {
+	Lookup(name string, qtype uint16) (answer []dns.RR, found bool)
+}
+
+// answerLocal отвечает по локальным записям и сообщает, описано ли имя
+// локально. CNAME на внешнее имя раскрывается вышестоящими серверами
+// группы клиента.
+func (s *Server) answerLocal(w dns.ResponseWriter, req *dns.Msg, pol policy.Policy, identity policy.Identity) bool {
+	if s.local == nil {
+		return false
+	}
+
+	question := req.Question[0]
+	answer, found := s.local.Lookup(question.Name, question.Qtype)
+	if !found {
+		return false
+	}
+
+	resp := &dns.Msg{}
+	resp.SetReply(req)
+	resp.Authoritative = true
+	resp.RecursionAvailable = true
+	resp.Answer = answer
+
+	if n := len(answer); n > 0 && question.Qtype != dns.TypeCNAME {
+		if cname, ok := answer[n-1].(*dns.CNAME); ok {
+			s.resolveTarget(resp, cname.Target, question.Qtype, pol, identity)
+		}
+	}
+
+	s.writeMsg(w, resp)
+	s.history.Save(history.NewLocal(w.RemoteAddr(), question))
+	s.logger.Debug(
+		"domain is answered locally",
+		zap.String("client", w.RemoteAddr().String()),
+		zap.String("domain", question.Name),
+	)
+	s.localAnswers.Inc()
+	return true
+}
+
+// resolveTarget дополняет ответ записями цели CNAME. Цель проверяется
+// правилами фильтрации клиента, как обычный запрос.
+func (s *Server) resolveTarget(resp *dns.Msg, target string, qtype uint16, pol policy.Policy, identity policy.Identity) {
+	if d := s.decide(pol, identity, target, qtype, false); d.blocked {
+		s.blockAnswer(resp, target, qtype, pol.BlockMode)
+		s.logger.Debug("CNAME target is blocked", zap.String("target", target), zap.String("reason", d.reason))
+		s.blocked.Inc()
+		return
+	}
+
+	req := &dns.Msg{}
+	req.SetQuestion(target, qtype)
+
+	answer, err := s.resolverFor(pol).Lookup(req)
+	if err != nil {
+		s.logger.Error("unable to resolve a CNAME target", zap.String("target", target), zap.Error(err))
+		resp.Rcode = dns.RcodeServerFailure
+		return
+	}
+	resp.Answer = append(resp.Answer, answer.Msg.Answer...)
+	resp.Rcode = answer.Msg.Rcode
+}
@@ -0,0 +1,257 @@
+package dnsserver
+
+import (
+	"context"
+	"net"
+	"net/netip"
+
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/denisdubovitskiy/blackhole/internal/patterns"
+	"github.com/denisdubovitskiy/blackhole/internal/policy"
+	"github.com/miekg/dns"
+)
+
+// Policies выбирает политику фильтрации для клиента.
+type Policies interface {
+	Match(id policy.Identity) policy.Policy
+}
+
+// Schedules сообщает, какие правила действуют в текущий момент.
+type Schedules interface {
+	GroupActive(group string) bool
+	Origins(rule string, origins []string) []string
+}
+
+// Services отбрасывает сервисы каталога, не заблокированные для группы.
+type Services interface {
+	Origins(group string, origins []string) []string
+}
+
+// Patterns - правила блокировки по регулярным выражениям.
+type Patterns interface {
+	Match(domain string) bool
+	// Matching возвращает все совпавшие правила, не меняя статистику.
+	Matching(domain string) []patterns.Rule
+}
+
+// Pauses сообщает, приостановлена ли защита для клиента.
+type Pauses interface {
+	Paused(client, group string, ip netip.Addr) bool
+}
+
+// macOption - EDNS-опция с MAC-адресом клиента, которую добавляют
+// пересылающие серверы, например, dnsmasq с --add-mac.
+const macOption = 65001
+
+func (s *Server) identify(w dns.ResponseWriter, req *dns.Msg) policy.Identity {
+	identity := policy.Identity{
+		IP: clientIP(w.RemoteAddr()),
+		ID: s.clientID(w),
+	}
+	// опция удаляется из запроса любого клиента
+	if mac := takeMAC(req); mac != nil && s.trustsMAC(identity.IP) {
+		identity.MAC = mac
+	}
+	return identity
+}
+
+// trustsMAC сообщает, принимается ли MAC-адрес от клиента с адресом ip.
+func (s *Server) trustsMAC(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, forwarder := range s.macForwarders {
+		if forwarder.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// takeMAC достает MAC-адрес клиента из запроса и удаляет опцию, чтобы
+// она не ушла вышестоящим серверам.
+func takeMAC(req *dns.Msg) net.HardwareAddr {
+	opt := req.IsEdns0()
+	if opt == nil {
+		return nil
+	}
+
+	var mac net.HardwareAddr
+	options := opt.Option[:0]
+	for _, o := range opt.Option {
+		if local, ok := o.(*dns.EDNS0_LOCAL); ok && local.Code == macOption {
+			if len(local.Data) == 6 {
+				mac = net.HardwareAddr(local.Data)
+			}
+			continue
+		}
+		options = append(options, o)
+	}
+	opt.Option = options
+
+	return mac
+}
+
+func (s *Server) policy(identity policy.Identity) policy.Policy {
+	if s.policies == nil {
+		return policy.Policy{}
+	}
+	return s.policies.Match(identity)
+}
+
+// pausedFor сообщает, приостановлена ли защита для клиента.
+func (s *Server) pausedFor(pol policy.Policy, identity policy.Identity) bool {
+	return s.pauses != nil && s.pauses.Paused(pol.Client, pol.Group, identity.IP)
+}
+
+// decision - результат проверки имени правилами фильтрации клиента.
+type decision struct {
+	blocked bool
+	// reason - чем имя заблокировано или почему не блокируется
+	reason string
+	// rules - совпавшие правила, собираются только для объяснения
+	rules []Rule
+}
+
+func (d *decision) block(reason string) {
+	if !d.blocked {
+		d.blocked = true
+		d.reason = reason
+	}
+}
+
+// decide проверяет имя правилами фильтрации клиента. На ней основаны
+// и ответы на запросы, и Check. Без explain проверка останавливается на
+// первом блокирующем правиле и учитывается в статистике, с explain -
+// собирает все совпавшие правила и статистику не меняет.
+func (s *Server) decide(pol policy.Policy, identity policy.Identity, name string, qtype uint16, explain bool) decision {
+	var d decision
+	active := true
+	// правила хранятся в нижнем регистре, а имя в запросе - как его
+	// написал клиент
+	name = dns.CanonicalName(name)
+
+	switch pol.BlockMode {
+	case policy.BlockNXDomain, policy.BlockRefused:
+	default:
+		// адресами отвечают только на запросы A и AAAA, остальные режимы
+		// блокируют запросы любого типа
+		if qtype != dns.TypeA && qtype != dns.TypeAAAA {
+			d.reason = "queries of this type are not blocked in this block mode"
+			active = false
+		}
+	}
+	if active && s.pausedFor(pol, identity) {
+		if !explain {
+			s.paused.Inc()
+		}
+		d.reason = "protection is paused"
+		active = false
+	}
+	if active && s.schedules != nil && !s.schedules.GroupActive(pol.Group) {
+		d.reason = "group is outside its schedule"
+		active = false
+	}
+	if !active && !explain {
+		return d
+	}
+
+	if rule, allow, ok := pol.GroupRule(name); ok {
+		kind := RuleGroupBlock
+		if allow {
+			kind = RuleGroupAllow
+		}
+		if explain {
+			d.rules = append(d.rules, Rule{Kind: kind, Rule: rule, Active: active})
+		}
+		switch {
+		case allow && active:
+			d.reason = "allowed by a group rule"
+			// разрешенный домен не блокируется остальными правилами
+			if !explain {
+				return d
+			}
+			active = false
+		case active:
+			d.block("blocked by the group rule " + rule)
+			if !explain {
+				return d
+			}
+		}
+	}
+
+	var matches []blacklist.Match
+	if explain {
+		matches = s.blacklist.Matches(context.Background(), name)
+	} else {
+		matches = s.blacklist.Lookup(context.Background(), name)
+	}
+	for _, m := range matches {
+		on := active && len(pol.Sources(s.activeOrigins(pol, m.Rule, m.Origins))) > 0
+		if explain {
+			kind := RuleExact
+			if m.Rule != name {
+				kind = RuleWildcard
+			}
+			d.rules = append(d.rules, Rule{Kind: kind, Rule: m.Rule, Origins: m.Origins, Active: on})
+		}
+		if on {
+			d.block("blocked by the blacklist rule " + m.Rule)
+			if !explain {
+				return d
+			}
+		}
+	}
+
+	// регулярные выражения медленнее, их проверяем последними
+	if s.patterns != nil {
+		origins := []string{blacklist.OriginRegex}
+		on := active && len(pol.Sources(s.activeOrigins(pol, name, origins))) > 0
+		switch {
+		case explain:
+			for _, r := range s.patterns.Matching(name) {
+				d.rules = append(d.rules, Rule{Kind: RuleRegex, Rule: r.Pattern, Origins: origins, Active: on})
+				if on {
+					d.block("blocked by the regex rule " + r.Pattern)
+				}
+			}
+		case on && s.patterns.Match(name):
+			d.block("blocked by a regex rule")
+			return d
+		}
+	}
+
+	if d.reason == "" {
+		if len(d.rules) > 0 {
+			d.reason = "matching rules do not apply to the client"
+		} else {
+			d.reason = "no rule matches"
+		}
+	}
+	return d
+}
+
+// activeOrigins оставляет источники правила rule, действующие сейчас
+// для группы клиента.
+func (s *Server) activeOrigins(pol policy.Policy, rule string, origins []string) []string {
+	if s.schedules != nil {
+		origins = s.schedules.Origins(rule, origins)
+	}
+	if s.services != nil && len(origins) > 0 {
+		origins = s.services.Origins(pol.Group, origins)
+	}
+	return origins
+}
+
+func (s *Server) resolverFor(pol policy.Policy) Resolver {
+	if r, ok := s.groupResolvers[pol.Upstreams]; ok {
+		return r
+	}
+	return s.resolver
+}
+
+// cacheKey разделяет кеш групп с разными вышестоящими серверами.
+func cacheKey(pol policy.Policy, name string) string {
+	if pol.Upstreams == "" {
+		return name
+	}
+	return pol.Upstreams + "/" + name
+}
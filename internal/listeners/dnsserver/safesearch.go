@@ -0,0 +1,58 @@
+package dnsserver
+
+import (
+	"github.com/denisdubovitskiy/blackhole/internal/history"
+	"github.com/denisdubovitskiy/blackhole/internal/policy"
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+// safeSearchTTL - TTL записи CNAME на безопасный адрес.
+const safeSearchTTL = 300
+
+// SafeSearch выбирает безопасный адрес поисковика для группы клиентов.
+type SafeSearch interface {
+	Rewrite(group, name string) (target string, ok bool)
+}
+
+// answerSafeSearch отвечает CNAME на безопасный адрес поисковика, если
+// для группы клиента включен безопасный режим. Цель раскрывается
+// вышестоящими серверами группы, как обычный запрос. На время
+// приостановки защиты запросы не переписываются.
+func (s *Server) answerSafeSearch(w dns.ResponseWriter, req *dns.Msg, pol policy.Policy, identity policy.Identity) bool {
+	if s.safeSearch == nil {
+		return false
+	}
+
+	question := req.Question[0]
+	target, ok := s.safeSearch.Rewrite(pol.Group, question.Name)
+	if !ok {
+		return false
+	}
+	if s.pausedFor(pol, identity) {
+		return false
+	}
+
+	resp := &dns.Msg{}
+	resp.SetReply(req)
+	resp.RecursionAvailable = true
+	resp.Answer = []dns.RR{&dns.CNAME{
+		Hdr:    dns.RR_Header{Name: question.Name, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: safeSearchTTL},
+		Target: target,
+	}}
+	if question.Qtype != dns.TypeCNAME {
+		s.resolveTarget(resp, target, question.Qtype, pol, identity)
+	}
+
+	s.writeMsg(w, resp)
+	s.history.Save(history.NewRewritten(w.RemoteAddr(), question))
+	s.logger.Debug(
+		"domain is rewritten to safe search",
+		zap.String("client", w.RemoteAddr().String()),
+		zap.String("group", pol.Group),
+		zap.String("domain", question.Name),
+		zap.String("target", target),
+	)
+	s.rewritten.Inc()
+	return true
+}
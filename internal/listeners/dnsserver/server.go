@@ -3,23 +3,89 @@ package dnsserver
 import (
 	"context"
 	"expvar"
-	"fmt"
 	"net"
+	"net/netip"
 	"time"
 
 	"go.uber.org/atomic"
 
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
 	"github.com/denisdubovitskiy/blackhole/internal/cache"
+	"github.com/denisdubovitskiy/blackhole/internal/dnssec"
 	"github.com/denisdubovitskiy/blackhole/internal/history"
+	"github.com/denisdubovitskiy/blackhole/internal/policy"
 	"github.com/denisdubovitskiy/blackhole/internal/resolver"
+	"github.com/denisdubovitskiy/blackhole/internal/ttl"
 	"github.com/miekg/dns"
 	"go.uber.org/zap"
 )
 
 type Config struct {
-	BlockTTL           time.Duration
-	UpstreamDNSServers []string
-	Blacklist          Blacklist
+	// UDP и TCP - адреса, на которых принимаются запросы. Пустой список
+	// отключает транспорт.
+	UDP []string
+	TCP []string
+	// PacketConns и Listeners - заранее открытые сокеты, например,
+	// полученные через socket activation от systemd.
+	PacketConns []net.PacketConn
+	Listeners   []net.Listener
+	// TLS - адреса DNS-over-TLS (RFC 7858), HTTPS - адреса DNS-over-HTTPS
+	// (RFC 8484 и JSON API). Оба используют сертификат из CertFile и KeyFile.
+	TLS      []string
+	HTTPS    []string
+	CertFile string
+	KeyFile  string
+	// DoHPath - путь DNS-over-HTTPS, по умолчанию DefaultDoHPath.
+	DoHPath string
+	// QUIC - адреса DNS-over-QUIC (RFC 9250) с тем же сертификатом.
+	// Allow0RTT разрешает клиентам отправлять запросы в 0-RTT.
+	QUIC      []string
+	Allow0RTT bool
+
+	// Access решает, каких клиентов обслуживать. Остальным отвечает
+	// REFUSED или, если задан DropDenied, их запросы отбрасываются.
+	Access     Access
+	DropDenied bool
+	// ClientIDDomain - домен, поддомены которого в SNI зашифрованных
+	// транспортов считаются идентификаторами клиентов:
+	// <id>.<ClientIDDomain>.
+	ClientIDDomain string
+	// Limiter ограничивает частоту запросов клиентов, Slip задает, какой
+	// по счету ограниченный UDP-запрос получит ответ с флагом TC
+	// (0 - отбрасывать все).
+	Limiter Limiter
+	Slip    int
+
+	BlockTTL  time.Duration
+	TTL       ttl.Policy
+	Resolver  Resolver
+	Blacklist Blacklist
+	// Policies выбирает политику фильтрации для клиента, без нее все
+	// клиенты блокируют домены из всех источников.
+	Policies Policies
+	// MACForwarders - адреса и подсети пересылающих серверов, от которых
+	// принимается MAC-адрес клиента в EDNS. У остальных клиентов опция
+	// отбрасывается: иначе любой мог бы выдать себя за другого клиента.
+	MACForwarders []netip.Prefix
+	// GroupResolvers - цепочки разрешения для групп вышестоящих серверов,
+	// указанных в политиках, остальные запросы идут в Resolver.
+	GroupResolvers map[string]Resolver
+	// Services выбирает, для каких групп действуют правила сервисов
+	// каталога.
+	Services Services
+	// Patterns - правила по регулярным выражениям, проверяются, если
+	// домен не заблокирован черным списком.
+	Patterns Patterns
+	// Schedules ограничивает действие правил их расписаниями.
+	Schedules Schedules
+	// Pauses приостанавливает защиту для всех, групп или клиентов.
+	Pauses Pauses
+	// Local - локальные записи, на них сервер отвечает раньше блокировок,
+	// кеша и вышестоящих серверов.
+	Local LocalRecords
+	// SafeSearch переписывает запросы к поисковикам на их безопасные
+	// адреса.
+	SafeSearch SafeSearch
 
 	Logger  *zap.Logger
 	History History
@@ -27,30 +93,52 @@ type Config struct {
 
 func New(config Config) *Server {
 	s := &Server{
-		blockTTLSeconds: uint32(config.BlockTTL.Seconds()),
+		blockTTLSeconds: config.TTL.Blocking(config.BlockTTL),
+		ttl:             config.TTL,
 		cache:           cache.NewMemoryCache(),
-		resolver:        resolver.New(config.UpstreamDNSServers),
+		resolver:        config.Resolver,
 		blacklist:       config.Blacklist,
+		policies:        config.Policies,
+		macForwarders:   config.MACForwarders,
+		groupResolvers:  config.GroupResolvers,
+		services:        config.Services,
+		patterns:        config.Patterns,
+		schedules:       config.Schedules,
+		pauses:          config.Pauses,
+		local:           config.Local,
+		safeSearch:      config.SafeSearch,
 		logger:          config.Logger,
 		history:         config.History,
-		tcp: &dns.Server{
-			Addr:         "0.0.0.0:53",
-			Net:          "tcp",
-			ReadTimeout:  5 * time.Second,
-			WriteTimeout: 5 * time.Second,
-		},
-		udp: &dns.Server{
-			Addr:         "0.0.0.0:53",
-			Net:          "udp",
-			UDPSize:      65535,
-			ReadTimeout:  5 * time.Second,
-			WriteTimeout: 5 * time.Second,
-		},
+		udpAddrs:        config.UDP,
+		tcpAddrs:        config.TCP,
+		packetConns:     config.PacketConns,
+		listeners:       config.Listeners,
+		tlsAddrs:        config.TLS,
+		httpsAddrs:      config.HTTPS,
+		certFile:        config.CertFile,
+		keyFile:         config.KeyFile,
+		dohPath:         config.DoHPath,
+		quicAddrs:       config.QUIC,
+		allow0RTT:       config.Allow0RTT,
+		access:          config.Access,
+		dropDenied:      config.DropDenied,
+		clientIDDomain:  dns.CanonicalName(config.ClientIDDomain),
+		limiter:         config.Limiter,
+		slip:            config.Slip,
 
 		blocked:  atomic.NewInt32(0),
 		resolved: atomic.NewInt32(0),
 		failed:   atomic.NewInt32(0),
 		cached:   atomic.NewInt32(0),
+
+		refused:   atomic.NewInt64(0),
+		dropped:   atomic.NewInt64(0),
+		truncated: atomic.NewInt64(0),
+		slipped:   atomic.NewInt64(0),
+		paused:    atomic.NewInt64(0),
+
+		localAnswers: atomic.NewInt64(0),
+		rewritten:    atomic.NewInt64(0),
 	}
 	if s.logger == nil {
 		s.logger = zap.NewNop()
@@ -58,33 +146,38 @@ func New(config Config) *Server {
 	if s.history == nil {
 		s.history = history.NewNop()
 	}
+	if s.dohPath == "" {
+		s.dohPath = DefaultDoHPath
+	}
 
-	tcpHandler := dns.NewServeMux()
-	tcpHandler.HandleFunc(".", s.handler)
-	s.tcp.Handler = tcpHandler
-
-	udpHandler := dns.NewServeMux()
-	udpHandler.HandleFunc(".", s.handler)
-	s.udp.Handler = udpHandler
+	mux := dns.NewServeMux()
+	mux.HandleFunc(".", s.handler)
+	s.mux = mux
 
-	expvar.Publish("blackhole_server", expvar.Func(func() any {
-		return s.dumpStats()
-	}))
+	if expvar.Get("blackhole_server") == nil {
+		expvar.Publish("blackhole_server", expvar.Func(func() any {
+			return s.dumpStats()
+		}))
+	}
 
 	return s
 }
 
 type Blacklist interface {
-	Has(ctx context.Context, server string) bool
+	// Lookup возвращает правила, под которые попал домен, и учитывает
+	// попадание в статистике.
+	Lookup(ctx context.Context, domain string) []blacklist.Match
+	// Matches возвращает правила, под которые попал домен.
+	Matches(ctx context.Context, domain string) []blacklist.Match
 }
 
 type Resolver interface {
-	Lookup(req *dns.Msg) (*dns.Msg, error)
+	Lookup(req *dns.Msg) (*resolver.Answer, error)
 }
 
 type Cache interface {
-	Get(reqType uint16, domain string) (dns.RR, bool)
-	Set(reqType uint16, domain string, ip dns.RR)
+	Get(reqType uint16, domain string) (cache.Entry, bool)
+	Set(reqType uint16, domain string, entry cache.Entry, ttl uint32)
 }
 
 type History interface {
@@ -92,96 +185,109 @@ type History interface {
 }
 
 type Server struct {
-	tcp *dns.Server
-	udp *dns.Server
+	udpAddrs    []string
+	tcpAddrs    []string
+	packetConns []net.PacketConn
+	listeners   []net.Listener
+	tlsAddrs    []string
+	httpsAddrs  []string
+	certFile    string
+	keyFile     string
+	dohPath     string
+	quicAddrs   []string
+	allow0RTT   bool
+	mux         dns.Handler
+
+	access         Access
+	dropDenied     bool
+	clientIDDomain string
+	limiter        Limiter
+	slip           int
 
 	cache           Cache
 	resolver        Resolver
 	blacklist       Blacklist
+	policies        Policies
+	macForwarders   []netip.Prefix
+	groupResolvers  map[string]Resolver
+	services        Services
+	patterns        Patterns
+	schedules       Schedules
+	pauses          Pauses
+	local           LocalRecords
+	safeSearch      SafeSearch
 	history         History
 	blockTTLSeconds uint32
+	ttl             ttl.Policy
 	logger          *zap.Logger
 
 	blocked  *atomic.Int32
 	resolved *atomic.Int32
 	cached   *atomic.Int32
 	failed   *atomic.Int32
-}
-
-func (s *Server) Run(ctx context.Context) error {
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
 
-	errch := make(chan error, 2)
-
-	go func() {
-		if err := s.tcp.ListenAndServe(); err != nil {
-			errch <- fmt.Errorf("unable to serve tcp: %v", err)
-		}
-	}()
+	refused   *atomic.Int64
+	dropped   *atomic.Int64
+	truncated *atomic.Int64
+	slipped   *atomic.Int64
+	paused    *atomic.Int64
 
-	go func() {
-		if err := s.udp.ListenAndServe(); err != nil {
-			errch <- fmt.Errorf("unable to serve udp: %v", err)
-		}
-	}()
-
-	for {
-		select {
-		case <-errch:
-			cancel()
-			s.shutdown()
-			return nil
-		case <-ctx.Done():
-			s.shutdown()
-			return nil
-		}
-	}
-}
-
-func (s *Server) shutdown() {
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
-
-	s.tcp.ShutdownContext(shutdownCtx)
-	s.udp.ShutdownContext(shutdownCtx)
-
-	shutdownCancel()
+	localAnswers *atomic.Int64
+	rewritten    *atomic.Int64
 }
 
 func (s *Server) handler(w dns.ResponseWriter, req *dns.Msg) {
 	defer w.Close()
 
+	identity := s.identify(w, req)
+	if !s.admit(w, req, identity) {
+		return
+	}
+
 	question := req.Question[0]
+	pol := s.policy(identity)
 
-	// достаем из кеша
-	if cached, ok := s.cache.Get(question.Qtype, question.Name); ok {
-		s.respondFromCache(w, req, cached)
-		s.history.Save(history.NewCached(w.RemoteAddr(), question))
+	if s.answerLocal(w, req, pol, identity) {
+		return
+	}
+
+	// блокируем раньше кеша: у клиентов разные политики
+	if d := s.decide(pol, identity, question.Name, question.Qtype, false); d.blocked {
+		s.blockDomain(w, question, req, pol.BlockMode)
+		s.history.Save(history.NewBlocked(w.RemoteAddr(), question))
 		s.logger.Debug(
-			"domain is cached",
+			"domain is blocked",
 			zap.String("client", w.RemoteAddr().String()),
+			zap.String("group", pol.Group),
 			zap.String("domain", question.Name),
+			zap.String("reason", d.reason),
 		)
-		s.cached.Inc()
+		s.blocked.Inc()
 		return
 	}
 
-	// блокируем
-	if (question.Qtype == dns.TypeA || question.Qtype == dns.TypeAAAA) &&
-		s.blacklist.Has(context.Background(), question.Name) {
+	if s.answerSafeSearch(w, req, pol, identity) {
+		return
+	}
 
-		s.blockDomain(w, question, req)
-		s.history.Save(history.NewBlocked(w.RemoteAddr(), question))
+	// достаем из кеша
+	key := cacheKey(pol, question.Name)
+	if cached, ok := s.cache.Get(question.Qtype, key); ok {
+		s.respondFromCache(w, req, cached)
+
+		record := history.NewCached(w.RemoteAddr(), question)
+		record.Validation = cached.Validation
+		s.history.Save(record)
 		s.logger.Debug(
-			"domain is blocked",
+			"domain is cached",
 			zap.String("client", w.RemoteAddr().String()),
 			zap.String("domain", question.Name),
 		)
-		s.blocked.Inc()
+		s.cached.Inc()
 		return
 	}
 
-	resp, err := s.resolver.Lookup(req)
+	answer, err := s.resolverFor(pol).Lookup(req)
 
 	if err != nil {
 		s.fail(w, req)
@@ -196,21 +302,67 @@ func (s *Server) handler(w dns.ResponseWriter, req *dns.Msg) {
 		return
 	}
 
-	if len(resp.Answer) > 0 {
-		s.cache.Set(question.Qtype, question.Name, resp.Answer[0])
-	}
+	resp := answer.Msg
+	s.store(question.Qtype, key, cache.Entry{Msg: resp, Validation: string(answer.Validation)})
 
-	s.writeMsg(w, resp)
+	s.writeMsg(w, forClient(req, resp, answer.Validation))
 
-	s.history.Save(history.NewResolved(w.RemoteAddr(), question))
+	record := history.NewResolved(w.RemoteAddr(), question, answer.Group)
+	record.Validation = string(answer.Validation)
+	s.history.Save(record)
 	s.logger.Debug(
 		"domain is resolved",
 		zap.String("client", w.RemoteAddr().String()),
 		zap.String("domain", question.Name),
+		zap.String("group", answer.Group),
+		zap.String("upstream", answer.Upstream),
+		zap.String("validation", string(answer.Validation)),
 	)
 	s.resolved.Inc()
 }
 
+// store приводит TTL ответа к настроенным границам и кладет его в кеш.
+// Ответы с ошибками сервера не кешируются.
+func (s *Server) store(qtype uint16, key string, entry cache.Entry) {
+	resp := entry.Msg
+
+	var lifetime uint32
+
+	switch {
+	case ttl.IsNegative(resp):
+		lifetime = s.ttl.RewriteNegative(resp)
+	case resp.Rcode == dns.RcodeSuccess:
+		lifetime = s.ttl.RewritePositive(resp)
+	default:
+		return
+	}
+
+	if lifetime == 0 {
+		return
+	}
+
+	s.cache.Set(qtype, key, entry, lifetime)
+}
+
+// forClient подготавливает ответ для конкретного клиента: после проверки
+// DNSSEC бит AD выставляется только тем, кто понимает DNSSEC (DO или AD
+// в запросе), а подписи отдаются только запросившим их.
+func forClient(req, resp *dns.Msg, validation resolver.Validation) *dns.Msg {
+	do := false
+	if opt := req.IsEdns0(); opt != nil {
+		do = opt.Do()
+	}
+
+	if validation != "" {
+		resp.AuthenticatedData = validation == resolver.ValidationSecure && (do || req.AuthenticatedData)
+	}
+	if !do {
+		dnssec.Strip(resp, req.Question[0].Qtype)
+	}
+
+	return resp
+}
+
 func (s *Server) writeMsg(w dns.ResponseWriter, msg *dns.Msg) {
 	if err := w.WriteMsg(msg); err != nil {
 		s.logger.Error(
@@ -222,30 +374,47 @@ func (s *Server) writeMsg(w dns.ResponseWriter, msg *dns.Msg) {
 	}
 }
 
-func (s *Server) blockDomain(w dns.ResponseWriter, question dns.Question, req *dns.Msg) {
+func (s *Server) blockDomain(w dns.ResponseWriter, question dns.Question, req *dns.Msg, mode policy.BlockMode) {
 	response := &dns.Msg{}
 	response.SetReply(req)
-	response.Answer = append(response.Answer, s.newBlockedRecord(question.Qtype, dns.RR_Header{
-		Name:   question.Name,
-		Rrtype: question.Qtype,
-		Class:  dns.ClassINET,
-		Ttl:    s.blockTTLSeconds,
-	}))
+	s.blockAnswer(response, question.Name, question.Qtype, mode)
 
 	w.WriteMsg(response)
 }
 
-func (s *Server) newBlockedRecord(qtype uint16, head dns.RR_Header) dns.RR {
+// blockAnswer дополняет ответ блокировкой имени name в режиме mode.
+func (s *Server) blockAnswer(response *dns.Msg, name string, qtype uint16, mode policy.BlockMode) {
+	switch mode {
+	case policy.BlockNXDomain:
+		response.Rcode = dns.RcodeNameError
+	case policy.BlockRefused:
+		response.Rcode = dns.RcodeRefused
+	default:
+		response.Answer = append(response.Answer, s.newBlockedRecord(qtype, mode, dns.RR_Header{
+			Name:   name,
+			Rrtype: qtype,
+			Class:  dns.ClassINET,
+			Ttl:    s.blockTTLSeconds,
+		}))
+	}
+}
+
+func (s *Server) newBlockedRecord(qtype uint16, mode policy.BlockMode, head dns.RR_Header) dns.RR {
+	ipv4, ipv6 := blockedIPV4, blockedIPV6
+	if mode == policy.BlockNull {
+		ipv4, ipv6 = net.IPv4zero, net.IPv6zero
+	}
+
 	if qtype == dns.TypeA {
 		return &dns.A{
 			Hdr: head,
-			A:   blockedIPV4,
+			A:   ipv4,
 		}
 	}
 
 	return &dns.AAAA{
 		Hdr:  head,
-		AAAA: blockedIPV6,
+		AAAA: ipv6,
 	}
 }
 
@@ -255,12 +424,14 @@ func (s *Server) fail(w dns.ResponseWriter, req *dns.Msg) {
 	s.writeMsg(w, resp)
 }
 
-func (s *Server) respondFromCache(w dns.ResponseWriter, req *dns.Msg, cached dns.RR) {
+func (s *Server) respondFromCache(w dns.ResponseWriter, req *dns.Msg, cached cache.Entry) {
 	response := &dns.Msg{}
-	response.SetReply(req)
-	response.Answer = append(response.Answer, cached)
+	response.SetRcode(req, cached.Msg.Rcode)
+	response.RecursionAvailable = cached.Msg.RecursionAvailable
+	response.Answer = cached.Msg.Answer
+	response.Ns = cached.Msg.Ns
 
-	w.WriteMsg(response)
+	w.WriteMsg(forClient(req, response, resolver.Validation(cached.Validation)))
 }
 
 type stats struct {
@@ -268,6 +439,20 @@ type stats struct {
 	Resolved int32 `json:"resolved"`
 	Failed   int32 `json:"failed"`
 	Cached   int32 `json:"cached"`
+
+	Refused   int64 `json:"refused"`
+	Dropped   int64 `json:"dropped"`
+	Truncated int64 `json:"truncated"`
+	// Slipped - ограниченные по UDP запросы при включенном slip, каждый
+	// slip-й из них получил ответ с TC.
+	Slipped int64 `json:"slipped"`
+	// Paused - запросы, обслуженные без фильтрации на время приостановки
+	// защиты.
+	Paused int64 `json:"paused"`
+	// Local - ответы из локальных записей.
+	Local int64 `json:"local"`
+	// Rewritten - запросы, переписанные на безопасные адреса поисковиков.
+	Rewritten int64 `json:"rewritten"`
 }
 
 func (s *Server) dumpStats() stats {
@@ -276,6 +461,14 @@ func (s *Server) dumpStats() stats {
 		Resolved: s.resolved.Load(),
 		Failed:   s.failed.Load(),
 		Cached:   s.cached.Load(),
+
+		Refused:   s.refused.Load(),
+		Dropped:   s.dropped.Load(),
+		Truncated: s.truncated.Load(),
+		Slipped:   s.slipped.Load(),
+		Paused:    s.paused.Load(),
+		Local:     s.localAnswers.Load(),
+		Rewritten: s.rewritten.Load(),
 	}
 }
 
@@ -0,0 +1,1028 @@
+package dnsserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/denisdubovitskiy/blackhole/internal/patterns"
+	"github.com/denisdubovitskiy/blackhole/internal/policy"
+	"github.com/denisdubovitskiy/blackhole/internal/resolver"
+	"github.com/miekg/dns"
+	"github.com/quic-go/quic-go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeBlacklist map[string]bool
+
+func (b fakeBlacklist) Lookup(ctx context.Context, domain string) []blacklist.Match {
+	return b.Matches(ctx, domain)
+}
+
+func (b fakeBlacklist) Matches(_ context.Context, domain string) []blacklist.Match {
+	if b[domain] {
+		return []blacklist.Match{{Rule: domain, Origins: []string{"manual"}}}
+	}
+	return nil
+}
+
+type fakeResolver struct {
+	validation resolver.Validation
+}
+
+func (r fakeResolver) Lookup(req *dns.Msg) (*resolver.Answer, error) {
+	name := req.Question[0].Name
+
+	resp := &dns.Msg{}
+	resp.SetReply(req)
+	resp.Answer = append(resp.Answer,
+		&dns.A{
+			Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+			A:   net.ParseIP("192.0.2.1"),
+		},
+		&dns.RRSIG{
+			Hdr:         dns.RR_Header{Name: name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 60},
+			TypeCovered: dns.TypeA,
+			SignerName:  "example.test.",
+		},
+	)
+
+	return &resolver.Answer{Msg: resp, Group: resolver.DefaultGroup, Validation: r.validation}, nil
+}
+
+// run запускает сервер на заранее открытых сокетах.
+func run(t *testing.T, config Config) (udp, tcp string) {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	config.PacketConns = []net.PacketConn{pc}
+	config.Listeners = []net.Listener{l}
+	if config.Blacklist == nil {
+		config.Blacklist = fakeBlacklist{}
+	}
+	if config.Resolver == nil {
+		config.Resolver = fakeResolver{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errch := make(chan error, 1)
+	go func() { errch <- New(config).Run(ctx) }()
+
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-errch)
+	})
+
+	return pc.LocalAddr().String(), l.Addr().String()
+}
+
+func ask(t *testing.T, network, addr, name string, do bool) *dns.Msg {
+	t.Helper()
+
+	req := &dns.Msg{}
+	req.SetQuestion(name, dns.TypeA)
+	if do {
+		req.SetEdns0(dns.DefaultMsgSize, true)
+	}
+
+	client := &dns.Client{Net: network, Timeout: time.Second}
+
+	var (
+		resp *dns.Msg
+		err  error
+	)
+	// сервер запускается асинхронно
+	require.Eventually(t, func() bool {
+		resp, _, err = client.Exchange(req, addr)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	return resp
+}
+
+func TestServerListeners(t *testing.T) {
+	udp, tcp := run(t, Config{Blacklist: fakeBlacklist{"blocked.test.": true}})
+
+	for network, addr := range map[string]string{"udp": udp, "tcp": tcp} {
+		resp := ask(t, network, addr, "example.test.", false)
+		require.Equal(t, dns.RcodeSuccess, resp.Rcode)
+		require.Equal(t, "192.0.2.1", resp.Answer[0].(*dns.A).A.String())
+
+		resp = ask(t, network, addr, "blocked.test.", false)
+		require.Equal(t, blockedIPV4.String(), resp.Answer[0].(*dns.A).A.String())
+	}
+}
+
+func TestServerBindFailure(t *testing.T) {
+	busy, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer busy.Close()
+
+	srv := New(Config{
+		UDP:       []string{busy.LocalAddr().String()},
+		Blacklist: fakeBlacklist{},
+		Resolver:  fakeResolver{},
+	})
+
+	err = srv.Run(context.Background())
+	require.ErrorContains(t, err, busy.LocalAddr().String())
+}
+
+func TestServerWithoutListeners(t *testing.T) {
+	srv := New(Config{Blacklist: fakeBlacklist{}, Resolver: fakeResolver{}})
+
+	require.Error(t, srv.Run(context.Background()))
+}
+
+func TestServerDNSSECBits(t *testing.T) {
+	udp, _ := run(t, Config{Resolver: fakeResolver{validation: resolver.ValidationSecure}})
+
+	// подписи и бит AD получают только клиенты, понимающие DNSSEC
+	resp := ask(t, "udp", udp, "plain.test.", false)
+	require.False(t, resp.AuthenticatedData)
+	require.Len(t, resp.Answer, 1)
+
+	resp = ask(t, "udp", udp, "signed.test.", true)
+	require.True(t, resp.AuthenticatedData)
+	require.Len(t, resp.Answer, 2)
+
+	// то же для ответа из кеша
+	resp = ask(t, "udp", udp, "signed.test.", false)
+	require.False(t, resp.AuthenticatedData)
+	require.Len(t, resp.Answer, 1)
+
+	resp = ask(t, "udp", udp, "plain.test.", true)
+	require.True(t, resp.AuthenticatedData)
+	require.Len(t, resp.Answer, 2)
+}
+
+func TestActivationSockets(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+
+	packetConns, listeners, err := activationSockets("1", "2", listenFdsStart)
+	require.NoError(t, err)
+	require.Empty(t, packetConns)
+	require.Empty(t, listeners)
+
+	_, _, err = activationSockets(pid, "two", listenFdsStart)
+	require.Error(t, err)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	lf, err := l.(*net.TCPListener).File()
+	require.NoError(t, err)
+	defer lf.Close()
+
+	// activationSockets закрывает переданный дескриптор, отдаем ему копию
+	fd, err := syscall.Dup(int(lf.Fd()))
+	require.NoError(t, err)
+
+	_, listeners, err = activationSockets(pid, "1", fd)
+	require.NoError(t, err)
+	require.Len(t, listeners, 1)
+	require.Equal(t, l.Addr().String(), listeners[0].Addr().String())
+	listeners[0].Close()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+	pf, err := pc.(*net.UDPConn).File()
+	require.NoError(t, err)
+	defer pf.Close()
+
+	fd, err = syscall.Dup(int(pf.Fd()))
+	require.NoError(t, err)
+
+	packetConns, _, err = activationSockets(pid, "1", fd)
+	require.NoError(t, err)
+	require.Len(t, packetConns, 1)
+	require.Equal(t, pc.LocalAddr().String(), packetConns[0].LocalAddr().String())
+	packetConns[0].Close()
+}
+
+// writeCertificate выпускает самоподписанный сертификат для 127.0.0.1
+// и записывает его в файлы.
+func writeCertificate(t *testing.T, dir string, serial int64) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "blackhole.test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+// runEncrypted запускает DoT, DoH и DoQ на свободных портах и возвращает
+// их адреса.
+func runEncrypted(t *testing.T, config Config) map[string]string {
+	t.Helper()
+
+	certFile, keyFile := writeCertificate(t, t.TempDir(), 1)
+	config.TLS = []string{"127.0.0.1:0"}
+	config.HTTPS = []string{"127.0.0.1:0"}
+	config.QUIC = []string{"127.0.0.1:0"}
+	config.CertFile = certFile
+	config.KeyFile = keyFile
+	config.Blacklist = fakeBlacklist{"blocked.test.": true}
+	config.Resolver = fakeResolver{}
+	s := New(config)
+
+	services, err := s.listen()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errch := make(chan error, 1)
+	go func() { errch <- serve(ctx, services) }()
+
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-errch)
+	})
+
+	addrs := make(map[string]string)
+	for _, svc := range services {
+		addrs[svc.network()] = svc.addr()
+	}
+	return addrs
+}
+
+func TestServerDoT(t *testing.T) {
+	dot := runEncrypted(t, Config{})["tcp-tls"]
+
+	req := &dns.Msg{}
+	req.SetQuestion("example.test.", dns.TypeA)
+
+	client := &dns.Client{Net: "tcp-tls", Timeout: time.Second, TLSConfig: &tls.Config{InsecureSkipVerify: true}}
+
+	var (
+		resp *dns.Msg
+		err  error
+	)
+	require.Eventually(t, func() bool {
+		resp, _, err = client.Exchange(req, dot)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	require.Equal(t, "192.0.2.1", resp.Answer[0].(*dns.A).A.String())
+}
+
+func TestServerDoH(t *testing.T) {
+	doh := runEncrypted(t, Config{})["https"]
+
+	client := &http.Client{
+		Timeout:   time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	endpoint := "https://" + doh + DefaultDoHPath
+
+	get := func(url string) *http.Response {
+		var (
+			resp *http.Response
+			err  error
+		)
+		require.Eventually(t, func() bool {
+			resp, err = client.Get(url)
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	unpack := func(resp *http.Response) *dns.Msg {
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, dnsMessageType, resp.Header.Get("Content-Type"))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		msg := &dns.Msg{}
+		require.NoError(t, msg.Unpack(body))
+		return msg
+	}
+
+	req := &dns.Msg{}
+	req.SetQuestion("example.test.", dns.TypeA)
+	packed, err := req.Pack()
+	require.NoError(t, err)
+
+	t.Run("get", func(t *testing.T) {
+		resp := get(endpoint + "?dns=" + base64.RawURLEncoding.EncodeToString(packed))
+		require.Equal(t, "max-age=60", resp.Header.Get("Cache-Control"))
+
+		msg := unpack(resp)
+		require.Equal(t, "192.0.2.1", msg.Answer[0].(*dns.A).A.String())
+	})
+
+	t.Run("post", func(t *testing.T) {
+		resp, err := client.Post(endpoint, dnsMessageType, bytes.NewReader(packed))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		msg := unpack(resp)
+		require.Equal(t, "192.0.2.1", msg.Answer[0].(*dns.A).A.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		resp := get(endpoint + "?name=blocked.test&type=A")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, dnsJSONType, resp.Header.Get("Content-Type"))
+
+		var got jsonResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+		require.Equal(t, dns.RcodeSuccess, got.Status)
+		require.Equal(t, []jsonQuestion{{Name: "blocked.test.", Type: dns.TypeA}}, got.Question)
+		require.Len(t, got.Answer, 1)
+		require.Equal(t, blockedIPV4.String(), got.Answer[0].Data)
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, get(endpoint+"?dns=!!!").StatusCode)
+		require.Equal(t, http.StatusBadRequest, get(endpoint+"?name=example.test&type=BOGUS").StatusCode)
+	})
+}
+
+func TestServerDoQ(t *testing.T) {
+	doq := runEncrypted(t, Config{})["quic"]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := quic.DialAddr(ctx, doq, &tls.Config{InsecureSkipVerify: true, NextProtos: []string{doqALPN}}, nil)
+	require.NoError(t, err)
+	defer conn.CloseWithError(doqNoError, "")
+
+	exchange := func(name string, id uint16) (*dns.Msg, error) {
+		req := &dns.Msg{}
+		req.SetQuestion(name, dns.TypeA)
+		req.Id = id
+		packed, err := req.Pack()
+		require.NoError(t, err)
+
+		stream, err := conn.OpenStreamSync(ctx)
+		if err != nil {
+			return nil, err
+		}
+		buf := binary.BigEndian.AppendUint16(nil, uint16(len(packed)))
+		if _, err := stream.Write(append(buf, packed...)); err != nil {
+			return nil, err
+		}
+		// клиент закрывает свою сторону потока после запроса
+		require.NoError(t, stream.Close())
+
+		return readQUICMsg(stream)
+	}
+
+	// каждый запрос идет в своем потоке одного соединения
+	for _, name := range []string{"example.test.", "blocked.test."} {
+		resp, err := exchange(name, 0)
+		require.NoError(t, err)
+		require.Len(t, resp.Answer, 1)
+	}
+
+	// ненулевой идентификатор - ошибка протокола, соединение закрывается
+	_, err = exchange("example.test.", 42)
+	require.Error(t, err)
+
+	var appErr *quic.ApplicationError
+	require.ErrorAs(t, context.Cause(conn.Context()), &appErr)
+	require.Equal(t, quic.ApplicationErrorCode(doqProtocolError), appErr.ErrorCode)
+}
+
+func TestServerClientID(t *testing.T) {
+	addrs := runEncrypted(t, Config{
+		Access:         fakeAccess{"laptop": true},
+		ClientIDDomain: "dns.example.test",
+	})
+
+	req := &dns.Msg{}
+	req.SetQuestion("example.test.", dns.TypeA)
+
+	// DoT: идентификатор в SNI
+	exchange := func(serverName string) *dns.Msg {
+		client := &dns.Client{Net: "tcp-tls", Timeout: time.Second, TLSConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			ServerName:         serverName,
+		}}
+		var (
+			resp *dns.Msg
+			err  error
+		)
+		require.Eventually(t, func() bool {
+			resp, _, err = client.Exchange(req, addrs["tcp-tls"])
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+		return resp
+	}
+	require.Equal(t, dns.RcodeSuccess, exchange("laptop.dns.example.test").Rcode)
+	require.Equal(t, dns.RcodeRefused, exchange("phone.dns.example.test").Rcode)
+	require.Equal(t, dns.RcodeRefused, exchange("dns.example.test").Rcode)
+
+	// DoH: идентификатор в пути
+	packed, err := req.Pack()
+	require.NoError(t, err)
+	client := &http.Client{
+		Timeout:   time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	get := func(path string) *dns.Msg {
+		resp, err := client.Get("https://" + addrs["https"] + path + "?dns=" + base64.RawURLEncoding.EncodeToString(packed))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		msg := &dns.Msg{}
+		require.NoError(t, msg.Unpack(body))
+		return msg
+	}
+	require.Equal(t, dns.RcodeSuccess, get(DefaultDoHPath+"/laptop").Rcode)
+	require.Equal(t, dns.RcodeRefused, get(DefaultDoHPath).Rcode)
+}
+
+func TestServerEncryptedWithoutCertificate(t *testing.T) {
+	srv := New(Config{TLS: []string{"127.0.0.1:0"}, Blacklist: fakeBlacklist{}, Resolver: fakeResolver{}})
+
+	require.Error(t, srv.Run(context.Background()))
+}
+
+func TestCertificateReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir, 1)
+
+	c, err := newCertificate(certFile, keyFile, zap.NewNop())
+	require.NoError(t, err)
+	c.interval = 0
+
+	serial := func() int64 {
+		cert, err := c.get(nil)
+		require.NoError(t, err)
+		parsed, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		return parsed.SerialNumber.Int64()
+	}
+	require.Equal(t, int64(1), serial())
+
+	writeCertificate(t, dir, 2)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, later, later))
+	require.Equal(t, int64(2), serial())
+
+	// испорченный файл не заменяет рабочий сертификат
+	require.NoError(t, os.WriteFile(keyFile, []byte("garbage"), 0o600))
+	later = later.Add(time.Minute)
+	require.NoError(t, os.Chtimes(keyFile, later, later))
+	require.Equal(t, int64(2), serial())
+}
+
+type fakeLimiter func(ip netip.Addr) bool
+
+func (f fakeLimiter) Allow(ip netip.Addr) bool {
+	return f(ip)
+}
+
+type fakeAccess map[string]bool
+
+func (a fakeAccess) Allowed(ip netip.Addr, clientID string) bool {
+	return a[ip.String()] || a[clientID]
+}
+
+func TestServerAccess(t *testing.T) {
+	udp, tcp := run(t, Config{Access: fakeAccess{}})
+
+	for network, addr := range map[string]string{"udp": udp, "tcp": tcp} {
+		resp := ask(t, network, addr, "example.test.", false)
+		require.Equal(t, dns.RcodeRefused, resp.Rcode)
+		require.Empty(t, resp.Answer)
+	}
+
+	udp, _ = run(t, Config{Access: fakeAccess{}, DropDenied: true})
+
+	req := &dns.Msg{}
+	req.SetQuestion("example.test.", dns.TypeA)
+	_, _, err := (&dns.Client{Timeout: 200 * time.Millisecond}).Exchange(req, udp)
+	require.Error(t, err)
+
+	udp, _ = run(t, Config{Access: fakeAccess{"127.0.0.1": true}})
+	resp := ask(t, "udp", udp, "example.test.", false)
+	require.Equal(t, dns.RcodeSuccess, resp.Rcode)
+}
+
+func TestServerRateLimit(t *testing.T) {
+	udp, tcp := run(t, Config{
+		Limiter: fakeLimiter(func(netip.Addr) bool { return false }),
+		Slip:    2,
+	})
+
+	// по TCP адрес клиента подтвержден, ему отказывают явно
+	resp := ask(t, "tcp", tcp, "example.test.", false)
+	require.Equal(t, dns.RcodeRefused, resp.Rcode)
+
+	// по UDP первый ограниченный запрос отбрасывается, второй получает TC
+	req := &dns.Msg{}
+	req.SetQuestion("example.test.", dns.TypeA)
+	client := &dns.Client{Net: "udp", Timeout: 200 * time.Millisecond}
+
+	_, _, err := client.Exchange(req, udp)
+	require.Error(t, err)
+
+	resp, _, err = client.Exchange(req, udp)
+	require.NoError(t, err)
+	require.True(t, resp.Truncated)
+	require.Empty(t, resp.Answer)
+}
+
+// routedResolver отвечает адресом, по которому видно, какая цепочка
+// разрешения использовалась.
+type routedResolver string
+
+func (r routedResolver) Lookup(req *dns.Msg) (*resolver.Answer, error) {
+	resp := &dns.Msg{}
+	resp.SetReply(req)
+	resp.Answer = append(resp.Answer, &dns.A{
+		Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+		A:   net.ParseIP(string(r)),
+	})
+	return &resolver.Answer{Msg: resp, Group: string(r)}, nil
+}
+
+func TestServerPolicies(t *testing.T) {
+	policies, err := policy.New(policy.Config{
+		Groups: []policy.Group{
+			{Name: "kids", BlockMode: policy.BlockNXDomain, Block: []string{"games.test"}},
+			{Name: "servers", Unfiltered: true, Upstreams: "internal"},
+		},
+		Clients: []policy.Client{
+			{Name: "tablet", Group: "kids", IDs: []string{"02:00:00:00:00:01"}},
+			{Name: "localhost", Group: "servers", IDs: []string{"127.0.0.1"}},
+		},
+	})
+	require.NoError(t, err)
+
+	config := Config{
+		Blacklist:      fakeBlacklist{"blocked.test.": true},
+		Resolver:       routedResolver("192.0.2.1"),
+		GroupResolvers: map[string]Resolver{"internal": routedResolver("192.0.2.2")},
+		Policies:       policies,
+	}
+	untrusted, _ := run(t, config)
+	config.MACForwarders = []netip.Prefix{netip.MustParsePrefix("127.0.0.1/32")}
+	udp, _ := run(t, config)
+
+	client := &dns.Client{Timeout: time.Second}
+	exchangeWith := func(addr, name string, mac net.HardwareAddr) *dns.Msg {
+		req := &dns.Msg{}
+		req.SetQuestion(name, dns.TypeA)
+		if mac != nil {
+			req.SetEdns0(dns.DefaultMsgSize, false)
+			opt := req.IsEdns0()
+			opt.Option = append(opt.Option, &dns.EDNS0_LOCAL{Code: macOption, Data: mac})
+		}
+		var resp *dns.Msg
+		require.Eventually(t, func() bool {
+			resp, _, err = client.Exchange(req, addr)
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+		return resp
+	}
+	exchange := func(name string, mac net.HardwareAddr) *dns.Msg {
+		return exchangeWith(udp, name, mac)
+	}
+
+	// сервер без фильтрации ходит в свою группу вышестоящих серверов
+	resp := exchange("blocked.test.", nil)
+	require.Equal(t, "192.0.2.2", resp.Answer[0].(*dns.A).A.String())
+
+	// клиент за dnsmasq определяется по MAC-адресу
+	mac, _ := net.ParseMAC("02:00:00:00:00:01")
+	resp = exchange("blocked.test.", mac)
+	require.Equal(t, dns.RcodeNameError, resp.Rcode)
+	resp = exchange("play.games.test.", mac)
+	require.Equal(t, dns.RcodeNameError, resp.Rcode)
+
+	// MAC-адрес принимается только от пересылающих серверов
+	resp = exchangeWith(untrusted, "blocked.test.", mac)
+	require.Equal(t, "192.0.2.2", resp.Answer[0].(*dns.A).A.String())
+
+	// кеш групп с разными вышестоящими серверами не смешивается
+	resp = exchange("example.test.", mac)
+	require.Equal(t, "192.0.2.1", resp.Answer[0].(*dns.A).A.String())
+	resp = exchange("example.test.", nil)
+	require.Equal(t, "192.0.2.2", resp.Answer[0].(*dns.A).A.String())
+}
+
+// fakeSchedules отключает выключенные группы и источники.
+type fakeSchedules struct {
+	mu  sync.Mutex
+	off map[string]bool
+}
+
+func (f *fakeSchedules) disable(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.off[name] = true
+}
+
+func (f *fakeSchedules) GroupActive(group string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !f.off[group]
+}
+
+func (f *fakeSchedules) Origins(_ string, origins []string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var active []string
+	for _, origin := range origins {
+		if !f.off[origin] {
+			active = append(active, origin)
+		}
+	}
+	return active
+}
+
+func TestServerSchedules(t *testing.T) {
+	policies, err := policy.New(policy.Config{
+		Groups:  []policy.Group{{Name: "kids", Block: []string{"games.test"}}},
+		Clients: []policy.Client{{Name: "tablet", Group: "kids", IDs: []string{"127.0.0.1"}}},
+	})
+	require.NoError(t, err)
+
+	schedules := &fakeSchedules{off: make(map[string]bool)}
+	udp, _ := run(t, Config{
+		Blacklist: fakeBlacklist{"blocked.test.": true},
+		Resolver:  routedResolver("192.0.2.1"),
+		Policies:  policies,
+		Schedules: schedules,
+	})
+
+	resp := ask(t, "udp", udp, "blocked.test.", false)
+	require.Equal(t, "127.0.0.1", resp.Answer[0].(*dns.A).A.String())
+
+	// ручные блокировки вне расписания
+	schedules.disable("manual")
+	resp = ask(t, "udp", udp, "blocked.test.", false)
+	require.Equal(t, "192.0.2.1", resp.Answer[0].(*dns.A).A.String())
+	resp = ask(t, "udp", udp, "games.test.", false)
+	require.Equal(t, "127.0.0.1", resp.Answer[0].(*dns.A).A.String())
+
+	// группа вне расписания не фильтруется совсем
+	schedules.disable("kids")
+	resp = ask(t, "udp", udp, "games.test.", false)
+	require.Equal(t, "192.0.2.1", resp.Answer[0].(*dns.A).A.String())
+}
+
+type fakePauses struct {
+	paused atomic.Bool
+}
+
+func (f *fakePauses) Paused(_, _ string, ip netip.Addr) bool {
+	return f.paused.Load() && ip.IsLoopback()
+}
+
+func TestServerPause(t *testing.T) {
+	pauses := &fakePauses{}
+	udp, _ := run(t, Config{
+		Blacklist: fakeBlacklist{"blocked.test.": true},
+		Resolver:  routedResolver("192.0.2.1"),
+		Pauses:    pauses,
+	})
+
+	resp := ask(t, "udp", udp, "blocked.test.", false)
+	require.Equal(t, "127.0.0.1", resp.Answer[0].(*dns.A).A.String())
+
+	pauses.paused.Store(true)
+	resp = ask(t, "udp", udp, "blocked.test.", false)
+	require.Equal(t, "192.0.2.1", resp.Answer[0].(*dns.A).A.String())
+}
+
+// serviceBlacklist блокирует все домены правилами сервиса tiktok.
+type serviceBlacklist struct{}
+
+func (b serviceBlacklist) Lookup(ctx context.Context, domain string) []blacklist.Match {
+	return b.Matches(ctx, domain)
+}
+
+func (serviceBlacklist) Matches(context.Context, string) []blacklist.Match {
+	return []blacklist.Match{{Rule: "*.tiktok.com.", Origins: []string{"service:tiktok"}}}
+}
+
+// fakeServices блокирует сервисы только для группы kids.
+type fakeServices struct{}
+
+func (fakeServices) Origins(group string, origins []string) []string {
+	if group == "kids" {
+		return origins
+	}
+	return nil
+}
+
+func TestServerServices(t *testing.T) {
+	policies, err := policy.New(policy.Config{
+		Groups:  []policy.Group{{Name: "kids", Sources: []string{"https://lists.test/ads"}}},
+		Clients: []policy.Client{{Name: "tablet", Group: "kids", IDs: []string{"id:tablet"}}},
+	})
+	require.NoError(t, err)
+
+	udp, _ := run(t, Config{
+		Blacklist: serviceBlacklist{},
+		Resolver:  routedResolver("192.0.2.1"),
+		Policies:  policies,
+		Services:  fakeServices{},
+	})
+
+	// сервис не заблокирован для группы default
+	resp := ask(t, "udp", udp, "www.tiktok.com.", false)
+	require.Equal(t, "192.0.2.1", resp.Answer[0].(*dns.A).A.String())
+
+	// и заблокирован для группы kids, хотя источника нет в ее списке
+	pol := policies.Match(policy.Identity{ID: "tablet"})
+	s := New(Config{Blacklist: serviceBlacklist{}, Services: fakeServices{}})
+	require.True(t, s.decide(pol, policy.Identity{}, "www.tiktok.com.", dns.TypeA, false).blocked)
+}
+
+// fakePatterns блокирует домены, начинающиеся с ad.
+type fakePatterns struct{}
+
+func (fakePatterns) Match(domain string) bool {
+	return strings.HasPrefix(domain, "ad")
+}
+
+func (p fakePatterns) Matching(domain string) []patterns.Rule {
+	if p.Match(domain) {
+		return []patterns.Rule{{ID: 1, Pattern: "^ad"}}
+	}
+	return nil
+}
+
+func TestServerPatterns(t *testing.T) {
+	policies, err := policy.New(policy.Config{
+		Groups:  []policy.Group{{Name: "staff", Sources: []string{"manual"}}},
+		Clients: []policy.Client{{Name: "office", Group: "staff", IDs: []string{"id:office"}}},
+	})
+	require.NoError(t, err)
+
+	udp, _ := run(t, Config{
+		Blacklist: fakeBlacklist{},
+		Resolver:  routedResolver("192.0.2.1"),
+		Policies:  policies,
+		Patterns:  fakePatterns{},
+	})
+
+	resp := ask(t, "udp", udp, "ad1.example.test.", false)
+	require.Equal(t, "127.0.0.1", resp.Answer[0].(*dns.A).A.String())
+	resp = ask(t, "udp", udp, "www.example.test.", false)
+	require.Equal(t, "192.0.2.1", resp.Answer[0].(*dns.A).A.String())
+
+	// группа без источника regex
+	pol := policies.Match(policy.Identity{ID: "office"})
+	s := New(Config{Blacklist: fakeBlacklist{}, Patterns: fakePatterns{}})
+	require.False(t, s.decide(pol, policy.Identity{}, "ad1.example.test.", dns.TypeA, false).blocked)
+}
+
+// fakeLocal описывает nas.home. адресом и alias.home. - CNAME
+// на внешнее имя.
+type fakeLocal struct{}
+
+func (fakeLocal) Lookup(name string, qtype uint16) ([]dns.RR, bool) {
+	switch name {
+	case "nas.home.":
+		if qtype != dns.TypeA {
+			return nil, true
+		}
+		rr, _ := dns.NewRR("nas.home. 300 IN A 10.0.0.2")
+		return []dns.RR{rr}, true
+	case "alias.home.":
+		rr, _ := dns.NewRR("alias.home. 300 IN CNAME external.test.")
+		return []dns.RR{rr}, true
+	case "tracked.home.":
+		rr, _ := dns.NewRR("tracked.home. 300 IN CNAME tracker.test.")
+		return []dns.RR{rr}, true
+	}
+	return nil, false
+}
+
+func TestServerLocalRecords(t *testing.T) {
+	udp, _ := run(t, Config{
+		Blacklist: fakeBlacklist{"nas.home.": true, "tracker.test.": true},
+		Resolver:  routedResolver("192.0.2.1"),
+		Local:     fakeLocal{},
+	})
+
+	// локальная запись сильнее блокировки
+	resp := ask(t, "udp", udp, "nas.home.", false)
+	require.True(t, resp.Authoritative)
+	require.Equal(t, "10.0.0.2", resp.Answer[0].(*dns.A).A.String())
+
+	req := &dns.Msg{}
+	req.SetQuestion("nas.home.", dns.TypeAAAA)
+	resp, err := dns.Exchange(req, udp)
+	require.NoError(t, err)
+	require.True(t, resp.Authoritative)
+	require.Equal(t, dns.RcodeSuccess, resp.Rcode)
+	require.Empty(t, resp.Answer)
+
+	// внешняя цель CNAME разрешается вышестоящими серверами
+	resp = ask(t, "udp", udp, "alias.home.", false)
+	require.Len(t, resp.Answer, 2)
+	require.Equal(t, "external.test.", resp.Answer[1].Header().Name)
+	require.Equal(t, "192.0.2.1", resp.Answer[1].(*dns.A).A.String())
+
+	// заблокированная цель блокируется и за локальным CNAME
+	resp = ask(t, "udp", udp, "tracked.home.", false)
+	require.Len(t, resp.Answer, 2)
+	require.Equal(t, "tracker.test.", resp.Answer[1].Header().Name)
+	require.Equal(t, "127.0.0.1", resp.Answer[1].(*dns.A).A.String())
+
+	// правила не зависят от регистра имени в запросе
+	resp = ask(t, "udp", udp, "Tracker.TEST.", false)
+	require.Equal(t, "127.0.0.1", resp.Answer[0].(*dns.A).A.String())
+
+	resp = ask(t, "udp", udp, "other.test.", false)
+	require.False(t, resp.Authoritative)
+}
+
+// fakeSafeSearch переписывает www.google.test. на безопасный адрес.
+type fakeSafeSearch struct{}
+
+func (fakeSafeSearch) Rewrite(_, name string) (string, bool) {
+	return "forcesafesearch.google.test.", name == "www.google.test."
+}
+
+func TestServerSafeSearch(t *testing.T) {
+	pauses := &fakePauses{}
+	udp, _ := run(t, Config{
+		Blacklist:  fakeBlacklist{},
+		Resolver:   routedResolver("192.0.2.1"),
+		Pauses:     pauses,
+		SafeSearch: fakeSafeSearch{},
+	})
+
+	// цель CNAME разрешается вышестоящими серверами
+	resp := ask(t, "udp", udp, "www.google.test.", false)
+	require.Len(t, resp.Answer, 2)
+	require.Equal(t, "forcesafesearch.google.test.", resp.Answer[0].(*dns.CNAME).Target)
+	require.Equal(t, "forcesafesearch.google.test.", resp.Answer[1].Header().Name)
+
+	resp = ask(t, "udp", udp, "other.test.", false)
+	require.Len(t, resp.Answer, 1)
+
+	// на время приостановки защиты запросы не переписываются
+	pauses.paused.Store(true)
+	resp = ask(t, "udp", udp, "www.google.test.", false)
+	require.Len(t, resp.Answer, 1)
+	require.Equal(t, "www.google.test.", resp.Answer[0].Header().Name)
+}
+
+func TestServerCheck(t *testing.T) {
+	ctx := context.Background()
+	bl := blacklist.New(16)
+	bl.AddFrom(ctx, "https://lists.test/ads", "tracker.test")
+	bl.AddFrom(ctx, "service:tiktok", "*.tiktok.test")
+	bl.Add(ctx, "school.games.test")
+
+	policies, err := policy.New(policy.Config{
+		Groups: []policy.Group{{
+			Name:    "kids",
+			Sources: []string{"manual"},
+			Block:   []string{"games.test"},
+			Allow:   []string{"school.games.test"},
+		}},
+		Clients: []policy.Client{{Name: "tablet", Group: "kids", IDs: []string{"id:tablet"}}},
+	})
+	require.NoError(t, err)
+
+	pauses := &fakePauses{}
+	s := New(Config{
+		Blacklist:  bl,
+		Policies:   policies,
+		Patterns:   fakePatterns{},
+		Pauses:     pauses,
+		Local:      fakeLocal{},
+		SafeSearch: fakeSafeSearch{},
+		Access:     fakeAccess{"127.0.0.1": true, "tablet": true},
+	})
+	everyone := policy.Identity{IP: netip.MustParseAddr("127.0.0.1")}
+	tablet := policy.Identity{ID: "tablet"}
+
+	v := s.Check("Tracker.TEST", dns.TypeA, everyone)
+	require.Equal(t, DecisionBlocked, v.Decision)
+	require.Equal(t, policy.DefaultGroup, v.Group)
+	require.Equal(t, []Rule{{Kind: RuleExact, Rule: "tracker.test.", Origins: []string{"https://lists.test/ads"}, Active: true}}, v.Rules)
+
+	// источника нет среди источников группы
+	v = s.Check("tracker.test.", dns.TypeA, tablet)
+	require.Equal(t, DecisionAllowed, v.Decision)
+	require.Equal(t, "tablet", v.Client)
+	require.False(t, v.Rules[0].Active)
+
+	v = s.Check("www.tiktok.test.", dns.TypeA, tablet)
+	require.Equal(t, DecisionBlocked, v.Decision)
+	require.Equal(t, RuleWildcard, v.Rules[0].Kind)
+	require.Equal(t, "*.tiktok.test.", v.Rules[0].Rule)
+
+	// разрешающее правило группы сильнее блокировки
+	v = s.Check("school.games.test.", dns.TypeA, tablet)
+	require.Equal(t, DecisionAllowed, v.Decision)
+	require.Equal(t, "allowed by a group rule", v.Reason)
+	require.Equal(t, RuleGroupAllow, v.Rules[0].Kind)
+	require.False(t, v.Rules[1].Active)
+
+	v = s.Check("ad1.games.test.", dns.TypeA, tablet)
+	require.Equal(t, DecisionBlocked, v.Decision)
+	require.Equal(t, []RuleKind{RuleGroupBlock, RuleRegex}, []RuleKind{v.Rules[0].Kind, v.Rules[1].Kind})
+	require.False(t, v.Rules[1].Active)
+
+	v = s.Check("tracker.test.", dns.TypeTXT, everyone)
+	require.Equal(t, DecisionAllowed, v.Decision)
+	require.Contains(t, v.Reason, "not blocked")
+
+	v = s.Check("alias.home.", dns.TypeA, everyone)
+	require.Equal(t, DecisionLocal, v.Decision)
+	require.Equal(t, "external.test.", v.Target)
+
+	// цель CNAME проверяется теми же правилами
+	v = s.Check("tracked.home.", dns.TypeA, everyone)
+	require.Equal(t, DecisionBlocked, v.Decision)
+	require.Equal(t, "tracker.test.", v.Target)
+	require.Equal(t, RuleExact, v.Rules[1].Kind)
+
+	v = s.Check("tracker.test.", dns.TypeA, policy.Identity{IP: netip.MustParseAddr("192.0.2.10")})
+	require.Equal(t, DecisionRefused, v.Decision)
+	require.Empty(t, v.Rules)
+
+	v = s.Check("www.google.test.", dns.TypeA, everyone)
+	require.Equal(t, DecisionRewritten, v.Decision)
+	require.Equal(t, "forcesafesearch.google.test.", v.Target)
+
+	pauses.paused.Store(true)
+	v = s.Check("tracker.test.", dns.TypeA, everyone)
+	require.Equal(t, DecisionAllowed, v.Decision)
+	require.Equal(t, "protection is paused", v.Reason)
+	v = s.Check("www.google.test.", dns.TypeA, everyone)
+	require.Equal(t, DecisionAllowed, v.Decision)
+	// приостановка проверяется и для типов, которые режим не блокирует
+	v = s.Check("www.google.test.", dns.TypeTXT, everyone)
+	require.Equal(t, DecisionAllowed, v.Decision)
+}
+
+func TestTakeMAC(t *testing.T) {
+	req := &dns.Msg{}
+	req.SetQuestion("example.test.", dns.TypeA)
+	require.Nil(t, takeMAC(req))
+
+	req.SetEdns0(dns.DefaultMsgSize, false)
+	opt := req.IsEdns0()
+	opt.Option = append(opt.Option,
+		&dns.EDNS0_LOCAL{Code: macOption, Data: []byte{2, 0, 0, 0, 0, 1}},
+		&dns.EDNS0_COOKIE{Code: dns.EDNS0COOKIE, Cookie: "0123456789abcdef"},
+	)
+
+	require.Equal(t, "02:00:00:00:00:01", takeMAC(req).String())
+	// опция не уходит дальше
+	require.Len(t, opt.Option, 1)
+	require.Nil(t, takeMAC(req))
+}
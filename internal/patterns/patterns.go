@@ -0,0 +1,279 @@
+package patterns
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/miekg/dns"
+	"go.uber.org/atomic"
+)
+
+const (
+	// MaxPatternLength ограничивает длину выражения.
+	MaxPatternLength = 512
+	// maxInstructions ограничивает размер скомпилированной программы:
+	// чем она больше, тем дольше проверяется каждое имя.
+	maxInstructions = 2000
+)
+
+// Compile компилирует выражение RE2. Выражения длиннее MaxPatternLength
+// и со слишком большой программой отвергаются.
+func Compile(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("patterns: pattern is empty")
+	}
+	if len(pattern) > MaxPatternLength {
+		return nil, fmt.Errorf("patterns: pattern is longer than %d bytes", MaxPatternLength)
+	}
+
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("patterns: invalid pattern %q: %v", pattern, err)
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, fmt.Errorf("patterns: invalid pattern %q: %v", pattern, err)
+	}
+	if len(prog.Inst) > maxInstructions {
+		return nil, fmt.Errorf("patterns: pattern %q is too complex: %d instructions, at most %d allowed", pattern, len(prog.Inst), maxInstructions)
+	}
+
+	return regexp.Compile(pattern)
+}
+
+// Rule - правило блокировки по регулярному выражению и его статистика
+// с момента запуска. Выражение проверяется на имени без завершающей
+// точки в нижнем регистре: ad12.example.com.
+type Rule struct {
+	ID      int64
+	Pattern string
+	Comment string
+
+	// Evaluations - сколько раз выражение проверялось, Hits - сколько
+	// раз совпало, Spent - суммарное время проверок.
+	Evaluations int64
+	Hits        int64
+	Spent       time.Duration
+	LastHit     time.Time
+}
+
+type rule struct {
+	id      int64
+	pattern string
+	comment string
+	re      *regexp.Regexp
+
+	evaluations *atomic.Int64
+	hits        *atomic.Int64
+	spent       *atomic.Int64
+	lastHit     *atomic.Int64
+}
+
+func newRule(id int64, pattern, comment string, re *regexp.Regexp) *rule {
+	return &rule{
+		id:          id,
+		pattern:     pattern,
+		comment:     comment,
+		re:          re,
+		evaluations: atomic.NewInt64(0),
+		hits:        atomic.NewInt64(0),
+		spent:       atomic.NewInt64(0),
+		lastHit:     atomic.NewInt64(0),
+	}
+}
+
+func (r *rule) snapshot() Rule {
+	rule := Rule{
+		ID:          r.id,
+		Pattern:     r.pattern,
+		Comment:     r.comment,
+		Evaluations: r.evaluations.Load(),
+		Hits:        r.hits.Load(),
+		Spent:       time.Duration(r.spent.Load()),
+	}
+	if last := r.lastHit.Load(); last != 0 {
+		rule.LastHit = time.Unix(0, last)
+	}
+	return rule
+}
+
+type Storage interface {
+	RegexRules(ctx context.Context) ([]datastore.RegexRule, error)
+	AddRegexRule(ctx context.Context, rule datastore.RegexRule) (int64, error)
+	DeleteRegexRule(ctx context.Context, id int64) error
+}
+
+// Patterns - правила блокировки по регулярным выражениям. Они медленнее
+// точных правил и шаблонов черного списка и проверяются после них.
+type Patterns struct {
+	storage Storage
+
+	// update упорядочивает изменения: запись в storage идет без mu,
+	// чтобы Match не ждал диска
+	update sync.Mutex
+	mu     sync.RWMutex
+	rules  []*rule
+}
+
+func New(storage Storage) *Patterns {
+	p := &Patterns{storage: storage}
+
+	if expvar.Get("blackhole_patterns") == nil {
+		expvar.Publish("blackhole_patterns", expvar.Func(func() any {
+			return p.List()
+		}))
+	}
+
+	return p
+}
+
+func (p *Patterns) Load(ctx context.Context) error {
+	stored, err := p.storage.RegexRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	rules := make([]*rule, 0, len(stored))
+	for _, st := range stored {
+		re, err := Compile(st.Pattern)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, newRule(st.ID, st.Pattern, st.Comment, re))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rules = rules
+	return nil
+}
+
+// List возвращает правила в порядке добавления.
+func (p *Patterns) List() []Rule {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	list := make([]Rule, len(p.rules))
+	for i, r := range p.rules {
+		list[i] = r.snapshot()
+	}
+	return list
+}
+
+func (p *Patterns) Add(ctx context.Context, pattern, comment string) (Rule, error) {
+	re, err := Compile(pattern)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	p.update.Lock()
+	defer p.update.Unlock()
+
+	if p.find(func(r *rule) bool { return r.pattern == pattern }) >= 0 {
+		return Rule{}, fmt.Errorf("patterns: pattern %q already exists", pattern)
+	}
+	id, err := p.storage.AddRegexRule(ctx, datastore.RegexRule{Pattern: pattern, Comment: comment})
+	if err != nil {
+		return Rule{}, err
+	}
+
+	r := newRule(id, pattern, comment, re)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rules = append(p.rules, r)
+	return r.snapshot(), nil
+}
+
+func (p *Patterns) Delete(ctx context.Context, id int64) error {
+	p.update.Lock()
+	defer p.update.Unlock()
+
+	if p.find(func(r *rule) bool { return r.id == id }) < 0 {
+		return fmt.Errorf("patterns: rule %d does not exist", id)
+	}
+	if err := p.storage.DeleteRegexRule(ctx, id); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// под update список правил с момента поиска не менялся
+	i := p.findLocked(func(r *rule) bool { return r.id == id })
+	p.rules = append(p.rules[:i:i], p.rules[i+1:]...)
+	return nil
+}
+
+// find возвращает индекс первого подходящего правила или -1.
+func (p *Patterns) find(match func(r *rule) bool) int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.findLocked(match)
+}
+
+func (p *Patterns) findLocked(match func(r *rule) bool) int {
+	for i, r := range p.rules {
+		if match(r) {
+			return i
+		}
+	}
+	return -1
+}
+
+func subject(domain string) string {
+	return strings.TrimSuffix(dns.CanonicalName(domain), ".")
+}
+
+// Match сообщает, совпадает ли домен хотя бы с одним правилом. Проверка
+// останавливается на первом совпадении, время и результат каждой
+// проверки учитываются в статистике правила.
+func (p *Patterns) Match(domain string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.rules) == 0 {
+		return false
+	}
+
+	name := subject(domain)
+	for _, r := range p.rules {
+		start := time.Now()
+		matched := r.re.MatchString(name)
+		r.spent.Add(int64(time.Since(start)))
+		r.evaluations.Inc()
+
+		if matched {
+			r.hits.Inc()
+			r.lastHit.Store(start.UnixNano())
+			return true
+		}
+	}
+	return false
+}
+
+// Matching возвращает все правила, совпадающие с доменом, не изменяя
+// статистику.
+func (p *Patterns) Matching(domain string) []Rule {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var matched []Rule
+	name := subject(domain)
+	for _, r := range p.rules {
+		if r.re.MatchString(name) {
+			matched = append(matched, r.snapshot())
+		}
+	}
+	return matched
+}
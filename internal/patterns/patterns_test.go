@@ -0,0 +1,127 @@
+package patterns
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStorage struct {
+	lastID int64
+	rules  []datastore.RegexRule
+}
+
+func (s *fakeStorage) RegexRules(context.Context) ([]datastore.RegexRule, error) {
+	return s.rules, nil
+}
+
+func (s *fakeStorage) AddRegexRule(_ context.Context, rule datastore.RegexRule) (int64, error) {
+	s.lastID++
+	rule.ID = s.lastID
+	s.rules = append(s.rules, rule)
+	return rule.ID, nil
+}
+
+func (s *fakeStorage) DeleteRegexRule(_ context.Context, id int64) error {
+	for i, r := range s.rules {
+		if r.ID == id {
+			s.rules = append(s.rules[:i], s.rules[i+1:]...)
+		}
+	}
+	return nil
+}
+
+func TestCompile(t *testing.T) {
+	_, err := Compile(`^ad[0-9]+\.`)
+	require.NoError(t, err)
+
+	for _, bad := range []string{
+		"",
+		`(ad`,
+		// обратные ссылки в RE2 не поддерживаются
+		`(a)\1`,
+		strings.Repeat("a", MaxPatternLength+1),
+		`(a{1,100}){1,100}`,
+	} {
+		_, err := Compile(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	ctx := context.Background()
+	storage := &fakeStorage{}
+	p := New(storage)
+
+	require.False(t, p.Match("ad1.example.com."))
+
+	ads, err := p.Add(ctx, `^ad[0-9]+\.`, "numbered ad servers")
+	require.NoError(t, err)
+	telemetry, err := p.Add(ctx, `-telemetry\.`, "")
+	require.NoError(t, err)
+	_, err = p.Add(ctx, `^ad[0-9]+\.`, "")
+	require.Error(t, err)
+
+	require.True(t, p.Match("AD12.example.com."))
+	require.True(t, p.Match("app-telemetry.example.com."))
+	require.False(t, p.Match("adserver.example.com."))
+
+	// проверка останавливается на первом совпадении
+	list := p.List()
+	require.Equal(t, int64(3), list[0].Evaluations)
+	require.Equal(t, int64(1), list[0].Hits)
+	require.False(t, list[0].LastHit.IsZero())
+	require.Equal(t, int64(2), list[1].Evaluations)
+	require.Equal(t, int64(1), list[1].Hits)
+	require.Positive(t, list[1].Spent)
+
+	// поиск всех совпадений не меняет статистику
+	matched := p.Matching("ad1.app-telemetry.example.com.")
+	require.Len(t, matched, 2)
+	require.Equal(t, list, p.List())
+
+	restored := New(storage)
+	require.NoError(t, restored.Load(ctx))
+	require.Len(t, restored.List(), 2)
+	require.Zero(t, restored.List()[0].Hits)
+
+	require.NoError(t, p.Delete(ctx, ads.ID))
+	require.Error(t, p.Delete(ctx, ads.ID))
+	require.False(t, p.Match("ad1.example.com."))
+	require.Equal(t, telemetry.ID, p.List()[0].ID)
+}
+
+// slowStorage держит запись, пока не закроют release.
+type slowStorage struct {
+	fakeStorage
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (s *slowStorage) AddRegexRule(ctx context.Context, rule datastore.RegexRule) (int64, error) {
+	close(s.writing)
+	<-s.release
+	return s.fakeStorage.AddRegexRule(ctx, rule)
+}
+
+func TestPatternsSlowStorage(t *testing.T) {
+	storage := &slowStorage{writing: make(chan struct{}), release: make(chan struct{})}
+	p := New(storage)
+
+	added := make(chan error)
+	go func() {
+		_, err := p.Add(context.Background(), `^ad[0-9]+\.`, "")
+		added <- err
+	}()
+
+	// проверка имени не ждет записи в хранилище
+	<-storage.writing
+	require.False(t, p.Match("ad1.example.com."))
+
+	close(storage.release)
+	require.NoError(t, <-added)
+	require.True(t, p.Match("ad1.example.com."))
+}
@@ -0,0 +1,300 @@
+package pause
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"net/netip"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/denisdubovitskiy/blackhole/internal/schedule"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// Scope - кого касается приостановка защиты.
+type Scope string
+
+const (
+	ScopeAll Scope = "all"
+	// ScopeGroup - группа клиентов.
+	ScopeGroup Scope = "group"
+	// ScopeClient - клиент по имени из конфигурации или по IP-адресу.
+	ScopeClient Scope = "client"
+)
+
+// resumeInterval - как часто снимаются истекшие приостановки.
+// Истекшая приостановка перестает действовать сразу, проверка лишь
+// убирает ее из хранилища.
+const resumeInterval = 10 * time.Second
+
+// Pause - приостановка защиты до момента Until.
+type Pause struct {
+	Scope  Scope
+	Target string
+	Until  time.Time
+}
+
+type key struct {
+	scope  Scope
+	target string
+}
+
+func newKey(scope Scope, target string) (key, error) {
+	switch scope {
+	case ScopeAll:
+		if target != "" {
+			return key{}, fmt.Errorf("pause: target must be empty for scope all")
+		}
+	case ScopeGroup, ScopeClient:
+		if target == "" {
+			return key{}, fmt.Errorf("pause: target is empty")
+		}
+	default:
+		return key{}, fmt.Errorf("pause: unknown scope %q", scope)
+	}
+	return key{scope: scope, target: target}, nil
+}
+
+type Storage interface {
+	Pauses(ctx context.Context) ([]datastore.Pause, error)
+	SetPause(ctx context.Context, pause datastore.Pause) error
+	DeletePause(ctx context.Context, scope, target string) error
+}
+
+// Pauses хранит приостановки защиты. Приостановки переживают перезапуск,
+// по истечении срока защита возобновляется сама.
+type Pauses struct {
+	storage Storage
+	clock   schedule.Clock
+	log     *zap.Logger
+
+	// update упорядочивает изменения: запись в storage идет без mu,
+	// чтобы Paused не ждал диска
+	update sync.Mutex
+	mu     sync.RWMutex
+	pauses map[key]time.Time
+
+	resumed *atomic.Int64
+}
+
+func New(storage Storage, clock schedule.Clock, log *zap.Logger) *Pauses {
+	if clock == nil {
+		clock = schedule.SystemClock{}
+	}
+	p := &Pauses{
+		storage: storage,
+		clock:   clock,
+		log:     log,
+		pauses:  make(map[key]time.Time),
+		resumed: atomic.NewInt64(0),
+	}
+
+	if expvar.Get("blackhole_pause") == nil {
+		expvar.Publish("blackhole_pause", expvar.Func(func() any {
+			return p.dumpStats()
+		}))
+	}
+
+	return p
+}
+
+func (p *Pauses) Load(ctx context.Context) error {
+	stored, err := p.storage.Pauses(ctx)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, s := range stored {
+		k, err := newKey(Scope(s.Scope), s.Target)
+		if err != nil {
+			return err
+		}
+		p.pauses[k] = s.Until
+	}
+	return nil
+}
+
+// Pause приостанавливает защиту на время d. Повторная приостановка
+// переносит момент возобновления.
+func (p *Pauses) Pause(ctx context.Context, scope Scope, target string, d time.Duration) (Pause, error) {
+	k, err := newKey(scope, target)
+	if err != nil {
+		return Pause{}, err
+	}
+	if d <= 0 {
+		return Pause{}, fmt.Errorf("pause: duration must be positive")
+	}
+
+	p.update.Lock()
+	defer p.update.Unlock()
+
+	pause := Pause{Scope: scope, Target: target, Until: p.clock.Now().Add(d).Truncate(time.Second)}
+	err = p.storage.SetPause(ctx, datastore.Pause{Scope: string(scope), Target: target, Until: pause.Until})
+	if err != nil {
+		return Pause{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pauses[k] = pause.Until
+	return pause, nil
+}
+
+// Resume возобновляет защиту досрочно.
+func (p *Pauses) Resume(ctx context.Context, scope Scope, target string) error {
+	k, err := newKey(scope, target)
+	if err != nil {
+		return err
+	}
+
+	p.update.Lock()
+	defer p.update.Unlock()
+
+	p.mu.RLock()
+	until, ok := p.pauses[k]
+	p.mu.RUnlock()
+
+	if !ok || !p.clock.Now().Before(until) {
+		return fmt.Errorf("pause: protection is not paused")
+	}
+	if err := p.storage.DeletePause(ctx, string(scope), target); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.pauses, k)
+	return nil
+}
+
+// List возвращает действующие приостановки.
+func (p *Pauses) List() []Pause {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	now := p.clock.Now()
+	list := make([]Pause, 0, len(p.pauses))
+	for k, until := range p.pauses {
+		if now.Before(until) {
+			list = append(list, Pause{Scope: k.scope, Target: k.target, Until: until})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Scope != list[j].Scope {
+			return list[i].Scope < list[j].Scope
+		}
+		return list[i].Target < list[j].Target
+	})
+	return list
+}
+
+// Paused сообщает, приостановлена ли защита для клиента: глобально,
+// для его группы, по имени или по адресу.
+func (p *Pauses) Paused(client, group string, ip netip.Addr) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.pauses) == 0 {
+		return false
+	}
+
+	now := p.clock.Now()
+	active := func(k key) bool {
+		until, ok := p.pauses[k]
+		return ok && now.Before(until)
+	}
+
+	if active(key{scope: ScopeAll}) || active(key{scope: ScopeGroup, target: group}) {
+		return true
+	}
+	if client != "" && active(key{scope: ScopeClient, target: client}) {
+		return true
+	}
+	return ip.IsValid() && active(key{scope: ScopeClient, target: ip.Unmap().String()})
+}
+
+// RunPeriodicResume снимает истекшие приостановки.
+func (p *Pauses) RunPeriodicResume(ctx context.Context) {
+	ticker := time.NewTicker(resumeInterval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				p.resume(ctx)
+			}
+		}
+	}()
+}
+
+func (p *Pauses) resume(ctx context.Context) {
+	p.update.Lock()
+	defer p.update.Unlock()
+
+	now := p.clock.Now()
+	var expired []key
+	p.mu.RLock()
+	for k, until := range p.pauses {
+		if !now.Before(until) {
+			expired = append(expired, k)
+		}
+	}
+	p.mu.RUnlock()
+
+	for _, k := range expired {
+		if err := p.storage.DeletePause(ctx, string(k.scope), k.target); err != nil {
+			p.log.Error("pause: unable to resume protection", zap.Error(err))
+			continue
+		}
+		p.mu.Lock()
+		delete(p.pauses, k)
+		p.mu.Unlock()
+		p.resumed.Inc()
+		p.log.Info("pause: protection resumed",
+			zap.String("scope", string(k.scope)),
+			zap.String("target", k.target),
+		)
+	}
+}
+
+type pauseStats struct {
+	Scope     string    `json:"scope"`
+	Target    string    `json:"target,omitempty"`
+	Until     time.Time `json:"until"`
+	Remaining string    `json:"remaining"`
+}
+
+type stats struct {
+	Paused  []pauseStats `json:"paused"`
+	Resumed int64        `json:"resumed"`
+}
+
+func (p *Pauses) dumpStats() stats {
+	now := p.clock.Now()
+	list := p.List()
+
+	s := stats{Paused: make([]pauseStats, len(list)), Resumed: p.resumed.Load()}
+	for i, pause := range list {
+		s.Paused[i] = pauseStats{
+			Scope:     string(pause.Scope),
+			Target:    pause.Target,
+			Until:     pause.Until,
+			Remaining: pause.Until.Sub(now).Truncate(time.Second).String(),
+		}
+	}
+	return s
+}
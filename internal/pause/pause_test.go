@@ -0,0 +1,128 @@
+package pause
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+type fakeStorage map[[2]string]time.Time
+
+func (s fakeStorage) Pauses(context.Context) ([]datastore.Pause, error) {
+	var list []datastore.Pause
+	for k, until := range s {
+		list = append(list, datastore.Pause{Scope: k[0], Target: k[1], Until: until})
+	}
+	return list, nil
+}
+
+func (s fakeStorage) SetPause(_ context.Context, p datastore.Pause) error {
+	s[[2]string{p.Scope, p.Target}] = p.Until
+	return nil
+}
+
+func (s fakeStorage) DeletePause(_ context.Context, scope, target string) error {
+	delete(s, [2]string{scope, target})
+	return nil
+}
+
+func TestPauses(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	storage := fakeStorage{}
+	laptop := netip.MustParseAddr("10.0.0.5")
+	p := New(storage, clock, zap.NewNop())
+
+	require.False(t, p.Paused("", "default", laptop))
+
+	_, err := p.Pause(ctx, ScopeGroup, "", time.Minute)
+	require.Error(t, err)
+	_, err = p.Pause(ctx, ScopeAll, "kids", time.Minute)
+	require.Error(t, err)
+	_, err = p.Pause(ctx, ScopeClient, "laptop", 0)
+	require.Error(t, err)
+
+	// клиент по адресу
+	pause, err := p.Pause(ctx, ScopeClient, "10.0.0.5", 30*time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, clock.now.Add(30*time.Minute), pause.Until)
+	require.True(t, p.Paused("", "default", laptop))
+	require.False(t, p.Paused("", "default", netip.MustParseAddr("10.0.0.6")))
+
+	// группа
+	_, err = p.Pause(ctx, ScopeGroup, "kids", time.Hour)
+	require.NoError(t, err)
+	require.True(t, p.Paused("tablet", "kids", netip.MustParseAddr("10.0.0.7")))
+	require.Len(t, p.List(), 2)
+
+	// перезапуск не теряет момент возобновления
+	restored := New(storage, clock, zap.NewNop())
+	require.NoError(t, restored.Load(ctx))
+	require.Equal(t, p.List(), restored.List())
+
+	// по истечении срока защита возобновляется сама
+	clock.now = clock.now.Add(45 * time.Minute)
+	require.False(t, p.Paused("", "default", laptop))
+	require.Equal(t, []Pause{{Scope: ScopeGroup, Target: "kids", Until: pause.Until.Add(30 * time.Minute)}}, p.List())
+	require.Len(t, storage, 2)
+	p.resume(ctx)
+	require.Len(t, storage, 1)
+	require.Equal(t, int64(1), p.dumpStats().Resumed)
+
+	require.Error(t, p.Resume(ctx, ScopeClient, "10.0.0.5"))
+	require.NoError(t, p.Resume(ctx, ScopeGroup, "kids"))
+	require.False(t, p.Paused("tablet", "kids", netip.Addr{}))
+	require.Empty(t, storage)
+
+	// глобальная приостановка
+	_, err = p.Pause(ctx, ScopeAll, "", time.Minute)
+	require.NoError(t, err)
+	require.True(t, p.Paused("", "", netip.Addr{}))
+	require.Equal(t, "1m0s", p.dumpStats().Paused[0].Remaining)
+}
+
+// slowStorage держит запись, пока не закроют release.
+type slowStorage struct {
+	fakeStorage
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (s slowStorage) SetPause(ctx context.Context, p datastore.Pause) error {
+	close(s.writing)
+	<-s.release
+	return s.fakeStorage.SetPause(ctx, p)
+}
+
+func TestPausesSlowStorage(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	storage := slowStorage{fakeStorage: fakeStorage{}, writing: make(chan struct{}), release: make(chan struct{})}
+	p := New(storage, clock, zap.NewNop())
+
+	paused := make(chan error)
+	go func() {
+		_, err := p.Pause(context.Background(), ScopeAll, "", time.Minute)
+		paused <- err
+	}()
+
+	// проверка приостановки не ждет записи в хранилище
+	<-storage.writing
+	require.False(t, p.Paused("", "default", netip.Addr{}))
+
+	close(storage.release)
+	require.NoError(t, <-paused)
+	require.True(t, p.Paused("", "default", netip.Addr{}))
+}
@@ -0,0 +1,363 @@
+package policy
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/miekg/dns"
+)
+
+// DefaultGroup - группа клиентов, не подошедших ни под одно описание.
+const DefaultGroup = "default"
+
+// BlockMode - чем отвечать на запрос заблокированного домена.
+type BlockMode string
+
+const (
+	// BlockLocalhost отвечает адресами 127.0.0.1 и ::1.
+	BlockLocalhost BlockMode = "localhost"
+	// BlockNull отвечает адресами 0.0.0.0 и ::.
+	BlockNull     BlockMode = "null"
+	BlockNXDomain BlockMode = "nxdomain"
+	BlockRefused  BlockMode = "refused"
+)
+
+// Group - политика фильтрации для группы клиентов.
+type Group struct {
+	Name string
+	// Sources - источники блокировок, действующие для группы: URL списков,
+	// blacklist.OriginManual и blacklist.OriginRegex. Пустой список
+	// включает все источники.
+	// Сервисы каталога выбираются для групп отдельно и от Sources
+	// не зависят.
+	Sources []string
+	// Block и Allow - ручные правила группы: домены (вместе с поддоменами),
+	// которые блокируются или никогда не блокируются независимо
+	// от источников. Allow сильнее Block.
+	Block []string
+	Allow []string
+	// BlockMode по умолчанию BlockLocalhost.
+	BlockMode BlockMode
+	// Unfiltered отключает блокировку по источникам, ручные правила
+	// группы и сервисы каталога продолжают действовать. Чтобы сервис,
+	// заблокированный глобально, не блокировался для группы, его нужно
+	// разблокировать для группы.
+	Unfiltered bool
+	// Upstreams - группа вышестоящих серверов, пустая - выбор по правилам
+	// пересылки.
+	Upstreams string
+}
+
+// Client описывает клиента и его группу.
+type Client struct {
+	Name  string
+	Group string
+	// IDs - адреса, подсети CIDR, MAC-адреса (EDNS-опция 65001 от
+	// доверенных пересылающих серверов) и идентификаторы зашифрованных
+	// транспортов с префиксом id:.
+	IDs []string
+}
+
+type Config struct {
+	Groups  []Group
+	Clients []Client
+}
+
+// Identity - сведения о клиенте, известные по запросу.
+type Identity struct {
+	IP  netip.Addr
+	MAC net.HardwareAddr
+	// ID - идентификатор клиента DoH, DoT или DoQ.
+	ID string
+}
+
+// Policy - политика, действующая для запроса. Нулевое значение блокирует
+// домены из всех источников.
+type Policy struct {
+	Client    string
+	Group     string
+	BlockMode BlockMode
+	Upstreams string
+
+	g *group
+}
+
+// Blocked сообщает, блокируется ли домен, встретившийся в источниках
+// origins.
+func (p Policy) Blocked(domain string, origins []string) bool {
+	if p.g == nil {
+		return len(origins) > 0
+	}
+	return p.g.blocked(dns.CanonicalName(domain), origins)
+}
+
+// GroupRule возвращает ручное правило группы, под которое попал домен:
+// разрешающее (allow) или блокирующее.
+func (p Policy) GroupRule(domain string) (rule string, allow, ok bool) {
+	if p.g == nil {
+		return "", false, false
+	}
+	domain = dns.CanonicalName(domain)
+	if rule, ok := findSuffix(p.g.allow, domain); ok {
+		return rule, true, true
+	}
+	if rule, ok := findSuffix(p.g.block, domain); ok {
+		return rule, false, true
+	}
+	return "", false, false
+}
+
+// Sources оставляет источники, по которым группа блокирует домены.
+func (p Policy) Sources(origins []string) []string {
+	if p.g == nil || p.g.sources == nil && !p.g.Unfiltered {
+		return origins
+	}
+
+	var active []string
+	for _, origin := range origins {
+		if p.g.active(origin) {
+			active = append(active, origin)
+		}
+	}
+	return active
+}
+
+type group struct {
+	Group
+	sources map[string]bool
+	block   map[string]bool
+	allow   map[string]bool
+}
+
+func newGroup(config Group) (*group, error) {
+	switch config.BlockMode {
+	case "":
+		config.BlockMode = BlockLocalhost
+	case BlockLocalhost, BlockNull, BlockNXDomain, BlockRefused:
+	default:
+		return nil, fmt.Errorf("policy: unknown block mode %q in group %s", config.BlockMode, config.Name)
+	}
+
+	g := &group{
+		Group: config,
+		block: domainSet(config.Block),
+		allow: domainSet(config.Allow),
+	}
+	if len(config.Sources) > 0 {
+		g.sources = make(map[string]bool, len(config.Sources))
+		for _, s := range config.Sources {
+			g.sources[s] = true
+		}
+	}
+	return g, nil
+}
+
+func domainSet(domains []string) map[string]bool {
+	set := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		set[dns.CanonicalName(d)] = true
+	}
+	return set
+}
+
+func (g *group) blocked(domain string, origins []string) bool {
+	if matchSuffix(g.allow, domain) {
+		return false
+	}
+	if matchSuffix(g.block, domain) {
+		return true
+	}
+	for _, origin := range origins {
+		if g.active(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// active сообщает, блокирует ли группа домены источника. Сервисы
+// каталога выбираются для групп отдельно, поэтому проверяются раньше
+// Unfiltered и Sources.
+func (g *group) active(origin string) bool {
+	switch {
+	case strings.HasPrefix(origin, blacklist.OriginServicePrefix):
+		return true
+	case g.Unfiltered:
+		return false
+	case g.sources == nil:
+		return true
+	default:
+		return g.sources[origin]
+	}
+}
+
+// matchSuffix проверяет домен и все его родительские домены.
+func matchSuffix(set map[string]bool, domain string) bool {
+	_, ok := findSuffix(set, domain)
+	return ok
+}
+
+// findSuffix находит домен или ближайший родительский домен из set.
+func findSuffix(set map[string]bool, domain string) (string, bool) {
+	if len(set) == 0 {
+		return "", false
+	}
+	for off, end := 0, false; !end; off, end = dns.NextLabel(domain, off) {
+		if set[domain[off:]] {
+			return domain[off:], true
+		}
+	}
+	return "", false
+}
+
+type prefixClient struct {
+	prefix netip.Prefix
+	client *Client
+}
+
+// Policies сопоставляет клиентов с политиками их групп.
+type Policies struct {
+	groups map[string]*group
+
+	byID     map[string]*Client
+	byMAC    map[string]*Client
+	byIP     map[netip.Addr]*Client
+	prefixes []prefixClient
+}
+
+func New(config Config) (*Policies, error) {
+	p := &Policies{
+		groups: make(map[string]*group),
+		byID:   make(map[string]*Client),
+		byMAC:  make(map[string]*Client),
+		byIP:   make(map[netip.Addr]*Client),
+	}
+
+	for _, gc := range config.Groups {
+		if gc.Name == "" {
+			return nil, fmt.Errorf("policy: group name is empty")
+		}
+		if _, ok := p.groups[gc.Name]; ok {
+			return nil, fmt.Errorf("policy: group %s is defined twice", gc.Name)
+		}
+		g, err := newGroup(gc)
+		if err != nil {
+			return nil, err
+		}
+		p.groups[gc.Name] = g
+	}
+	if _, ok := p.groups[DefaultGroup]; !ok {
+		p.groups[DefaultGroup], _ = newGroup(Group{Name: DefaultGroup})
+	}
+
+	for i := range config.Clients {
+		c := &config.Clients[i]
+		if c.Group == "" {
+			c.Group = DefaultGroup
+		}
+		if _, ok := p.groups[c.Group]; !ok {
+			return nil, fmt.Errorf("policy: client %s refers to unknown group %s", c.Name, c.Group)
+		}
+		for _, id := range c.IDs {
+			if err := p.addID(c, id); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// более узкие подсети проверяются первыми
+	sort.SliceStable(p.prefixes, func(i, j int) bool {
+		return p.prefixes[i].prefix.Bits() > p.prefixes[j].prefix.Bits()
+	})
+
+	return p, nil
+}
+
+func (p *Policies) addID(c *Client, id string) error {
+	if clientID, ok := strings.CutPrefix(id, "id:"); ok {
+		p.byID[clientID] = c
+		return nil
+	}
+	if addr, err := netip.ParseAddr(id); err == nil {
+		p.byIP[addr.Unmap()] = c
+		return nil
+	}
+	if prefix, err := netip.ParsePrefix(id); err == nil {
+		p.prefixes = append(p.prefixes, prefixClient{prefix: prefix.Masked(), client: c})
+		return nil
+	}
+	if mac, err := net.ParseMAC(id); err == nil {
+		p.byMAC[mac.String()] = c
+		return nil
+	}
+	return fmt.Errorf("policy: client %s has invalid id %q", c.Name, id)
+}
+
+// Groups возвращает имена групп.
+func (p *Policies) Groups() []string {
+	names := make([]string, 0, len(p.groups))
+	for name := range p.groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Upstreams возвращает группы вышестоящих серверов, на которые ссылаются
+// группы клиентов.
+func (p *Policies) Upstreams() []string {
+	seen := make(map[string]bool)
+	var upstreams []string
+	for _, g := range p.groups {
+		if g.Upstreams != "" && !seen[g.Upstreams] {
+			seen[g.Upstreams] = true
+			upstreams = append(upstreams, g.Upstreams)
+		}
+	}
+	sort.Strings(upstreams)
+	return upstreams
+}
+
+// Match находит клиента по идентификатору, MAC-адресу, IP-адресу или
+// самой узкой подсети, именно в таком порядке, и возвращает политику
+// его группы.
+func (p *Policies) Match(id Identity) Policy {
+	c := p.client(id)
+	if c == nil {
+		return p.policy("", p.groups[DefaultGroup])
+	}
+	return p.policy(c.Name, p.groups[c.Group])
+}
+
+func (p *Policies) client(id Identity) *Client {
+	if c, ok := p.byID[id.ID]; ok && id.ID != "" {
+		return c
+	}
+	if c, ok := p.byMAC[id.MAC.String()]; ok && len(id.MAC) > 0 {
+		return c
+	}
+	ip := id.IP.Unmap()
+	if c, ok := p.byIP[ip]; ok {
+		return c
+	}
+	for _, pc := range p.prefixes {
+		if pc.prefix.Contains(ip) {
+			return pc.client
+		}
+	}
+	return nil
+}
+
+func (p *Policies) policy(client string, g *group) Policy {
+	return Policy{
+		Client:    client,
+		Group:     g.Name,
+		BlockMode: g.BlockMode,
+		Upstreams: g.Upstreams,
+		g:         g,
+	}
+}
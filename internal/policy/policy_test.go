@@ -0,0 +1,129 @@
+package policy
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	adsList     = "https://lists.test/ads"
+	adultList   = "https://lists.test/adult"
+	manualRules = "manual"
+)
+
+func newPolicies(t *testing.T) *Policies {
+	t.Helper()
+
+	p, err := New(Config{
+		Groups: []Group{
+			{Name: "kids", BlockMode: BlockNXDomain, Block: []string{"games.test"}, Allow: []string{"school.games.test"}},
+			{Name: "staff", Sources: []string{adsList, manualRules}},
+			{Name: "servers", Unfiltered: true, Upstreams: "internal"},
+		},
+		Clients: []Client{
+			{Name: "tablet", Group: "kids", IDs: []string{"02:00:00:00:00:01", "id:tablet"}},
+			{Name: "office", Group: "staff", IDs: []string{"10.0.0.0/8"}},
+			{Name: "printer", Group: "servers", IDs: []string{"10.0.0.5", "2001:db8::5"}},
+			{Name: "lab", Group: "servers", IDs: []string{"10.1.0.0/16"}},
+		},
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func TestMatch(t *testing.T) {
+	p := newPolicies(t)
+	mac, _ := net.ParseMAC("02:00:00:00:00:01")
+
+	tests := []struct {
+		name   string
+		id     Identity
+		client string
+		group  string
+	}{
+		{name: "unknown", id: Identity{IP: netip.MustParseAddr("192.0.2.1")}, group: DefaultGroup},
+		{name: "subnet", id: Identity{IP: netip.MustParseAddr("10.2.0.1")}, client: "office", group: "staff"},
+		{name: "narrower subnet", id: Identity{IP: netip.MustParseAddr("10.1.0.1")}, client: "lab", group: "servers"},
+		{name: "address", id: Identity{IP: netip.MustParseAddr("10.0.0.5")}, client: "printer", group: "servers"},
+		{name: "mapped address", id: Identity{IP: netip.MustParseAddr("::ffff:10.0.0.5")}, client: "printer", group: "servers"},
+		{name: "mac", id: Identity{IP: netip.MustParseAddr("10.0.0.5"), MAC: mac}, client: "tablet", group: "kids"},
+		{name: "client id", id: Identity{IP: netip.MustParseAddr("192.0.2.1"), ID: "tablet"}, client: "tablet", group: "kids"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Match(tt.id)
+			require.Equal(t, tt.client, got.Client)
+			require.Equal(t, tt.group, got.Group)
+		})
+	}
+}
+
+func TestBlocked(t *testing.T) {
+	p := newPolicies(t)
+	policy := func(ip string) Policy {
+		return p.Match(Identity{IP: netip.MustParseAddr(ip)})
+	}
+
+	everyone := policy("192.0.2.1")
+	require.Equal(t, BlockLocalhost, everyone.BlockMode)
+	require.True(t, everyone.Blocked("ads.test.", []string{adsList}))
+	require.True(t, everyone.Blocked("adult.test.", []string{adultList}))
+	require.False(t, everyone.Blocked("clean.test.", nil))
+
+	// группа с выбранными источниками
+	staff := policy("10.2.0.1")
+	require.True(t, staff.Blocked("ads.test.", []string{adsList}))
+	require.True(t, staff.Blocked("manual.test.", []string{manualRules}))
+	require.False(t, staff.Blocked("adult.test.", []string{adultList}))
+	// сервисы каталога не зависят от выбранных источников
+	require.True(t, staff.Blocked("tiktok.com.", []string{"service:tiktok"}))
+
+	// без фильтрации
+	servers := policy("10.0.0.5")
+	require.Equal(t, "internal", servers.Upstreams)
+	require.False(t, servers.Blocked("ads.test.", []string{adsList}))
+	// сервисы каталога выбираются для групп отдельно и действуют
+	// и без фильтрации
+	require.True(t, servers.Blocked("tiktok.com.", []string{adsList, "service:tiktok"}))
+
+	// ручные правила группы действуют на поддомены
+	kids := p.Match(Identity{ID: "tablet"})
+	require.Equal(t, BlockNXDomain, kids.BlockMode)
+	require.True(t, kids.Blocked("play.games.test.", nil))
+	require.False(t, kids.Blocked("school.games.test.", nil))
+	require.True(t, kids.Blocked("adult.test.", []string{adultList}))
+
+	// правила и источники, на которых основано решение
+	rule, allow, ok := kids.GroupRule("School.Games.test.")
+	require.True(t, ok)
+	require.True(t, allow)
+	require.Equal(t, "school.games.test.", rule)
+	_, _, ok = kids.GroupRule("clean.test.")
+	require.False(t, ok)
+	require.Equal(t, []string{adsList, "service:tiktok"}, staff.Sources([]string{adsList, adultList, "service:tiktok"}))
+	require.Empty(t, servers.Sources([]string{adsList}))
+	require.Equal(t, []string{"service:tiktok"}, servers.Sources([]string{adsList, "service:tiktok"}))
+	require.Equal(t, []string{adultList}, kids.Sources([]string{adultList}))
+
+	require.True(t, Policy{}.Blocked("ads.test.", []string{adsList}))
+	require.False(t, Policy{}.Blocked("clean.test.", nil))
+}
+
+func TestNewErrors(t *testing.T) {
+	_, err := New(Config{Clients: []Client{{Name: "x", Group: "missing"}}})
+	require.Error(t, err)
+
+	_, err = New(Config{Groups: []Group{{Name: "x", BlockMode: "silent"}}})
+	require.Error(t, err)
+
+	_, err = New(Config{Clients: []Client{{Name: "x", IDs: []string{"not an id"}}}})
+	require.Error(t, err)
+
+	p, err := New(Config{})
+	require.NoError(t, err)
+	require.Equal(t, []string{DefaultGroup}, p.Groups())
+}
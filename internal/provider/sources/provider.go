@@ -20,22 +20,31 @@ type Downloader interface {
 
 type Storage interface {
 	AddSource(ctx context.Context, url string) error
-	AddDomains(ctx context.Context, domains []string) error
+	AddDomains(ctx context.Context, origin string, domains []string) error
 	ForEachSource(ctx context.Context, f func(d string)) error
 }
 
+// Blacklist - черный список в памяти, в который загруженные домены
+// попадают сразу, без перезапуска.
+type Blacklist interface {
+	AddFrom(ctx context.Context, origin string, domains ...string) (count int)
+}
+
 type provider struct {
 	storage         Storage
+	blacklist       Blacklist
 	onRefreshSource func(url string)
 	downloader      Downloader
 }
 
 func NewProvider(
 	storage Storage,
+	blacklist Blacklist,
 	downloader Downloader,
 ) Provider {
 	return &provider{
 		storage:    storage,
+		blacklist:  blacklist,
 		downloader: downloader,
 	}
 }
@@ -70,8 +79,8 @@ func (p *provider) RefreshFromSource(ctx context.Context, url string) error {
 	err := p.downloader.ForEach(downloadCtx, url, func(domain string) error {
 		chunk = append(chunk, domain)
 		if len(chunk) == chunkSize {
-			if err := p.storage.AddDomains(ctx, chunk); err != nil {
-				return fmt.Errorf("downloader: unable to add domains: %v", err)
+			if err := p.addDomains(ctx, url, chunk); err != nil {
+				return err
 			}
 
 			chunk = chunk[:0]
@@ -84,13 +93,22 @@ func (p *provider) RefreshFromSource(ctx context.Context, url string) error {
 	}
 
 	if len(chunk) > 0 {
-		if err := p.storage.AddDomains(ctx, chunk); err != nil {
-			return fmt.Errorf("downloader: unable to add domains: %v", err)
+		if err := p.addDomains(ctx, url, chunk); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// addDomains сохраняет домены источника в базе и в черном списке.
+func (p *provider) addDomains(ctx context.Context, url string, domains []string) error {
+	if err := p.storage.AddDomains(ctx, url, domains); err != nil {
+		return fmt.Errorf("downloader: unable to add domains: %v", err)
+	}
+	p.blacklist.AddFrom(ctx, url, domains...)
+	return nil
+}
+
 func (p *provider) AddSource(ctx context.Context, u string) error {
 	if _, err := url.Parse(u); err != nil {
 		return fmt.Errorf("source: url %s is not valid", u)
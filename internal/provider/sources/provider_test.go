@@ -0,0 +1,65 @@
+package sources
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeDownloader map[string][]string
+
+func (d fakeDownloader) ForEach(_ context.Context, url string, f func(d string) error) error {
+	for _, domain := range d[url] {
+		if err := f(domain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRefreshAfterMigration(t *testing.T) {
+	ctx := context.Background()
+	const source = "https://lists.test/ads"
+
+	db, err := datastore.Open(filepath.Join(t.TempDir(), "blackhole.sqlite3"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	// база до появления источников у доменов
+	_, err = db.ExecContext(ctx, `
+CREATE TABLE domains (id INTEGER PRIMARY KEY AUTOINCREMENT, domain TEXT);
+CREATE UNIQUE INDEX domains_domain_ux ON domains (domain);
+CREATE TABLE sources (id INTEGER PRIMARY KEY AUTOINCREMENT, url TEXT);
+INSERT INTO domains (domain) VALUES ('ads.test'), ('tracker.test');
+INSERT INTO sources (url) VALUES ('`+source+`');
+`)
+	require.NoError(t, err)
+
+	storage := datastore.New(db, 100, zap.NewNop())
+	require.NoError(t, storage.Migrate(ctx))
+	require.True(t, storage.SourcesOutdated())
+
+	stored := map[string]string{}
+	forEach := func(domain, origin string) { stored[domain] = origin }
+	require.NoError(t, storage.ForEachDomain(ctx, forEach))
+	require.Empty(t, stored)
+
+	bl := blacklist.New(16)
+	p := NewProvider(storage, bl, fakeDownloader{source: {"ads.test", "tracker.test"}})
+	var refreshed []string
+	p.OnRefreshSource(func(url string) { refreshed = append(refreshed, url) })
+	require.NoError(t, p.RefreshSources(ctx))
+	require.Equal(t, []string{source}, refreshed)
+	require.NoError(t, p.RefreshFromSource(ctx, source))
+
+	// домены попадают и в базу, и сразу в черный список
+	require.NoError(t, storage.ForEachDomain(ctx, forEach))
+	require.Equal(t, map[string]string{"ads.test": source, "tracker.test": source}, stored)
+	require.Equal(t, []string{source}, bl.Origins(ctx, "ads.test"))
+	require.Equal(t, []string{source}, bl.Origins(ctx, "tracker.test"))
+}
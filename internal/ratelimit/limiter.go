@@ -0,0 +1,175 @@
+package ratelimit
+
+import (
+	"context"
+	"expvar"
+	"net/netip"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+const (
+	// DefaultIPv4Prefix и DefaultIPv6Prefix - размер подсети, которая
+	// считается одним клиентом.
+	DefaultIPv4Prefix = 32
+	DefaultIPv6Prefix = 56
+)
+
+type Config struct {
+	// Rate - количество запросов в секунду на клиента, ноль отключает
+	// ограничение.
+	Rate float64
+	// Burst - сколько запросов клиент может отправить разом, по умолчанию
+	// равен Rate.
+	Burst int
+	// IPv4Prefix и IPv6Prefix объединяют адреса одной подсети в одного
+	// клиента.
+	IPv4Prefix int
+	IPv6Prefix int
+}
+
+// Limiter ограничивает частоту запросов клиентов алгоритмом token bucket.
+type Limiter struct {
+	rate  float64
+	burst float64
+	v4    int
+	v6    int
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[netip.Prefix]*bucket
+
+	limited *atomic.Int64
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func New(config Config) *Limiter {
+	l := &Limiter{
+		rate:    config.Rate,
+		burst:   float64(config.Burst),
+		v4:      config.IPv4Prefix,
+		v6:      config.IPv6Prefix,
+		now:     time.Now,
+		buckets: make(map[netip.Prefix]*bucket),
+		limited: atomic.NewInt64(0),
+	}
+	if l.burst <= 0 {
+		l.burst = l.rate
+	}
+	if l.burst < 1 {
+		l.burst = 1
+	}
+	if l.v4 <= 0 || l.v4 > 32 {
+		l.v4 = DefaultIPv4Prefix
+	}
+	if l.v6 <= 0 || l.v6 > 128 {
+		l.v6 = DefaultIPv6Prefix
+	}
+
+	if expvar.Get("blackhole_ratelimit") == nil {
+		expvar.Publish("blackhole_ratelimit", expvar.Func(func() any {
+			return l.dumpStats()
+		}))
+	}
+
+	return l
+}
+
+// Allow расходует один токен клиента и сообщает, можно ли обслужить запрос.
+func (l *Limiter) Allow(ip netip.Addr) bool {
+	if l.rate <= 0 {
+		return true
+	}
+
+	key := l.client(ip)
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		l.limited.Inc()
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (l *Limiter) client(ip netip.Addr) netip.Prefix {
+	ip = ip.Unmap()
+
+	bits := l.v6
+	if ip.Is4() {
+		bits = l.v4
+	}
+
+	prefix, err := ip.Prefix(bits)
+	if err != nil {
+		return netip.PrefixFrom(ip, ip.BitLen())
+	}
+	return prefix
+}
+
+// RunPeriodicCleanup забывает клиентов, чьи корзины успели наполниться.
+func (l *Limiter) RunPeriodicCleanup(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.cleanup()
+			}
+		}
+	}()
+}
+
+func (l *Limiter) cleanup() {
+	if l.rate <= 0 {
+		return
+	}
+
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+type stats struct {
+	Clients int   `json:"clients"`
+	Limited int64 `json:"limited"`
+}
+
+func (l *Limiter) dumpStats() stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return stats{Clients: len(l.buckets), Limited: l.limited.Load()}
+}
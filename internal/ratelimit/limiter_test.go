@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := New(Config{Rate: 2, Burst: 3, IPv6Prefix: 64})
+	l.now = func() time.Time { return now }
+
+	client := netip.MustParseAddr("192.0.2.1")
+
+	// корзина изначально полна
+	for i := 0; i < 3; i++ {
+		require.True(t, l.Allow(client))
+	}
+	require.False(t, l.Allow(client))
+
+	// другой клиент не затронут
+	require.True(t, l.Allow(netip.MustParseAddr("192.0.2.2")))
+
+	// за полсекунды набирается один токен
+	now = now.Add(500 * time.Millisecond)
+	require.True(t, l.Allow(client))
+	require.False(t, l.Allow(client))
+
+	// адреса одной подсети считаются одним клиентом
+	for i := 0; i < 3; i++ {
+		require.True(t, l.Allow(netip.MustParseAddr("2001:db8::1")))
+	}
+	require.False(t, l.Allow(netip.MustParseAddr("2001:db8::2")))
+	require.True(t, l.Allow(netip.MustParseAddr("2001:db8:0:1::1")))
+
+	// наполнившиеся корзины удаляются
+	now = now.Add(time.Minute)
+	l.cleanup()
+	require.Equal(t, 0, l.dumpStats().Clients)
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	l := New(Config{})
+
+	for i := 0; i < 100; i++ {
+		require.True(t, l.Allow(netip.MustParseAddr("192.0.2.1")))
+	}
+}
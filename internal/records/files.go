@@ -0,0 +1,263 @@
+package records
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+// fileCheckInterval - как часто проверяются файлы с записями.
+const fileCheckInterval = 5 * time.Second
+
+const (
+	// FormatHosts - файл в формате /etc/hosts.
+	FormatHosts = "hosts"
+	// FormatZone - файл зоны в формате BIND.
+	FormatZone = "zone"
+)
+
+// File - файл с локальными записями. Origin - зона по умолчанию для
+// относительных имен файла зоны, если в нем нет $ORIGIN.
+type File struct {
+	Path   string
+	Format string
+	Origin string
+}
+
+// ParseHosts разбирает файл вида "адрес имя [псевдонимы...]".
+func ParseHosts(r io.Reader, source string) ([]Record, error) {
+	var records []Record
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("records: %s:%d: host name is missing", source, line)
+		}
+
+		// адрес может быть с зоной: fe80::1%lo0
+		addr, err := netip.ParseAddr(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("records: %s:%d: invalid address %q", source, line, fields[0])
+		}
+		addr = addr.WithZone("").Unmap()
+		typ := "AAAA"
+		if addr.Is4() {
+			typ = "A"
+		}
+
+		for _, name := range fields[1:] {
+			r := Record{Name: name, Type: typ, Value: addr.String(), Source: source}
+			if err := r.normalize(); err != nil {
+				return nil, fmt.Errorf("%v (%s:%d)", err, source, line)
+			}
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("records: unable to read %s: %v", source, err)
+	}
+
+	return records, nil
+}
+
+// ParseZone разбирает файл зоны. Записи неподдерживаемых типов (SOA, NS,
+// MX и прочие) пропускаются.
+func ParseZone(r io.Reader, origin, source string) ([]Record, error) {
+	var records []Record
+
+	parser := dns.NewZoneParser(r, dns.Fqdn(origin), source)
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		record := Record{Name: rr.Header().Name, TTL: rr.Header().Ttl, Source: source}
+		switch v := rr.(type) {
+		case *dns.A:
+			record.Type, record.Value = "A", v.A.String()
+		case *dns.AAAA:
+			record.Type, record.Value = "AAAA", v.AAAA.String()
+		case *dns.CNAME:
+			record.Type, record.Value = "CNAME", v.Target
+		case *dns.PTR:
+			record.Type, record.Value = "PTR", v.Ptr
+		case *dns.TXT:
+			record.Type, record.Value = "TXT", strings.Join(v.Txt, "")
+		default:
+			continue
+		}
+		if err := record.normalize(); err != nil {
+			return nil, fmt.Errorf("%v (%s)", err, source)
+		}
+		records = append(records, record)
+	}
+	if err := parser.Err(); err != nil {
+		return nil, fmt.Errorf("records: %v", err)
+	}
+
+	return records, nil
+}
+
+// synthesizePTR добавляет обратные записи для адресов A и AAAA: первое
+// имя адреса становится его PTR, если PTR не задан явно.
+func synthesizePTR(records []Record) []Record {
+	explicit := make(map[string]bool)
+	for _, r := range records {
+		if r.Type == "PTR" {
+			explicit[r.Name] = true
+		}
+	}
+
+	result := records
+	for _, r := range records {
+		if (r.Type != "A" && r.Type != "AAAA") || strings.HasPrefix(r.Name, "*.") {
+			continue
+		}
+		reverse, err := dns.ReverseAddr(r.Value)
+		if err != nil || explicit[reverse] {
+			continue
+		}
+		explicit[reverse] = true
+		result = append(result, Record{Name: reverse, Type: "PTR", Value: r.Name, TTL: r.TTL, Source: r.Source})
+	}
+	return result
+}
+
+func readFile(f File) ([]Record, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, fmt.Errorf("records: unable to open %s: %v", f.Path, err)
+	}
+	defer file.Close()
+
+	var records []Record
+	switch f.Format {
+	case FormatHosts:
+		records, err = ParseHosts(file, f.Path)
+	case FormatZone:
+		records, err = ParseZone(file, f.Origin, f.Path)
+	default:
+		return nil, fmt.Errorf("records: unknown format %q of %s", f.Format, f.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return synthesizePTR(records), nil
+}
+
+// SetFile заменяет записи файла source.
+func (s *Store) SetFile(source string, records []Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[source] = records
+	s.index()
+}
+
+// Watcher загружает файлы с записями и перечитывает их при изменении.
+type Watcher struct {
+	store    *Store
+	files    []File
+	interval time.Duration
+	log      *zap.Logger
+
+	modTimes map[string]time.Time
+}
+
+func NewWatcher(store *Store, files []File, log *zap.Logger) *Watcher {
+	return &Watcher{
+		store:    store,
+		files:    files,
+		interval: fileCheckInterval,
+		log:      log,
+		modTimes: make(map[string]time.Time),
+	}
+}
+
+// Load загружает все файлы, ошибка любого из них прерывает загрузку.
+func (w *Watcher) Load() error {
+	for _, f := range w.files {
+		if err := w.load(f); err != nil {
+			return err
+		}
+	}
+	w.logShadowed()
+	return nil
+}
+
+func (w *Watcher) load(f File) error {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return fmt.Errorf("records: unable to read %s: %v", f.Path, err)
+	}
+	records, err := readFile(f)
+	if err != nil {
+		return err
+	}
+
+	w.store.SetFile(f.Path, records)
+	w.modTimes[f.Path] = info.ModTime()
+	return nil
+}
+
+// Run перечитывает изменившиеся файлы. При ошибке остаются прежние
+// записи файла.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				w.reload()
+			}
+		}
+	}()
+}
+
+func (w *Watcher) reload() {
+	changed := false
+	for _, f := range w.files {
+		info, err := os.Stat(f.Path)
+		if err != nil || info.ModTime().Equal(w.modTimes[f.Path]) {
+			continue
+		}
+		if err := w.load(f); err != nil {
+			w.log.Error("records: unable to reload a file, keeping previous records", zap.Error(err))
+			// не повторяем попытку, пока файл не изменится снова
+			w.modTimes[f.Path] = info.ModTime()
+			continue
+		}
+		w.log.Info("records: file reloaded", zap.String("file", f.Path))
+		changed = true
+	}
+	if changed {
+		w.logShadowed()
+	}
+}
+
+func (w *Watcher) logShadowed() {
+	for _, r := range w.store.Shadowed() {
+		w.log.Warn("records: record conflicts with other records and is ignored",
+			zap.String("file", r.Source),
+			zap.String("name", r.Name),
+			zap.String("type", r.Type),
+			zap.String("value", r.Value),
+		)
+	}
+}
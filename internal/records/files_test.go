@@ -0,0 +1,123 @@
+package records
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const hosts = `
+# комментарий
+10.0.0.2    nas.home nas   # псевдоним
+fe80::1%lo0 router.home
+10.0.0.2    storage.home
+`
+
+const zone = `
+$ORIGIN office.
+$TTL 60
+@        IN SOA ns.office. admin.office. 1 3600 600 86400 60
+@        IN NS  ns.office.
+printer  IN A     10.1.0.5
+wiki     IN CNAME printer
+*.lab    IN AAAA  2001:db8::1
+info     IN TXT   "hello" "world"
+5.0.1.10.in-addr.arpa. IN PTR print-server.office.
+`
+
+func TestParseHosts(t *testing.T) {
+	records, err := ParseHosts(strings.NewReader(hosts), "hosts")
+	require.NoError(t, err)
+	require.Equal(t, []Record{
+		{Name: "nas.home.", Type: "A", Value: "10.0.0.2", TTL: DefaultTTL, Source: "hosts"},
+		{Name: "nas.", Type: "A", Value: "10.0.0.2", TTL: DefaultTTL, Source: "hosts"},
+		{Name: "router.home.", Type: "AAAA", Value: "fe80::1", TTL: DefaultTTL, Source: "hosts"},
+		{Name: "storage.home.", Type: "A", Value: "10.0.0.2", TTL: DefaultTTL, Source: "hosts"},
+	}, records)
+
+	// обратная запись - первое имя адреса
+	records = synthesizePTR(records)
+	require.Len(t, records, 6)
+	require.Equal(t, Record{Name: "2.0.0.10.in-addr.arpa.", Type: "PTR", Value: "nas.home.", TTL: DefaultTTL, Source: "hosts"}, records[4])
+	require.Equal(t, "router.home.", records[5].Value)
+
+	_, err = ParseHosts(strings.NewReader("10.0.0.1\n"), "hosts")
+	require.Error(t, err)
+	_, err = ParseHosts(strings.NewReader("10.0.0.300 bad.home\n"), "hosts")
+	require.Error(t, err)
+}
+
+func TestParseZone(t *testing.T) {
+	records, err := ParseZone(strings.NewReader(zone), "", "office.zone")
+	require.NoError(t, err)
+	require.Equal(t, []Record{
+		{Name: "printer.office.", Type: "A", Value: "10.1.0.5", TTL: 60, Source: "office.zone"},
+		{Name: "wiki.office.", Type: "CNAME", Value: "printer.office.", TTL: 60, Source: "office.zone"},
+		{Name: "*.lab.office.", Type: "AAAA", Value: "2001:db8::1", TTL: 60, Source: "office.zone"},
+		{Name: "info.office.", Type: "TXT", Value: "helloworld", TTL: 60, Source: "office.zone"},
+		{Name: "5.0.1.10.in-addr.arpa.", Type: "PTR", Value: "print-server.office.", TTL: 60, Source: "office.zone"},
+	}, records)
+
+	// явный PTR не заменяется, у шаблона обратной записи нет
+	require.Equal(t, records, synthesizePTR(records))
+
+	// относительные имена без $ORIGIN
+	records, err = ParseZone(strings.NewReader("nas 300 IN A 10.0.0.2\n"), "home", "home.zone")
+	require.NoError(t, err)
+	require.Equal(t, "nas.home.", records[0].Name)
+
+	_, err = ParseZone(strings.NewReader("nas IN A 10.0.0\n"), "home", "home.zone")
+	require.Error(t, err)
+}
+
+func TestWatcher(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	hostsFile := filepath.Join(dir, "hosts")
+	require.NoError(t, os.WriteFile(hostsFile, []byte("10.0.0.2 nas.home\n10.0.0.3 tv.home\n"), 0o644))
+
+	store := New(&fakeStorage{records: make(map[int64]datastore.LocalRecord)})
+	w := NewWatcher(store, []File{{Path: hostsFile, Format: FormatHosts}}, zap.NewNop())
+	require.NoError(t, w.Load())
+
+	answer, found := store.Lookup("nas.home.", dns.TypeA)
+	require.True(t, found)
+	require.Equal(t, "10.0.0.2", answer[0].(*dns.A).A.String())
+	answer, _ = store.Lookup("3.0.0.10.in-addr.arpa.", dns.TypePTR)
+	require.Equal(t, "tv.home.", answer[0].(*dns.PTR).Ptr)
+
+	// запись API сильнее записи из файла
+	_, err := store.Add(ctx, Record{Name: "tv.home", Type: "CNAME", Value: "nas.home"})
+	require.NoError(t, err)
+	answer, _ = store.Lookup("tv.home.", dns.TypeA)
+	require.Len(t, answer, 2)
+	require.Equal(t, "tv.home.", store.Shadowed()[0].Name)
+	for _, r := range store.List() {
+		require.Equal(t, r.Source == hostsFile && r.Name == "tv.home.", r.Shadowed, r)
+	}
+	// записи из файла не меняются через API
+	require.Error(t, store.Delete(ctx, 0))
+
+	// файл перечитывается при изменении
+	require.NoError(t, os.WriteFile(hostsFile, []byte("10.0.0.4 nas.home\n"), 0o644))
+	require.NoError(t, os.Chtimes(hostsFile, time.Now(), time.Now().Add(time.Minute)))
+	w.reload()
+	answer, _ = store.Lookup("nas.home.", dns.TypeA)
+	require.Equal(t, "10.0.0.4", answer[0].(*dns.A).A.String())
+	require.Empty(t, store.Shadowed())
+
+	// ошибочный файл не затирает прежние записи
+	require.NoError(t, os.WriteFile(hostsFile, []byte("broken\n"), 0o644))
+	require.NoError(t, os.Chtimes(hostsFile, time.Now(), time.Now().Add(2*time.Minute)))
+	w.reload()
+	_, found = store.Lookup("nas.home.", dns.TypeA)
+	require.True(t, found)
+}
@@ -0,0 +1,410 @@
+package records
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/miekg/dns"
+)
+
+// DefaultTTL - TTL записи, для которой он не задан.
+const DefaultTTL = 300
+
+// maxChain ограничивает цепочку CNAME внутри локальных записей.
+const maxChain = 8
+
+// SourceAPI - источник записей, управляемых через API. Источник записей
+// из файлов - путь к файлу.
+const SourceAPI = "api"
+
+// Record - локальная запись. Имя вида *.home. задает шаблон для всех
+// поддоменов home., для которых нет своих записей.
+type Record struct {
+	ID    int64
+	Name  string
+	Type  string
+	Value string
+	TTL   uint32
+
+	Source string
+	// Shadowed - запись из файла не действует: имя занято записями API
+	// или другого файла.
+	Shadowed bool
+}
+
+var types = map[string]uint16{
+	"A":     dns.TypeA,
+	"AAAA":  dns.TypeAAAA,
+	"CNAME": dns.TypeCNAME,
+	"TXT":   dns.TypeTXT,
+	"PTR":   dns.TypePTR,
+}
+
+// normalize проверяет запись и приводит имена и адреса к каноническому
+// виду.
+func (r *Record) normalize() error {
+	r.Name = dns.CanonicalName(strings.TrimSpace(r.Name))
+	if _, ok := dns.IsDomainName(r.Name); !ok || r.Name == "." {
+		return fmt.Errorf("records: invalid name %q", r.Name)
+	}
+	if strings.Contains(strings.TrimPrefix(r.Name, "*."), "*") {
+		return fmt.Errorf("records: wildcard is allowed only as the first label of %q", r.Name)
+	}
+
+	r.Type = strings.ToUpper(strings.TrimSpace(r.Type))
+	switch r.Type {
+	case "A", "AAAA":
+		addr, err := netip.ParseAddr(strings.TrimSpace(r.Value))
+		if err != nil || addr.Is4() != (r.Type == "A") {
+			return fmt.Errorf("records: invalid %s address %q", r.Type, r.Value)
+		}
+		r.Value = addr.String()
+	case "CNAME", "PTR":
+		r.Value = dns.CanonicalName(strings.TrimSpace(r.Value))
+		if _, ok := dns.IsDomainName(r.Value); !ok || r.Value == "." {
+			return fmt.Errorf("records: invalid %s target %q", r.Type, r.Value)
+		}
+	case "TXT":
+		if r.Value == "" {
+			return fmt.Errorf("records: TXT value is empty")
+		}
+	default:
+		return fmt.Errorf("records: unsupported type %q", r.Type)
+	}
+
+	if r.TTL == 0 {
+		r.TTL = DefaultTTL
+	}
+	return nil
+}
+
+func (r Record) rrtype() uint16 {
+	return types[r.Type]
+}
+
+// rr создает запись ответа для имени owner: для шаблонов это имя
+// из запроса.
+func (r Record) rr(owner string) dns.RR {
+	hdr := dns.RR_Header{Name: owner, Rrtype: r.rrtype(), Class: dns.ClassINET, Ttl: r.TTL}
+
+	switch r.Type {
+	case "A":
+		return &dns.A{Hdr: hdr, A: netip.MustParseAddr(r.Value).AsSlice()}
+	case "AAAA":
+		return &dns.AAAA{Hdr: hdr, AAAA: netip.MustParseAddr(r.Value).AsSlice()}
+	case "CNAME":
+		return &dns.CNAME{Hdr: hdr, Target: r.Value}
+	case "PTR":
+		return &dns.PTR{Hdr: hdr, Ptr: r.Value}
+	default:
+		return &dns.TXT{Hdr: hdr, Txt: splitTXT(r.Value)}
+	}
+}
+
+// splitTXT делит значение на строки по 255 байт.
+func splitTXT(value string) []string {
+	var txt []string
+	for len(value) > 255 {
+		txt = append(txt, value[:255])
+		value = value[255:]
+	}
+	return append(txt, value)
+}
+
+type Storage interface {
+	LocalRecords(ctx context.Context) ([]datastore.LocalRecord, error)
+	AddLocalRecord(ctx context.Context, record datastore.LocalRecord) (int64, error)
+	UpdateLocalRecord(ctx context.Context, record datastore.LocalRecord) error
+	DeleteLocalRecord(ctx context.Context, id int64) error
+}
+
+// Store - локальные записи, на которые сервер отвечает сам
+// как authoritative.
+type Store struct {
+	storage Storage
+
+	mu      sync.RWMutex
+	records []Record
+	// files - записи из файлов по путям, они не меняются через API
+	// и уступают записям API
+	files map[string][]Record
+	// shadowed - записи из файлов, уступившие другим записям
+	shadowed []Record
+	// exact - записи по именам, wildcard - шаблоны по родительскому
+	// домену: *.home. хранится под home.
+	exact    map[string][]Record
+	wildcard map[string][]Record
+}
+
+func New(storage Storage) *Store {
+	s := &Store{storage: storage, files: make(map[string][]Record)}
+	s.index()
+	return s
+}
+
+func (s *Store) Load(ctx context.Context) error {
+	stored, err := s.storage.LocalRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	records := make([]Record, 0, len(stored))
+	for _, st := range stored {
+		r := Record{ID: st.ID, Name: st.Name, Type: st.Type, Value: st.Value, TTL: st.TTL, Source: SourceAPI}
+		if err := r.normalize(); err != nil {
+			return err
+		}
+		records = append(records, r)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records = records
+	s.index()
+	return nil
+}
+
+// index собирает действующие записи: все записи API и записи файлов,
+// имена которых не заняты. Файлы просматриваются в порядке путей.
+func (s *Store) index() {
+	effective := append([]Record(nil), s.records...)
+
+	byName := make(map[string][]Record)
+	for _, r := range s.records {
+		byName[r.Name] = append(byName[r.Name], r)
+	}
+
+	sources := make([]string, 0, len(s.files))
+	for source := range s.files {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	s.shadowed = nil
+	for _, source := range sources {
+		for _, r := range s.files[source] {
+			if shadows(byName[r.Name], r) {
+				r.Shadowed = true
+				s.shadowed = append(s.shadowed, r)
+				continue
+			}
+			byName[r.Name] = append(byName[r.Name], r)
+			effective = append(effective, r)
+		}
+	}
+
+	s.exact = make(map[string][]Record)
+	s.wildcard = make(map[string][]Record)
+	for _, r := range effective {
+		if parent, ok := strings.CutPrefix(r.Name, "*."); ok {
+			s.wildcard[parent] = append(s.wildcard[parent], r)
+			continue
+		}
+		s.exact[r.Name] = append(s.exact[r.Name], r)
+	}
+}
+
+// shadows сообщает, что запись из файла уступает записям имени
+// из других источников.
+func shadows(existing []Record, r Record) bool {
+	for _, e := range existing {
+		if e.Source == SourceAPI && e.Source != r.Source {
+			return true
+		}
+		if e.Type == "CNAME" || r.Type == "CNAME" {
+			return true
+		}
+		if e.Type == r.Type && e.Value == r.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// List возвращает записи API и файлов, упорядоченные по имени и типу.
+func (s *Store) List() []Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := append([]Record(nil), s.records...)
+	for _, records := range s.files {
+		for _, r := range records {
+			list = append(list, r)
+		}
+	}
+	shadowed := make(map[Record]bool, len(s.shadowed))
+	for _, r := range s.shadowed {
+		r.Shadowed = false
+		shadowed[r] = true
+	}
+	for i := range list {
+		list[i].Shadowed = shadowed[list[i]]
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].Type < list[j].Type
+	})
+	return list
+}
+
+// conflicts проверяет, можно ли добавить запись к остальным: записи
+// не повторяются, а CNAME не соседствует с другими записями имени.
+func (s *Store) conflicts(r Record) error {
+	for _, existing := range s.records {
+		if existing.ID == r.ID || existing.Name != r.Name {
+			continue
+		}
+		if existing.Type == r.Type && existing.Value == r.Value {
+			return fmt.Errorf("records: %s %s %s already exists", r.Name, r.Type, r.Value)
+		}
+		if existing.Type == "CNAME" || r.Type == "CNAME" {
+			return fmt.Errorf("records: CNAME of %s cannot coexist with other records", r.Name)
+		}
+	}
+	return nil
+}
+
+// Shadowed возвращает записи из файлов, уступившие записям API или
+// других файлов.
+func (s *Store) Shadowed() []Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]Record(nil), s.shadowed...)
+}
+
+func (s *Store) Add(ctx context.Context, r Record) (Record, error) {
+	r.ID = 0
+	r.Source = SourceAPI
+	if err := r.normalize(); err != nil {
+		return Record{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.conflicts(r); err != nil {
+		return Record{}, err
+	}
+	id, err := s.storage.AddLocalRecord(ctx, toStored(r))
+	if err != nil {
+		return Record{}, err
+	}
+	r.ID = id
+
+	s.records = append(s.records, r)
+	s.index()
+	return r, nil
+}
+
+func (s *Store) Update(ctx context.Context, r Record) (Record, error) {
+	r.Source = SourceAPI
+	if err := r.normalize(); err != nil {
+		return Record{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(r.ID)
+	if i < 0 {
+		return Record{}, fmt.Errorf("records: record %d does not exist", r.ID)
+	}
+	if err := s.conflicts(r); err != nil {
+		return Record{}, err
+	}
+	if err := s.storage.UpdateLocalRecord(ctx, toStored(r)); err != nil {
+		return Record{}, err
+	}
+
+	s.records[i] = r
+	s.index()
+	return r, nil
+}
+
+func (s *Store) Delete(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(id)
+	if i < 0 {
+		return fmt.Errorf("records: record %d does not exist", id)
+	}
+	if err := s.storage.DeleteLocalRecord(ctx, id); err != nil {
+		return err
+	}
+
+	s.records = append(s.records[:i:i], s.records[i+1:]...)
+	s.index()
+	return nil
+}
+
+func (s *Store) find(id int64) int {
+	for i, r := range s.records {
+		if r.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Lookup ищет ответ в локальных записях. found сообщает, что имя
+// описано локально: тогда ответ authoritative, даже если записей
+// запрошенного типа нет. CNAME раскрывается по локальным записям,
+// цепочка может закончиться CNAME на внешнее имя.
+func (s *Store) Lookup(name string, qtype uint16) (answer []dns.RR, found bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	owner := dns.Fqdn(name)
+	for i := 0; i < maxChain; i++ {
+		records, ok := s.match(dns.CanonicalName(owner))
+		if !ok {
+			return answer, found
+		}
+		found = true
+
+		if qtype != dns.TypeCNAME && records[0].Type == "CNAME" {
+			cname := records[0].rr(owner)
+			answer = append(answer, cname)
+			owner = records[0].Value
+			continue
+		}
+
+		for _, r := range records {
+			if r.rrtype() == qtype || qtype == dns.TypeANY {
+				answer = append(answer, r.rr(owner))
+			}
+		}
+		return answer, found
+	}
+	return answer, found
+}
+
+// match находит записи имени, а если их нет - ближайший шаблон.
+func (s *Store) match(name string) ([]Record, bool) {
+	if records, ok := s.exact[name]; ok {
+		return records, true
+	}
+	if len(s.wildcard) == 0 {
+		return nil, false
+	}
+	for off, end := dns.NextLabel(name, 0); !end; off, end = dns.NextLabel(name, off) {
+		if records, ok := s.wildcard[name[off:]]; ok {
+			return records, true
+		}
+	}
+	return nil, false
+}
+
+func toStored(r Record) datastore.LocalRecord {
+	return datastore.LocalRecord{ID: r.ID, Name: r.Name, Type: r.Type, Value: r.Value, TTL: r.TTL}
+}
@@ -0,0 +1,119 @@
+package records
+
+import (
+	"context"
+	"testing"
+
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStorage struct {
+	lastID  int64
+	records map[int64]datastore.LocalRecord
+}
+
+func (s *fakeStorage) LocalRecords(context.Context) ([]datastore.LocalRecord, error) {
+	var list []datastore.LocalRecord
+	for _, r := range s.records {
+		list = append(list, r)
+	}
+	return list, nil
+}
+
+func (s *fakeStorage) AddLocalRecord(_ context.Context, r datastore.LocalRecord) (int64, error) {
+	s.lastID++
+	r.ID = s.lastID
+	s.records[r.ID] = r
+	return r.ID, nil
+}
+
+func (s *fakeStorage) UpdateLocalRecord(_ context.Context, r datastore.LocalRecord) error {
+	s.records[r.ID] = r
+	return nil
+}
+
+func (s *fakeStorage) DeleteLocalRecord(_ context.Context, id int64) error {
+	delete(s.records, id)
+	return nil
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	storage := &fakeStorage{records: make(map[int64]datastore.LocalRecord)}
+	s := New(storage)
+
+	for _, bad := range []Record{
+		{Name: "nas.home", Type: "A", Value: "::1"},
+		{Name: "nas.home", Type: "AAAA", Value: "10.0.0.1"},
+		{Name: "nas.home", Type: "MX", Value: "mail.home"},
+		{Name: "a.*.home", Type: "A", Value: "10.0.0.1"},
+		{Name: "nas.home", Type: "CNAME", Value: ""},
+	} {
+		_, err := s.Add(ctx, bad)
+		require.Error(t, err, bad)
+	}
+
+	nas, err := s.Add(ctx, Record{Name: "NAS.home", Type: "a", Value: "10.0.0.2"})
+	require.NoError(t, err)
+	require.Equal(t, Record{ID: 1, Name: "nas.home.", Type: "A", Value: "10.0.0.2", TTL: DefaultTTL, Source: SourceAPI}, nas)
+
+	_, err = s.Add(ctx, Record{Name: "nas.home.", Type: "A", Value: "10.0.0.2"})
+	require.Error(t, err)
+	_, err = s.Add(ctx, Record{Name: "nas.home.", Type: "CNAME", Value: "storage.home."})
+	require.Error(t, err)
+
+	_, err = s.Add(ctx, Record{Name: "*.office", Type: "A", Value: "10.1.0.1", TTL: 60})
+	require.NoError(t, err)
+	_, err = s.Add(ctx, Record{Name: "api.company.com", Type: "CNAME", Value: "nas.home"})
+	require.NoError(t, err)
+	_, err = s.Add(ctx, Record{Name: "2.0.0.10.in-addr.arpa", Type: "PTR", Value: "nas.home"})
+	require.NoError(t, err)
+
+	answer, found := s.Lookup("nas.home.", dns.TypeA)
+	require.True(t, found)
+	require.Equal(t, "nas.home.\t300\tIN\tA\t10.0.0.2", answer[0].String())
+
+	// имя описано, записей нужного типа нет
+	answer, found = s.Lookup("nas.home.", dns.TypeAAAA)
+	require.True(t, found)
+	require.Empty(t, answer)
+
+	// шаблон отвечает от имени запроса
+	answer, found = s.Lookup("printer.floor2.office.", dns.TypeA)
+	require.True(t, found)
+	require.Equal(t, "printer.floor2.office.\t60\tIN\tA\t10.1.0.1", answer[0].String())
+	_, found = s.Lookup("office.", dns.TypeA)
+	require.False(t, found)
+
+	// CNAME раскрывается по локальным записям
+	answer, found = s.Lookup("API.company.com.", dns.TypeA)
+	require.True(t, found)
+	require.Len(t, answer, 2)
+	require.Equal(t, "nas.home.", answer[0].(*dns.CNAME).Target)
+	require.Equal(t, "10.0.0.2", answer[1].(*dns.A).A.String())
+
+	answer, _ = s.Lookup("2.0.0.10.in-addr.arpa.", dns.TypePTR)
+	require.Equal(t, "nas.home.", answer[0].(*dns.PTR).Ptr)
+
+	_, found = s.Lookup("example.com.", dns.TypeA)
+	require.False(t, found)
+
+	nas.Value = "10.0.0.3"
+	_, err = s.Update(ctx, nas)
+	require.NoError(t, err)
+	answer, _ = s.Lookup("nas.home.", dns.TypeA)
+	require.Equal(t, "10.0.0.3", answer[0].(*dns.A).A.String())
+
+	// записи восстанавливаются из хранилища
+	restored := New(storage)
+	require.NoError(t, restored.Load(ctx))
+	require.Equal(t, s.List(), restored.List())
+
+	require.NoError(t, s.Delete(ctx, nas.ID))
+	require.Error(t, s.Delete(ctx, nas.ID))
+	answer, found = s.Lookup("api.company.com.", dns.TypeA)
+	require.True(t, found)
+	require.Len(t, answer, 1)
+}
@@ -0,0 +1,89 @@
+package recursor
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// delegation - серверы, авторитетные для зоны.
+type delegation struct {
+	zone string
+	// addrs - адреса серверов, известные из glue или найденные отдельно.
+	addrs []string
+	// names - имена серверов без известных адресов.
+	names []string
+	die   time.Time
+}
+
+func (d *delegation) shuffled() []string {
+	addrs := append([]string(nil), d.addrs...)
+	rand.Shuffle(len(addrs), func(i, j int) { addrs[i], addrs[j] = addrs[j], addrs[i] })
+	return addrs
+}
+
+// delegationCache хранит найденные точки делегирования, чтобы не начинать
+// каждый запрос с корня.
+type delegationCache struct {
+	mu    sync.RWMutex
+	zones map[string]*delegation
+	root  *delegation
+}
+
+func newDelegationCache(roots []string) *delegationCache {
+	return &delegationCache{
+		zones: make(map[string]*delegation),
+		root:  &delegation{zone: ".", addrs: roots},
+	}
+}
+
+// closest возвращает ближайшую к имени известную точку делегирования.
+func (c *delegationCache) closest(name string) *delegation {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	for off, end := 0, false; !end; off, end = dns.NextLabel(name, off) {
+		if d, ok := c.zones[name[off:]]; ok && d.die.After(now) {
+			return d
+		}
+	}
+
+	return c.root
+}
+
+func (c *delegationCache) put(d *delegation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.zones[d.zone] = d
+}
+
+func (c *delegationCache) len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.zones)
+}
+
+// cleanup удаляет устаревшие делегирования.
+func (c *delegationCache) cleanup() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for zone, d := range c.zones {
+		if d.die.Before(now) {
+			delete(c.zones, zone)
+		}
+	}
+}
+
+// inBailiwick сообщает, находится ли имя внутри зоны. Только такие glue
+// записи принимаются, чтобы сервер не мог подменить адреса чужих имен.
+func inBailiwick(name, zone string) bool {
+	return dns.IsSubDomain(zone, strings.ToLower(name))
+}
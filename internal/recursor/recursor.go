@@ -0,0 +1,451 @@
+package recursor
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/denisdubovitskiy/blackhole/internal/resolver"
+	"github.com/miekg/dns"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// Group - имя, под которым рекурсивные ответы попадают в историю.
+const Group = "recursive"
+
+const (
+	defaultTimeout = 2 * time.Second
+	// maxDepth ограничивает вложенность (поиск адресов NS, CNAME).
+	maxDepth = 8
+	// maxQueries ограничивает количество запросов на одно разрешение имени.
+	maxQueries = 64
+	// maxCNAMEs ограничивает длину цепочки CNAME.
+	maxCNAMEs = 8
+	// minDelegationTTL не дает делегированиям с нулевым TTL выпасть из кеша сразу.
+	minDelegationTTL = 30 * time.Second
+)
+
+var (
+	errBudgetExceeded = errors.New("recursor: query budget exceeded")
+	errNoServers      = errors.New("recursor: no reachable servers")
+)
+
+type Client interface {
+	Exchange(m *dns.Msg, address string) (r *dns.Msg, rtt time.Duration, err error)
+}
+
+type Config struct {
+	// Roots - адреса корневых серверов, по умолчанию встроенные root hints.
+	Roots []string
+	// Port - порт авторитетных серверов, по умолчанию 53.
+	Port string
+	// Timeout одного запроса к авторитетному серверу.
+	Timeout time.Duration
+	// DisableQNameMinimisation отключает минимизацию QNAME (RFC 9156).
+	DisableQNameMinimisation bool
+	Logger                   *zap.Logger
+}
+
+// Recursor разрешает имена самостоятельно, начиная с корневых серверов,
+// без пересылки запросов публичным резолверам.
+type Recursor struct {
+	client       Client
+	tcp          Client
+	port         string
+	minimise     bool
+	delegations  *delegationCache
+	logger       *zap.Logger
+	queries      *atomic.Int32
+	referrals    *atomic.Int32
+	resolveFails *atomic.Int32
+}
+
+func New(config Config) *Recursor {
+	if len(config.Roots) == 0 {
+		config.Roots = rootHints
+	}
+	if config.Port == "" {
+		config.Port = "53"
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	if config.Logger == nil {
+		config.Logger = zap.NewNop()
+	}
+
+	r := &Recursor{
+		client: &dns.Client{
+			Net:     "udp",
+			UDPSize: dns.DefaultMsgSize,
+			Timeout: config.Timeout,
+		},
+		tcp: &dns.Client{
+			Net:     "tcp",
+			Timeout: config.Timeout,
+		},
+		port:         config.Port,
+		minimise:     !config.DisableQNameMinimisation,
+		delegations:  newDelegationCache(config.Roots),
+		logger:       config.Logger,
+		queries:      atomic.NewInt32(0),
+		referrals:    atomic.NewInt32(0),
+		resolveFails: atomic.NewInt32(0),
+	}
+
+	if expvar.Get("blackhole_recursor") == nil {
+		expvar.Publish("blackhole_recursor", expvar.Func(func() any {
+			return r.dumpStats()
+		}))
+	}
+
+	return r
+}
+
+// RunPeriodicCleanup удаляет устаревшие делегирования из кеша.
+func (r *Recursor) RunPeriodicCleanup(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.delegations.cleanup()
+			}
+		}
+	}()
+}
+
+// resolution - состояние одного разрешения имени, общее для всех
+// вложенных запросов.
+type resolution struct {
+	queries int
+	// do - запрашивать ли DNSSEC-записи у авторитетных серверов.
+	do bool
+}
+
+func (r *Recursor) Lookup(req *dns.Msg) (*resolver.Answer, error) {
+	q := req.Question[0]
+
+	state := &resolution{}
+	if opt := req.IsEdns0(); opt != nil {
+		state.do = opt.Do()
+	}
+	resp, err := r.resolve(state, dns.CanonicalName(q.Name), q.Qtype, 0)
+	if err != nil {
+		r.resolveFails.Inc()
+		return nil, fmt.Errorf("can't resolve %s recursively: %v", q.Name, err)
+	}
+
+	answer := &dns.Msg{}
+	answer.SetRcode(req, resp.Rcode)
+	answer.RecursionAvailable = true
+	answer.Answer = resp.Answer
+	answer.Ns = resp.Ns
+	// вопрос возвращается в том регистре, в котором его задал клиент
+	answer.Question = req.Question
+
+	return &resolver.Answer{Msg: answer, Group: Group, Upstream: Group}, nil
+}
+
+// resolve находит ответ на вопрос (name, qtype), следуя делегированиям
+// и цепочкам CNAME.
+func (r *Recursor) resolve(state *resolution, name string, qtype uint16, depth int) (*dns.Msg, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("recursor: maximum depth exceeded while resolving %s", name)
+	}
+
+	var chain []dns.RR
+
+	for i := 0; i <= maxCNAMEs; i++ {
+		resp, err := r.iterate(state, name, qtype, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Answer = append(chain, resp.Answer...)
+
+		target, ok := danglingCNAME(resp, name, qtype)
+		if !ok {
+			return resp, nil
+		}
+
+		chain = resp.Answer
+		name = target
+	}
+
+	return nil, fmt.Errorf("recursor: CNAME chain is too long")
+}
+
+// danglingCNAME сообщает, заканчивается ли ответ CNAME без записей
+// целевого типа: тогда цель нужно разрешать отдельно.
+func danglingCNAME(resp *dns.Msg, name string, qtype uint16) (string, bool) {
+	if resp.Rcode != dns.RcodeSuccess || qtype == dns.TypeCNAME {
+		return "", false
+	}
+
+	current := name
+	for changed := true; changed; {
+		changed = false
+		for _, rr := range resp.Answer {
+			if !strings.EqualFold(rr.Header().Name, current) {
+				continue
+			}
+			if rr.Header().Rrtype == qtype {
+				return "", false
+			}
+			if cname, ok := rr.(*dns.CNAME); ok {
+				current = dns.CanonicalName(cname.Target)
+				changed = true
+			}
+		}
+	}
+
+	if current == name {
+		return "", false
+	}
+	return current, true
+}
+
+// iterate спускается по делегированиям от ближайшей известной зоны
+// до авторитетного ответа на вопрос.
+func (r *Recursor) iterate(state *resolution, name string, qtype uint16, depth int) (*dns.Msg, error) {
+	// записи DS хранятся на стороне родительской зоны, поэтому спуск
+	// должен начинаться выше самого имени
+	closest := name
+	if qtype == dns.TypeDS && name != "." {
+		_, closest = splitFirst(name)
+	}
+
+	zone := r.delegations.closest(closest)
+	// position - имя, до которого уже дошла минимизация внутри зоны
+	position := zone.zone
+	minimise := r.minimise
+
+	for {
+		qname, qt := name, qtype
+		if minimise {
+			qname, qt = minimised(position, name, qtype)
+		}
+
+		resp, err := r.query(state, zone, qname, qt, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		if next, ok := r.referral(state, resp, zone, name, depth); ok {
+			r.referrals.Inc()
+			zone, position = next, next.zone
+			continue
+		}
+
+		if qname == name {
+			return resp, nil
+		}
+
+		if resp.Rcode == dns.RcodeSuccess {
+			// имя существует, но отдельной зоны у него нет: спускаемся на
+			// следующую метку в пределах той же зоны
+			position = qname
+			continue
+		}
+
+		// NXDOMAIN на промежуточном имени встречается у некорректных
+		// серверов, переспрашиваем полное имя
+		minimise = false
+	}
+}
+
+// splitFirst отделяет первую метку имени.
+func splitFirst(name string) (string, string) {
+	next, end := dns.NextLabel(name, 0)
+	if end {
+		return name, "."
+	}
+	return name[:next], name[next:]
+}
+
+// minimised возвращает имя на одну метку длиннее зоны (RFC 9156).
+// Для промежуточных имен используется тип A: некоторые серверы
+// неправильно отвечают на запросы NS.
+func minimised(zone, name string, qtype uint16) (string, uint16) {
+	zoneLabels := dns.CountLabel(zone)
+	labels := dns.SplitDomainName(name)
+
+	if len(labels) <= zoneLabels+1 {
+		return name, qtype
+	}
+
+	return dns.Fqdn(strings.Join(labels[len(labels)-zoneLabels-1:], ".")), dns.TypeA
+}
+
+// referral разбирает ответ-делегирование и возвращает дочернюю зону.
+func (r *Recursor) referral(state *resolution, resp *dns.Msg, parent *delegation, name string, depth int) (*delegation, bool) {
+	if resp.Rcode != dns.RcodeSuccess || len(resp.Answer) > 0 || resp.Authoritative {
+		return nil, false
+	}
+
+	child := &delegation{}
+	var ttl uint32
+
+	for _, rr := range resp.Ns {
+		ns, ok := rr.(*dns.NS)
+		if !ok {
+			continue
+		}
+
+		zone := dns.CanonicalName(ns.Hdr.Name)
+		// делегирование должно вести вниз от текущей зоны к искомому имени
+		if zone == parent.zone || !dns.IsSubDomain(parent.zone, zone) || !dns.IsSubDomain(zone, name) {
+			continue
+		}
+		if child.zone != "" && child.zone != zone {
+			continue
+		}
+
+		child.zone = zone
+		child.names = append(child.names, dns.CanonicalName(ns.Ns))
+		if ttl == 0 || ns.Hdr.Ttl < ttl {
+			ttl = ns.Hdr.Ttl
+		}
+	}
+
+	if child.zone == "" {
+		return nil, false
+	}
+
+	// glue принимается только для имен внутри делегирующей зоны
+	glue := make(map[string]bool)
+	for _, rr := range resp.Extra {
+		owner := dns.CanonicalName(rr.Header().Name)
+		if !inBailiwick(owner, parent.zone) {
+			continue
+		}
+		for _, nsName := range child.names {
+			if owner != nsName {
+				continue
+			}
+			switch rr := rr.(type) {
+			case *dns.A:
+				child.addrs = append(child.addrs, rr.A.String())
+				glue[nsName] = true
+			case *dns.AAAA:
+				child.addrs = append(child.addrs, rr.AAAA.String())
+				glue[nsName] = true
+			}
+		}
+	}
+
+	var unresolved []string
+	for _, nsName := range child.names {
+		if !glue[nsName] {
+			unresolved = append(unresolved, nsName)
+		}
+	}
+	child.names = unresolved
+
+	// без glue адреса серверов приходится искать отдельно
+	if len(child.addrs) == 0 {
+		child.addrs = r.resolveNames(state, child.names, depth)
+	}
+	if len(child.addrs) == 0 {
+		r.logger.Debug("recursor: unable to find addresses of name servers", zap.String("zone", child.zone))
+		return nil, false
+	}
+
+	lifetime := time.Duration(ttl) * time.Second
+	if lifetime < minDelegationTTL {
+		lifetime = minDelegationTTL
+	}
+	child.die = time.Now().Add(lifetime)
+	r.delegations.put(child)
+
+	return child, true
+}
+
+func (r *Recursor) resolveNames(state *resolution, names []string, depth int) []string {
+	var addrs []string
+
+	for _, name := range names {
+		resp, err := r.resolve(state, name, dns.TypeA, depth+1)
+		if err != nil {
+			continue
+		}
+		for _, rr := range resp.Answer {
+			if a, ok := rr.(*dns.A); ok {
+				addrs = append(addrs, a.A.String())
+			}
+		}
+		if len(addrs) > 0 {
+			// одного сервера достаточно, остальные найдутся при необходимости
+			break
+		}
+	}
+
+	return addrs
+}
+
+// query отправляет нерекурсивный запрос серверам зоны по очереди.
+func (r *Recursor) query(state *resolution, zone *delegation, name string, qtype uint16, depth int) (*dns.Msg, error) {
+	addrs := zone.shuffled()
+	if len(addrs) == 0 {
+		addrs = r.resolveNames(state, zone.names, depth)
+	}
+
+	req := &dns.Msg{}
+	req.SetQuestion(name, qtype)
+	req.RecursionDesired = false
+	req.SetEdns0(dns.DefaultMsgSize, state.do)
+
+	err := errNoServers
+	for _, addr := range addrs {
+		if state.queries >= maxQueries {
+			return nil, errBudgetExceeded
+		}
+		state.queries++
+		r.queries.Inc()
+
+		resp, _, exchangeErr := r.client.Exchange(req, net.JoinHostPort(addr, r.port))
+		if exchangeErr == nil && resp.Truncated {
+			resp, _, exchangeErr = r.tcp.Exchange(req, net.JoinHostPort(addr, r.port))
+		}
+		if exchangeErr != nil {
+			err = exchangeErr
+			continue
+		}
+
+		switch resp.Rcode {
+		case dns.RcodeSuccess, dns.RcodeNameError:
+			return resp, nil
+		default:
+			// SERVFAIL, REFUSED: сервер неисправен или не обслуживает зону
+			err = fmt.Errorf("recursor: %s answered %s for %s", addr, dns.RcodeToString[resp.Rcode], name)
+		}
+	}
+
+	return nil, err
+}
+
+type stats struct {
+	Queries     int32 `json:"queries"`
+	Referrals   int32 `json:"referrals"`
+	Failures    int32 `json:"failures"`
+	Delegations int   `json:"delegations"`
+}
+
+func (r *Recursor) dumpStats() stats {
+	return stats{
+		Queries:     r.queries.Load(),
+		Referrals:   r.referrals.Load(),
+		Failures:    r.resolveFails.Load(),
+		Delegations: r.delegations.len(),
+	}
+}
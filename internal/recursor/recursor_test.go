@@ -0,0 +1,288 @@
+package recursor
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+// authority - упрощенный авторитетный сервер для тестовой иерархии.
+type authority struct {
+	records []dns.RR
+	// cuts - делегирования дочерних зон: NS записи и glue.
+	cuts map[string][]dns.RR
+
+	mu      sync.Mutex
+	queries []string
+}
+
+func newAuthority(records ...string) *authority {
+	a := &authority{cuts: make(map[string][]dns.RR)}
+	for _, record := range records {
+		a.records = append(a.records, mustRR(record))
+	}
+	return a
+}
+
+func (a *authority) delegate(zone string, records ...string) *authority {
+	for _, record := range records {
+		a.cuts[zone] = append(a.cuts[zone], mustRR(record))
+	}
+	return a
+}
+
+func (a *authority) seen() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return append([]string(nil), a.queries...)
+}
+
+func (a *authority) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	q := req.Question[0]
+	name := dns.CanonicalName(q.Name)
+
+	a.mu.Lock()
+	a.queries = append(a.queries, name)
+	a.mu.Unlock()
+
+	resp := &dns.Msg{}
+	resp.SetReply(req)
+
+	for zone, rrs := range a.cuts {
+		if !dns.IsSubDomain(zone, name) {
+			continue
+		}
+		for _, rr := range rrs {
+			if rr.Header().Rrtype == dns.TypeNS {
+				resp.Ns = append(resp.Ns, rr)
+			} else {
+				resp.Extra = append(resp.Extra, rr)
+			}
+		}
+		_ = w.WriteMsg(resp)
+		return
+	}
+
+	resp.Authoritative = true
+	exists := false
+	for _, rr := range a.records {
+		owner := rr.Header().Name
+		if dns.IsSubDomain(name, owner) {
+			exists = true
+		}
+		if owner != name {
+			continue
+		}
+		if rr.Header().Rrtype == q.Qtype || rr.Header().Rrtype == dns.TypeCNAME {
+			resp.Answer = append(resp.Answer, rr)
+		}
+	}
+	if !exists {
+		resp.Rcode = dns.RcodeNameError
+	}
+
+	_ = w.WriteMsg(resp)
+}
+
+func mustRR(s string) dns.RR {
+	rr, err := dns.NewRR(s)
+	if err != nil {
+		panic(err)
+	}
+	return rr
+}
+
+// serve запускает серверы на разных loopback адресах с общим портом.
+func serve(t *testing.T, servers map[string]dns.Handler) string {
+	t.Helper()
+
+	for attempt := 0; attempt < 10; attempt++ {
+		port, conns, ok := listen(t, servers)
+		if !ok {
+			continue
+		}
+
+		for addr, conn := range conns {
+			srv := &dns.Server{PacketConn: conn, Handler: servers[addr]}
+			go func() { _ = srv.ActivateAndServe() }()
+			t.Cleanup(func() { _ = srv.Shutdown() })
+		}
+
+		return port
+	}
+
+	t.Fatal("unable to find a free port shared by all test servers")
+	return ""
+}
+
+func listen(t *testing.T, servers map[string]dns.Handler) (string, map[string]net.PacketConn, bool) {
+	port := "0"
+	conns := make(map[string]net.PacketConn)
+
+	for addr := range servers {
+		conn, err := net.ListenPacket("udp", net.JoinHostPort(addr, port))
+		if err != nil {
+			for _, c := range conns {
+				_ = c.Close()
+			}
+			if strings.Contains(err.Error(), "assign requested address") {
+				t.Skipf("loopback address %s is not available: %v", addr, err)
+			}
+			return "", nil, false
+		}
+		conns[addr] = conn
+		_, port, _ = net.SplitHostPort(conn.LocalAddr().String())
+	}
+
+	return port, conns, true
+}
+
+type hierarchy struct {
+	root, tld, auth *authority
+	recursor        *Recursor
+}
+
+func newHierarchy(t *testing.T, config Config) *hierarchy {
+	h := &hierarchy{
+		root: newAuthority().delegate("test.",
+			"test. 3600 IN NS ns.test.",
+			"ns.test. 3600 IN A 127.0.0.3",
+		),
+		tld: newAuthority().
+			delegate("example.test.",
+				"example.test. 3600 IN NS ns1.example.test.",
+				"ns1.example.test. 3600 IN A 127.0.0.4",
+				// glue вне делегирующей зоны должен игнорироваться
+				"ns1.example.test.evil. 3600 IN A 127.0.0.9",
+			).
+			delegate("noglue.test.",
+				"noglue.test. 3600 IN NS ns1.example.test.",
+			),
+		auth: newAuthority(
+			"ns1.example.test. 3600 IN A 127.0.0.4",
+			"www.example.test. 300 IN A 192.0.2.1",
+			"alias.example.test. 300 IN CNAME www.example.test.",
+			"a.b.example.test. 300 IN A 192.0.2.3",
+			"host.noglue.test. 300 IN A 192.0.2.2",
+			"cross.noglue.test. 300 IN CNAME www.example.test.",
+		),
+	}
+
+	config.Port = serve(t, map[string]dns.Handler{
+		"127.0.0.2": h.root,
+		"127.0.0.3": h.tld,
+		"127.0.0.4": h.auth,
+	})
+	config.Roots = []string{"127.0.0.2"}
+	h.recursor = New(config)
+
+	return h
+}
+
+func lookup(t *testing.T, r *Recursor, name string, qtype uint16) *dns.Msg {
+	t.Helper()
+
+	req := &dns.Msg{}
+	req.SetQuestion(name, qtype)
+
+	answer, err := r.Lookup(req)
+	require.NoError(t, err)
+	require.Equal(t, Group, answer.Group)
+	require.Equal(t, req.Id, answer.Msg.Id)
+	require.True(t, answer.Msg.RecursionAvailable)
+
+	return answer.Msg
+}
+
+func addresses(msg *dns.Msg) []string {
+	var addrs []string
+	for _, rr := range msg.Answer {
+		if a, ok := rr.(*dns.A); ok {
+			addrs = append(addrs, a.A.String())
+		}
+	}
+	return addrs
+}
+
+func TestRecursorReferralsAndGlue(t *testing.T) {
+	h := newHierarchy(t, Config{})
+
+	resp := lookup(t, h.recursor, "www.example.test.", dns.TypeA)
+	require.Equal(t, dns.RcodeSuccess, resp.Rcode)
+	require.Equal(t, []string{"192.0.2.1"}, addresses(resp))
+
+	// делегирования закешированы, повторный запрос не доходит до корня
+	rootQueries := len(h.root.seen())
+	resp = lookup(t, h.recursor, "WWW.Example.test.", dns.TypeA)
+	require.Equal(t, []string{"192.0.2.1"}, addresses(resp))
+	require.Equal(t, "WWW.Example.test.", resp.Question[0].Name)
+	require.Len(t, h.root.seen(), rootQueries)
+	require.Equal(t, 2, h.recursor.delegations.len())
+}
+
+func TestRecursorQNameMinimisation(t *testing.T) {
+	h := newHierarchy(t, Config{})
+
+	resp := lookup(t, h.recursor, "a.b.example.test.", dns.TypeA)
+	require.Equal(t, []string{"192.0.2.3"}, addresses(resp))
+
+	require.Equal(t, []string{"test."}, h.root.seen())
+	require.Equal(t, []string{"example.test."}, h.tld.seen())
+	// пустой нетерминальный узел b.example.test. проходится по одной метке
+	require.Equal(t, []string{"b.example.test.", "a.b.example.test."}, h.auth.seen())
+}
+
+func TestRecursorWithoutQNameMinimisation(t *testing.T) {
+	h := newHierarchy(t, Config{DisableQNameMinimisation: true})
+
+	lookup(t, h.recursor, "www.example.test.", dns.TypeA)
+	require.Equal(t, []string{"www.example.test."}, h.root.seen())
+}
+
+func TestRecursorNoGlue(t *testing.T) {
+	h := newHierarchy(t, Config{})
+
+	resp := lookup(t, h.recursor, "host.noglue.test.", dns.TypeA)
+	require.Equal(t, []string{"192.0.2.2"}, addresses(resp))
+}
+
+func TestRecursorCNAME(t *testing.T) {
+	h := newHierarchy(t, Config{})
+
+	resp := lookup(t, h.recursor, "alias.example.test.", dns.TypeA)
+	require.Len(t, resp.Answer, 2)
+	require.Equal(t, []string{"192.0.2.1"}, addresses(resp))
+
+	// цель CNAME в другой зоне разрешается отдельно
+	resp = lookup(t, h.recursor, "cross.noglue.test.", dns.TypeA)
+	require.Len(t, resp.Answer, 2)
+	require.IsType(t, &dns.CNAME{}, resp.Answer[0])
+	require.Equal(t, []string{"192.0.2.1"}, addresses(resp))
+}
+
+func TestRecursorNXDomain(t *testing.T) {
+	h := newHierarchy(t, Config{})
+
+	resp := lookup(t, h.recursor, "missing.example.test.", dns.TypeA)
+	require.Equal(t, dns.RcodeNameError, resp.Rcode)
+	require.Empty(t, resp.Answer)
+
+	resp = lookup(t, h.recursor, "nothing.invalid.", dns.TypeA)
+	require.Equal(t, dns.RcodeNameError, resp.Rcode)
+}
+
+func TestRecursorUnreachable(t *testing.T) {
+	r := New(Config{Roots: []string{"127.0.0.1"}, Port: "1", Timeout: 100 * time.Millisecond})
+
+	req := &dns.Msg{}
+	req.SetQuestion("example.test.", dns.TypeA)
+
+	_, err := r.Lookup(req)
+	require.Error(t, err)
+}
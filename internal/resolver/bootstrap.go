@@ -0,0 +1,131 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DefaultBootstrap - серверы, через которые определяются адреса
+// вышестоящих серверов, заданных доменным именем.
+var DefaultBootstrap = []string{"8.8.8.8:53", "1.1.1.1:53"}
+
+// bootstrap разрешает имена вышестоящих серверов через обычный DNS,
+// чтобы не зависеть от системного резолвера (которым может быть
+// сам blackhole).
+type bootstrap struct {
+	servers []string
+	client  *dns.Client
+
+	mu    sync.Mutex
+	cache map[string]bootstrapEntry
+}
+
+type bootstrapEntry struct {
+	addrs []string
+	die   time.Time
+}
+
+func newBootstrap(servers []string) *bootstrap {
+	if len(servers) == 0 {
+		servers = DefaultBootstrap
+	}
+
+	normalized := make([]string, len(servers))
+	for i, s := range servers {
+		normalized[i] = withDefaultPort(s, "53")
+	}
+
+	return &bootstrap{
+		servers: normalized,
+		client:  &dns.Client{Net: ProtocolUDP, Timeout: 5 * time.Second},
+		cache:   make(map[string]bootstrapEntry),
+	}
+}
+
+// dialContext подходит для net/http.Transport и tls.Dialer: имя хоста
+// заменяется на его адрес, полученный через bootstrap-серверы.
+func (b *bootstrap) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := b.resolve(host)
+	if err != nil {
+		return nil, err
+	}
+
+	var dialer net.Dialer
+	for _, addr := range addrs {
+		var conn net.Conn
+		conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+
+	return nil, err
+}
+
+func (b *bootstrap) resolve(host string) ([]string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{host}, nil
+	}
+
+	b.mu.Lock()
+	entry, ok := b.cache[host]
+	b.mu.Unlock()
+	if ok && entry.die.After(time.Now()) {
+		return entry.addrs, nil
+	}
+
+	var (
+		addrs  []string
+		minTTL uint32
+	)
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		req := &dns.Msg{}
+		req.SetQuestion(dns.Fqdn(host), qtype)
+
+		for _, server := range b.servers {
+			resp, _, err := b.client.Exchange(req, server)
+			if err != nil || resp.Rcode != dns.RcodeSuccess {
+				continue
+			}
+			for _, rr := range resp.Answer {
+				var ip net.IP
+				switch rr := rr.(type) {
+				case *dns.A:
+					ip = rr.A
+				case *dns.AAAA:
+					ip = rr.AAAA
+				default:
+					continue
+				}
+				addrs = append(addrs, ip.String())
+				if minTTL == 0 || rr.Header().Ttl < minTTL {
+					minTTL = rr.Header().Ttl
+				}
+			}
+			break
+		}
+	}
+
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("resolver: unable to bootstrap %s", host)
+	}
+
+	b.mu.Lock()
+	b.cache[host] = bootstrapEntry{
+		addrs: addrs,
+		die:   time.Now().Add(time.Duration(minTTL) * time.Second),
+	}
+	b.mu.Unlock()
+
+	return addrs, nil
+}
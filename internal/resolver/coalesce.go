@@ -0,0 +1,137 @@
+package resolver
+
+import (
+	"expvar"
+	"strings"
+	"sync"
+
+	"github.com/miekg/dns"
+	"go.uber.org/atomic"
+)
+
+type Lookuper interface {
+	Lookup(req *dns.Msg) (*Answer, error)
+}
+
+// Coalescer объединяет одинаковые запросы, которые выполняются
+// одновременно: к вышестоящему серверу уходит только первый из них,
+// остальные дожидаются его ответа.
+type Coalescer struct {
+	next Lookuper
+
+	mu       sync.Mutex
+	inflight map[coalesceKey]*call
+
+	coalesced *atomic.Int32
+	lookups   *atomic.Int32
+}
+
+// coalesceKey - запросы, различающиеся только ID и регистром имени,
+// считаются одинаковыми. Бит DO учитывается, так как от него зависит
+// наличие DNSSEC-записей в ответе.
+type coalesceKey struct {
+	name   string
+	qtype  uint16
+	qclass uint16
+	do     bool
+}
+
+type call struct {
+	done   chan struct{}
+	answer *Answer
+	err    error
+}
+
+func NewCoalescer(next Lookuper) *Coalescer {
+	c := &Coalescer{
+		next:      next,
+		inflight:  make(map[coalesceKey]*call),
+		coalesced: atomic.NewInt32(0),
+		lookups:   atomic.NewInt32(0),
+	}
+
+	if expvar.Get("blackhole_coalescing") == nil {
+		expvar.Publish("blackhole_coalescing", expvar.Func(func() any {
+			return c.dumpStats()
+		}))
+	}
+
+	return c
+}
+
+func (c *Coalescer) Lookup(req *dns.Msg) (*Answer, error) {
+	key := newCoalesceKey(req)
+
+	c.mu.Lock()
+	if existing, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		c.coalesced.Inc()
+
+		<-existing.done
+		return personalize(existing, req)
+	}
+
+	cl := &call{done: make(chan struct{})}
+	c.inflight[key] = cl
+	c.mu.Unlock()
+
+	c.lookups.Inc()
+	cl.answer, cl.err = c.next.Lookup(req)
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	c.mu.Unlock()
+	close(cl.done)
+
+	return personalize(cl, req)
+}
+
+func newCoalesceKey(req *dns.Msg) coalesceKey {
+	q := req.Question[0]
+
+	key := coalesceKey{
+		name:   strings.ToLower(q.Name),
+		qtype:  q.Qtype,
+		qclass: q.Qclass,
+	}
+	if opt := req.IsEdns0(); opt != nil {
+		key.do = opt.Do()
+	}
+	return key
+}
+
+// personalize отдает каждому ожидающему собственную копию ответа с его ID
+// и вопросом: дальше ответ модифицируется (TTL), и общий экземпляр
+// привел бы к гонкам.
+func personalize(cl *call, req *dns.Msg) (*Answer, error) {
+	if cl.err != nil {
+		return nil, cl.err
+	}
+
+	msg := cl.answer.Msg.Copy()
+	msg.Id = req.Id
+	msg.Question = append([]dns.Question(nil), req.Question...)
+
+	answer := *cl.answer
+	answer.Msg = msg
+
+	return &answer, nil
+}
+
+type coalescerStats struct {
+	Lookups   int32 `json:"lookups"`
+	Coalesced int32 `json:"coalesced"`
+	Inflight  int   `json:"inflight"`
+}
+
+func (c *Coalescer) dumpStats() coalescerStats {
+	c.mu.Lock()
+	inflight := len(c.inflight)
+	c.mu.Unlock()
+
+	return coalescerStats{
+		Lookups:   c.lookups.Load(),
+		Coalesced: c.coalesced.Load(),
+		Inflight:  inflight,
+	}
+}
@@ -0,0 +1,87 @@
+package resolver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+// gatedLookuper сообщает о начале запроса в started и отвечает только
+// после закрытия release.
+type gatedLookuper struct {
+	calls   *atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (l gatedLookuper) Lookup(req *dns.Msg) (*Answer, error) {
+	l.calls.Inc()
+	l.started <- struct{}{}
+	<-l.release
+
+	resp := &dns.Msg{}
+	resp.SetReply(req)
+	return &Answer{Msg: resp, Group: DefaultGroup}, nil
+}
+
+type coalesceResult struct {
+	id     uint16
+	answer *Answer
+	err    error
+}
+
+func TestCoalescer(t *testing.T) {
+	next := gatedLookuper{
+		calls:   atomic.NewInt32(0),
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	c := NewCoalescer(next)
+
+	const waiters = 20
+
+	results := make(chan coalesceResult, waiters)
+	lookup := func(id uint16) {
+		req := &dns.Msg{}
+		req.SetQuestion("Example.COM.", dns.TypeA)
+		req.Id = id
+
+		answer, err := c.Lookup(req)
+		results <- coalesceResult{id: id, answer: answer, err: err}
+	}
+
+	// первый запрос уходит к вышестоящему серверу и ждет release
+	go lookup(1)
+	<-next.started
+
+	for i := 2; i <= waiters; i++ {
+		go lookup(uint16(i))
+	}
+	// остальные присоединяются к выполняющемуся запросу
+	require.Eventually(t, func() bool {
+		return c.coalesced.Load() == waiters-1
+	}, time.Second, time.Millisecond)
+	close(next.release)
+
+	for i := 0; i < waiters; i++ {
+		r := <-results
+		require.NoError(t, r.err)
+		require.Equal(t, r.id, r.answer.Msg.Id)
+		require.Equal(t, "Example.COM.", r.answer.Msg.Question[0].Name)
+	}
+
+	require.Equal(t, int32(1), next.calls.Load())
+
+	t.Run("DO bit is a part of the key", func(t *testing.T) {
+		plain := &dns.Msg{}
+		plain.SetQuestion("example.com.", dns.TypeA)
+
+		signed := plain.Copy()
+		signed.SetEdns0(1232, true)
+
+		require.NotEqual(t, newCoalesceKey(plain), newCoalesceKey(signed))
+	})
+}
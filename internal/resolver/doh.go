@@ -0,0 +1,120 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const dnsMessageContentType = "application/dns-message"
+
+// httpsClient реализует DNS-over-HTTPS (RFC 8484). Соединения
+// переиспользуются http.Transport.
+type httpsClient struct {
+	url    string
+	method string
+	http   *http.Client
+}
+
+func newHTTPSClient(u Upstream, config *tls.Config, boot *bootstrap) *httpsClient {
+	transport := &http.Transport{
+		DialContext:         boot.dialContext,
+		TLSClientConfig:     config,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConnsPerHost: maxIdleConns,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: u.Timeout,
+	}
+
+	return &httpsClient{
+		url:    u.Address,
+		method: u.Method,
+		http: &http.Client{
+			Transport: transport,
+			Timeout:   u.Timeout,
+		},
+	}
+}
+
+func (c *httpsClient) Exchange(m *dns.Msg, _ string) (*dns.Msg, time.Duration, error) {
+	start := time.Now()
+
+	// RFC 8484 рекомендует нулевой ID, чтобы ответы лучше кешировались
+	query := m.Copy()
+	query.Id = 0
+
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, 0, fmt.Errorf("resolver: unable to pack a query: %v", err)
+	}
+
+	req, err := c.newRequest(packed)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("resolver: unexpected status %s", res.Status)
+	}
+	if ct := res.Header.Get("Content-Type"); !strings.HasPrefix(ct, dnsMessageContentType) {
+		return nil, 0, fmt.Errorf("resolver: unexpected content type %q", ct)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, 0, fmt.Errorf("resolver: unable to read a response: %v", err)
+	}
+
+	resp := &dns.Msg{}
+	if err := resp.Unpack(body); err != nil {
+		return nil, 0, fmt.Errorf("resolver: unable to unpack a response: %v", err)
+	}
+	resp.Id = m.Id
+
+	return resp, time.Since(start), nil
+}
+
+func (c *httpsClient) newRequest(packed []byte) (*http.Request, error) {
+	ctx := context.Background()
+
+	if c.method == http.MethodGet {
+		sep := "?"
+		if strings.Contains(c.url, "?") {
+			sep = "&"
+		}
+		u := c.url + sep + "dns=" + base64.RawURLEncoding.EncodeToString(packed)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+		if err != nil {
+			return nil, fmt.Errorf("resolver: unable to compose request for %s: %v", c.url, err)
+		}
+		req.Header.Set("Accept", dnsMessageContentType)
+		return req, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(packed))
+	if err != nil {
+		return nil, fmt.Errorf("resolver: unable to compose request for %s: %v", c.url, err)
+	}
+	req.Header.Set("Content-Type", dnsMessageContentType)
+	req.Header.Set("Accept", dnsMessageContentType)
+	return req, nil
+}
+
+func (c *httpsClient) CloseIdleConnections() {
+	c.http.CloseIdleConnections()
+}
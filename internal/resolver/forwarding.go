@@ -0,0 +1,49 @@
+package resolver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// ForwardingRule направляет запросы к зоне Zone и ее поддоменам
+// в группу серверов Group.
+type ForwardingRule struct {
+	Zone  string `json:"zone"`
+	Group string `json:"group"`
+}
+
+func (r ForwardingRule) normalize() (ForwardingRule, error) {
+	r.Zone = dns.CanonicalName(strings.TrimSpace(r.Zone))
+	if _, ok := dns.IsDomainName(r.Zone); !ok {
+		return ForwardingRule{}, fmt.Errorf("resolver: invalid zone %q", r.Zone)
+	}
+	if r.Group == "" {
+		return ForwardingRule{}, fmt.Errorf("resolver: group of zone %s is empty", r.Zone)
+	}
+	return r, nil
+}
+
+// sortRules упорядочивает правила так, чтобы более длинные зоны
+// проверялись первыми: выигрывает самое длинное совпадение.
+func sortRules(rules []ForwardingRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		li, lj := dns.CountLabel(rules[i].Zone), dns.CountLabel(rules[j].Zone)
+		if li != lj {
+			return li > lj
+		}
+		return rules[i].Zone < rules[j].Zone
+	})
+}
+
+func match(rules []ForwardingRule, name string) (ForwardingRule, bool) {
+	name = dns.CanonicalName(name)
+	for _, rule := range rules {
+		if dns.IsSubDomain(rule.Zone, name) {
+			return rule, true
+		}
+	}
+	return ForwardingRule{}, false
+}
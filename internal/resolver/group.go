@@ -0,0 +1,222 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+// Group - именованный набор вышестоящих серверов со своей стратегией опроса.
+type Group struct {
+	Name      string
+	Strategy  string
+	Parallel  int
+	Upstreams []Upstream
+}
+
+type upstream struct {
+	Upstream
+	client Client
+	stats  *upstreamStats
+}
+
+type group struct {
+	name     string
+	strategy string
+	parallel int
+	rr       *roundRobin
+	logger   *zap.Logger
+
+	mu        sync.RWMutex
+	upstreams []*upstream
+}
+
+func newGroup(config Group, boot *bootstrap, previous *group, logger *zap.Logger) (*group, error) {
+	if config.Strategy == "" {
+		config.Strategy = DefaultStrategy
+	}
+	if config.Parallel <= 0 {
+		config.Parallel = DefaultParallel
+	}
+	if err := validateStrategy(config.Strategy); err != nil {
+		return nil, err
+	}
+	if len(config.Upstreams) == 0 {
+		return nil, fmt.Errorf("resolver: group %s must contain at least one upstream", config.Name)
+	}
+
+	g := &group{
+		name:     config.Name,
+		strategy: config.Strategy,
+		parallel: config.Parallel,
+		rr:       newRoundRobin(),
+		logger:   logger,
+	}
+
+	// статистика серверов, оставшихся в группе, сохраняется
+	stats := make(map[string]*upstreamStats)
+	if previous != nil {
+		for _, u := range previous.current() {
+			stats[u.String()] = u.stats
+		}
+	}
+
+	list := make([]*upstream, len(config.Upstreams))
+	for i, u := range config.Upstreams {
+		u, err := u.normalize()
+		if err != nil {
+			return nil, err
+		}
+
+		s, ok := stats[u.String()]
+		if !ok {
+			s = newUpstreamStats()
+		}
+		client, err := u.newClient(boot)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = &upstream{Upstream: u, client: client, stats: s}
+	}
+
+	// серверы с большим весом опрашиваются первыми
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Weight > list[j].Weight
+	})
+	g.upstreams = list
+
+	return g, nil
+}
+
+func (g *group) current() []*upstream {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.upstreams
+}
+
+func (g *group) config() Group {
+	upstreams := g.current()
+
+	config := Group{
+		Name:      g.name,
+		Strategy:  g.strategy,
+		Parallel:  g.parallel,
+		Upstreams: make([]Upstream, len(upstreams)),
+	}
+	for i, u := range upstreams {
+		config.Upstreams[i] = u.Upstream
+	}
+	return config
+}
+
+// close закрывает простаивающие соединения группы, которая больше не используется.
+func (g *group) close() {
+	for _, u := range g.current() {
+		if c, ok := u.client.(idleCloser); ok {
+			c.CloseIdleConnections()
+		}
+	}
+}
+
+func (g *group) lookup(req *dns.Msg) (*dns.Msg, *upstream, error) {
+	upstreams := order(g.strategy, g.current(), g.rr)
+
+	var (
+		resp *dns.Msg
+		from *upstream
+		err  = errors.New("no upstreams")
+	)
+
+	if g.strategy == StrategyParallel {
+		n := g.parallel
+		if n > len(upstreams) {
+			n = len(upstreams)
+		}
+		resp, from, err = g.race(req, upstreams[:n])
+		upstreams = upstreams[n:]
+	}
+
+	// оставшиеся серверы опрашиваются по очереди до первого ответа
+	for i := 0; resp == nil && i < len(upstreams); i++ {
+		from = upstreams[i]
+		resp, err = g.exchange(req, from)
+	}
+
+	if resp == nil {
+		return nil, nil, err
+	}
+
+	return resp, from, nil
+}
+
+// race отправляет запрос всем серверам одновременно и возвращает первый
+// успешный ответ.
+func (g *group) race(req *dns.Msg, upstreams []*upstream) (*dns.Msg, *upstream, error) {
+	type result struct {
+		resp *dns.Msg
+		from *upstream
+		err  error
+	}
+
+	// буфер на все ответы, чтобы опоздавшие горутины не блокировались
+	results := make(chan result, len(upstreams))
+	for _, u := range upstreams {
+		go func(u *upstream) {
+			resp, err := g.exchange(req, u)
+			results <- result{resp: resp, from: u, err: err}
+		}(u)
+	}
+
+	err := errors.New("no upstreams")
+	for range upstreams {
+		res := <-results
+		if res.err == nil {
+			return res.resp, res.from, nil
+		}
+		err = res.err
+	}
+
+	return nil, nil, err
+}
+
+func (g *group) exchange(req *dns.Msg, u *upstream) (*dns.Msg, error) {
+	resp, rtt, err := u.client.Exchange(req, u.Address)
+	if err == nil && resp.Rcode == dns.RcodeServerFailure {
+		err = errors.New("server failure")
+	}
+	u.stats.observe(rtt, err, u.Timeout)
+
+	if err != nil {
+		g.logger.Debug(
+			"resolver: upstream exchange failed",
+			zap.String("group", g.name),
+			zap.String("upstream", u.String()),
+			zap.String("domain", req.Question[0].Name),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("%s: %v", u, err)
+	}
+
+	return resp, nil
+}
+
+type groupStatsDump struct {
+	Strategy  string              `json:"strategy"`
+	Upstreams []upstreamStatsDump `json:"upstreams"`
+}
+
+func (g *group) dumpStats() groupStatsDump {
+	upstreams := g.current()
+
+	stats := make([]upstreamStatsDump, len(upstreams))
+	for i, u := range upstreams {
+		stats[i] = u.stats.dump(u.String())
+	}
+
+	return groupStatsDump{Strategy: g.strategy, Upstreams: stats}
+}
@@ -0,0 +1,86 @@
+package resolver
+
+import (
+	"context"
+	"time"
+
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+// HealthCheck описывает фоновую проверку доступности вышестоящих серверов.
+// Нулевой Interval отключает проверки.
+type HealthCheck struct {
+	Interval time.Duration
+	Domain   string
+	// Failures - количество неудачных проверок подряд, после которого
+	// сервер исключается из опроса.
+	Failures int
+}
+
+const (
+	DefaultHealthCheckDomain   = "."
+	DefaultHealthCheckFailures = 3
+)
+
+func (h HealthCheck) withDefaults() HealthCheck {
+	if h.Domain == "" {
+		h.Domain = DefaultHealthCheckDomain
+	}
+	if h.Failures <= 0 {
+		h.Failures = DefaultHealthCheckFailures
+	}
+	h.Domain = dns.Fqdn(h.Domain)
+	return h
+}
+
+// RunHealthChecks периодически опрашивает все серверы до отмены контекста.
+func (r *Resolver) RunHealthChecks(ctx context.Context) {
+	if r.healthCheck.Interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(r.healthCheck.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.checkAll()
+			}
+		}
+	}()
+}
+
+func (r *Resolver) checkAll() {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, g := range r.groups {
+		for _, u := range g.current() {
+			go r.check(u)
+		}
+	}
+}
+
+func (r *Resolver) check(u *upstream) {
+	probe := &dns.Msg{}
+	probe.SetQuestion(r.healthCheck.Domain, dns.TypeNS)
+
+	resp, _, err := u.client.Exchange(probe, u.Address)
+	ok := err == nil && (resp.Rcode == dns.RcodeSuccess || resp.Rcode == dns.RcodeNameError)
+
+	healthy, changed := u.stats.probed(ok, r.healthCheck.Failures)
+	if !changed {
+		return
+	}
+
+	if healthy {
+		r.logger.Info("resolver: upstream is back up", zap.String("upstream", u.String()))
+	} else {
+		r.logger.Warn("resolver: upstream is down", zap.String("upstream", u.String()), zap.Error(err))
+	}
+}
@@ -1,81 +1,303 @@
 package resolver
 
 import (
-	"errors"
-	"log"
+	"expvar"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/miekg/dns"
+	"go.uber.org/zap"
 )
 
+// DefaultGroup - группа серверов, обслуживающая запросы, для которых
+// не нашлось правила пересылки.
+const DefaultGroup = "default"
+
 type Client interface {
 	Exchange(m *dns.Msg, address string) (r *dns.Msg, rtt time.Duration, err error)
 }
 
+type Config struct {
+	// Upstreams, Strategy и Parallel описывают группу DefaultGroup.
+	Upstreams []Upstream
+	// Strategy определяет порядок опроса серверов, по умолчанию DefaultStrategy.
+	Strategy string
+	// Parallel - количество серверов, опрашиваемых одновременно стратегией parallel.
+	Parallel int
+	// Groups - дополнительные группы серверов для правил пересылки.
+	Groups     []Group
+	Forwarding []ForwardingRule
+
+	HealthCheck HealthCheck
+	// Bootstrap - серверы для определения адресов вышестоящих серверов,
+	// заданных именем, по умолчанию DefaultBootstrap.
+	Bootstrap []string
+	Logger    *zap.Logger
+}
+
+// Answer - ответ вышестоящего сервера и сведения о том, кто его дал.
+type Answer struct {
+	Msg      *dns.Msg
+	Group    string
+	Upstream string
+	// Validation - результат проверки DNSSEC, пустой, если ответ
+	// не проверялся.
+	Validation Validation
+}
+
+type Validation string
+
+const (
+	ValidationSecure   Validation = "secure"
+	ValidationInsecure Validation = "insecure"
+	ValidationBogus    Validation = "bogus"
+)
+
 type Resolver struct {
-	servers []string
-	client  Client
+	healthCheck HealthCheck
+	bootstrap   *bootstrap
+	logger      *zap.Logger
+
+	mu     sync.RWMutex
+	groups map[string]*group
+	rules  []ForwardingRule
 }
 
-func New(servers []string) *Resolver {
-	return &Resolver{
-		servers: servers,
-		client: &dns.Client{
-			Net:          "tcp",
-			ReadTimeout:  time.Second * 5,
-			WriteTimeout: time.Second * 5,
-		},
+func New(config Config) (*Resolver, error) {
+	r := &Resolver{
+		healthCheck: config.HealthCheck.withDefaults(),
+		bootstrap:   newBootstrap(config.Bootstrap),
+		logger:      config.Logger,
+		groups:      make(map[string]*group),
+	}
+	if r.logger == nil {
+		r.logger = zap.NewNop()
+	}
+
+	groups := append([]Group{{
+		Name:      DefaultGroup,
+		Strategy:  config.Strategy,
+		Parallel:  config.Parallel,
+		Upstreams: config.Upstreams,
+	}}, config.Groups...)
+
+	for _, g := range groups {
+		if err := r.SetGroup(g); err != nil {
+			return nil, err
+		}
+	}
+	for _, rule := range config.Forwarding {
+		if err := r.SetForwardingRule(rule); err != nil {
+			return nil, err
+		}
 	}
+
+	if expvar.Get("blackhole_upstreams") == nil {
+		expvar.Publish("blackhole_upstreams", expvar.Func(func() any {
+			return r.dumpStats()
+		}))
+	}
+
+	return r, nil
 }
 
-func (r *Resolver) Lookup(req *dns.Msg) (*dns.Msg, error) {
-	qName := req.Question[0].Name
+// Upstreams возвращает серверы группы по умолчанию.
+func (r *Resolver) Upstreams() []Upstream {
+	return r.group(DefaultGroup).config().Upstreams
+}
 
-	res := make(chan *dns.Msg, 1)
-	defer close(res)
-	var wg sync.WaitGroup
-	L := func(nameserver string) {
-		defer wg.Done()
-		rrsp, _, err := r.client.Exchange(req, nameserver)
-		if err != nil {
-			log.Printf("%s socket error on %s", qName, nameserver)
-			log.Printf("error:%s", err.Error())
-			return
+// Groups возвращает все группы серверов, группа по умолчанию идет первой.
+func (r *Resolver) Groups() []Group {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groups := make([]Group, 0, len(r.groups))
+	for _, g := range r.groups {
+		groups = append(groups, g.config())
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Name == DefaultGroup || groups[j].Name == DefaultGroup {
+			return groups[i].Name == DefaultGroup
 		}
-		if rrsp != nil && rrsp.Rcode != dns.RcodeSuccess {
-			if rrsp.Rcode == dns.RcodeServerFailure {
-				return
-			}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// SetGroup создает или заменяет группу серверов. Запросы, которые уже
+// выполняются, дорабатывают со старым составом группы.
+func (r *Resolver) SetGroup(config Group) error {
+	if config.Name == "" {
+		return fmt.Errorf("resolver: group name is empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := r.groups[config.Name]
+
+	g, err := newGroup(config, r.bootstrap, previous, r.logger)
+	if err != nil {
+		return err
+	}
+	r.groups[config.Name] = g
+
+	if previous != nil {
+		previous.close()
+	}
+
+	return nil
+}
+
+func (r *Resolver) DeleteGroup(name string) error {
+	if name == DefaultGroup {
+		return fmt.Errorf("resolver: group %s can not be deleted", DefaultGroup)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	g, ok := r.groups[name]
+	if !ok {
+		return fmt.Errorf("resolver: group %s does not exist", name)
+	}
+	for _, rule := range r.rules {
+		if rule.Group == name {
+			return fmt.Errorf("resolver: group %s is used by zone %s", name, rule.Zone)
 		}
-		select {
-		case res <- rrsp:
-		default:
+	}
+
+	delete(r.groups, name)
+	g.close()
+
+	return nil
+}
+
+func (r *Resolver) ForwardingRules() []ForwardingRule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]ForwardingRule(nil), r.rules...)
+}
+
+// SetForwardingRule добавляет правило пересылки или меняет группу
+// для уже существующей зоны.
+func (r *Resolver) SetForwardingRule(rule ForwardingRule) error {
+	rule, err := rule.normalize()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.groups[rule.Group]; !ok {
+		return fmt.Errorf("resolver: group %s does not exist", rule.Group)
+	}
+
+	rules := make([]ForwardingRule, 0, len(r.rules)+1)
+	for _, existing := range r.rules {
+		if existing.Zone != rule.Zone {
+			rules = append(rules, existing)
 		}
 	}
+	rules = append(rules, rule)
+	sortRules(rules)
+
+	r.rules = rules
+	return nil
+}
+
+func (r *Resolver) DeleteForwardingRule(zone string) error {
+	zone = dns.CanonicalName(zone)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rules := make([]ForwardingRule, 0, len(r.rules))
+	for _, existing := range r.rules {
+		if existing.Zone != zone {
+			rules = append(rules, existing)
+		}
+	}
+	if len(rules) == len(r.rules) {
+		return fmt.Errorf("resolver: zone %s is not forwarded", zone)
+	}
+
+	r.rules = rules
+	return nil
+}
+
+func (r *Resolver) group(name string) *group {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if g, ok := r.groups[name]; ok {
+		return g
+	}
+	return r.groups[DefaultGroup]
+}
 
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
+// route выбирает группу по самому длинному совпадению зоны.
+func (r *Resolver) route(name string) *group {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	// Start lookup on each nameserver top-down, in every second
-	for _, nameserver := range r.servers {
-		wg.Add(1)
-		go L(nameserver)
-		// but exit early, if we have an answer
-		select {
-		case r := <-res:
-			return r, nil
-		case <-ticker.C:
-			continue
+	if rule, ok := match(r.rules, name); ok {
+		if g, ok := r.groups[rule.Group]; ok {
+			return g
 		}
 	}
+	return r.groups[DefaultGroup]
+}
+
+func (r *Resolver) Lookup(req *dns.Msg) (*Answer, error) {
+	return r.lookup(req, r.route(req.Question[0].Name))
+}
+
+// InGroup возвращает Lookuper, который отправляет все запросы в группу
+// name в обход правил пересылки. Пока группы нет, запросы обслуживает
+// группа по умолчанию.
+func (r *Resolver) InGroup(name string) Lookuper {
+	return groupLookuper{resolver: r, name: name}
+}
+
+type groupLookuper struct {
+	resolver *Resolver
+	name     string
+}
+
+func (l groupLookuper) Lookup(req *dns.Msg) (*Answer, error) {
+	return l.resolver.lookup(req, l.resolver.group(l.name))
+}
+
+func (r *Resolver) lookup(req *dns.Msg, g *group) (*Answer, error) {
+	qName := req.Question[0].Name
+
+	resp, from, err := g.lookup(req)
+	if err != nil {
+		return nil, fmt.Errorf("can't resolve ip for %s via %s: %v", qName, g.name, err)
+	}
+
+	return &Answer{Msg: resp, Group: g.name, Upstream: from.String()}, nil
+}
+
+func (r *Resolver) dumpStats() any {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groups := make(map[string]groupStatsDump, len(r.groups))
+	for name, g := range r.groups {
+		groups[name] = g.dumpStats()
+	}
 
-	// wait for all the namservers to finish
-	wg.Wait()
-	select {
-	case r := <-res:
-		return r, nil
-	default:
-		return nil, errors.New("can't resolve ip for" + qName)
+	return struct {
+		Groups     map[string]groupStatsDump `json:"groups"`
+		Forwarding []ForwardingRule          `json:"forwarding"`
+	}{
+		Groups:     groups,
+		Forwarding: r.rules,
 	}
 }
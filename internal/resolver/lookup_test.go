@@ -0,0 +1,223 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClient struct {
+	mu    sync.Mutex
+	delay time.Duration
+	err   error
+	calls int
+}
+
+func (c *fakeClient) Exchange(m *dns.Msg, _ string) (*dns.Msg, time.Duration, error) {
+	c.mu.Lock()
+	c.calls++
+	c.mu.Unlock()
+
+	time.Sleep(c.delay)
+	if c.err != nil {
+		return nil, c.delay, c.err
+	}
+
+	resp := &dns.Msg{}
+	resp.SetReply(m)
+	return resp, c.delay, nil
+}
+
+func (c *fakeClient) Calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func newTestResolver(t *testing.T, strategy string, clients ...*fakeClient) *Resolver {
+	t.Helper()
+
+	upstreams := make([]Upstream, len(clients))
+	for i := range clients {
+		upstreams[i] = Upstream{Address: fmt.Sprintf("10.0.0.%d:53", i+1)}
+	}
+
+	r, err := New(Config{Upstreams: upstreams, Strategy: strategy})
+	require.NoError(t, err)
+
+	for i, u := range r.group(DefaultGroup).current() {
+		u.client = clients[i]
+	}
+	return r
+}
+
+func current(r *Resolver) []*upstream {
+	return r.group(DefaultGroup).current()
+}
+
+func newQuery() *dns.Msg {
+	req := &dns.Msg{}
+	req.SetQuestion("example.com.", dns.TypeA)
+	return req
+}
+
+func TestLookup(t *testing.T) {
+	t.Run("failover skips dead upstream without waiting", func(t *testing.T) {
+		dead := &fakeClient{err: errors.New("connection refused")}
+		alive := &fakeClient{}
+		r := newTestResolver(t, StrategyFailover, dead, alive)
+
+		start := time.Now()
+		resp, err := r.Lookup(newQuery())
+
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		require.Less(t, time.Since(start), time.Second)
+		require.Equal(t, 1, alive.Calls())
+	})
+
+	t.Run("all upstreams fail", func(t *testing.T) {
+		r := newTestResolver(t, StrategyFailover,
+			&fakeClient{err: errors.New("timeout")},
+			&fakeClient{err: errors.New("timeout")},
+		)
+
+		_, err := r.Lookup(newQuery())
+
+		require.Error(t, err)
+	})
+
+	t.Run("parallel returns the first answer", func(t *testing.T) {
+		slow := &fakeClient{delay: 500 * time.Millisecond}
+		fast := &fakeClient{}
+		r := newTestResolver(t, StrategyParallel, slow, fast)
+
+		start := time.Now()
+		_, err := r.Lookup(newQuery())
+
+		require.NoError(t, err)
+		require.Less(t, time.Since(start), 400*time.Millisecond)
+	})
+
+	t.Run("fastest prefers the lowest latency", func(t *testing.T) {
+		slow := &fakeClient{delay: 20 * time.Millisecond}
+		fast := &fakeClient{delay: time.Millisecond}
+		r := newTestResolver(t, StrategyFastest, slow, fast)
+		for _, u := range current(r) {
+			u.stats.observe(u.client.(*fakeClient).delay, nil, 0)
+		}
+
+		for i := 0; i < 5; i++ {
+			_, err := r.Lookup(newQuery())
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, 0, slow.Calls())
+		require.Equal(t, 5, fast.Calls())
+	})
+
+	t.Run("fastest moves a failing upstream last", func(t *testing.T) {
+		dead := &fakeClient{err: errors.New("connection refused")}
+		alive := &fakeClient{delay: time.Millisecond}
+		r := newTestResolver(t, StrategyFastest, dead, alive)
+
+		for i := 0; i < 10; i++ {
+			_, err := r.Lookup(newQuery())
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, 1, dead.Calls())
+		require.Equal(t, 10, alive.Calls())
+	})
+
+	t.Run("round-robin respects weights", func(t *testing.T) {
+		heavy, light := &fakeClient{}, &fakeClient{}
+		r := newTestResolver(t, StrategyRoundRobin, heavy, light)
+		current(r)[0].Weight = 3
+
+		for i := 0; i < 8; i++ {
+			_, err := r.Lookup(newQuery())
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, 6, heavy.Calls())
+		require.Equal(t, 2, light.Calls())
+	})
+}
+
+func TestHealthCheck(t *testing.T) {
+	dead := &fakeClient{err: errors.New("timeout")}
+	alive := &fakeClient{}
+	r := newTestResolver(t, StrategyFailover, dead, alive)
+
+	for i := 0; i < DefaultHealthCheckFailures; i++ {
+		r.check(current(r)[0])
+	}
+	require.False(t, current(r)[0].stats.Healthy())
+
+	_, err := r.Lookup(newQuery())
+	require.NoError(t, err)
+	require.Equal(t, DefaultHealthCheckFailures, dead.Calls())
+
+	dead.err = nil
+	r.check(current(r)[0])
+	require.True(t, current(r)[0].stats.Healthy())
+}
+
+func TestForwarding(t *testing.T) {
+	r, err := New(Config{
+		Upstreams: []Upstream{{Address: "10.0.0.1"}},
+		Groups: []Group{
+			{Name: "office", Upstreams: []Upstream{{Address: "10.1.0.1"}}},
+			{Name: "lab", Upstreams: []Upstream{{Address: "10.2.0.1"}}},
+		},
+		Forwarding: []ForwardingRule{
+			{Zone: "corp.internal", Group: "office"},
+			{Zone: "lab.corp.internal.", Group: "lab"},
+			{Zone: "168.192.in-addr.arpa.", Group: "office"},
+		},
+	})
+	require.NoError(t, err)
+
+	for name, client := range map[string]*fakeClient{DefaultGroup: {}, "office": {}, "lab": {}} {
+		r.group(name).current()[0].client = client
+	}
+
+	cases := map[string]string{
+		"example.com.":               DefaultGroup,
+		"corp.internal.":             "office",
+		"host.corp.internal.":        "office",
+		"HOST.Lab.Corp.Internal.":    "lab",
+		"notcorp.internal.":          DefaultGroup,
+		"10.1.168.192.in-addr.arpa.": "office",
+		"10.1.16.172.in-addr.arpa.":  DefaultGroup,
+	}
+
+	for name, group := range cases {
+		req := &dns.Msg{}
+		req.SetQuestion(name, dns.TypeA)
+
+		answer, err := r.Lookup(req)
+		require.NoError(t, err)
+		require.Equal(t, group, answer.Group, name)
+	}
+
+	// группа, выбранная политикой клиента, важнее правил пересылки
+	answer, err := r.InGroup("lab").Lookup(newQuery())
+	require.NoError(t, err)
+	require.Equal(t, "lab", answer.Group)
+
+	answer, err = r.InGroup("missing").Lookup(newQuery())
+	require.NoError(t, err)
+	require.Equal(t, DefaultGroup, answer.Group)
+
+	require.Error(t, r.DeleteGroup("office"))
+	require.NoError(t, r.DeleteForwardingRule("lab.corp.internal"))
+	require.NoError(t, r.DeleteGroup("lab"))
+	require.Error(t, r.SetForwardingRule(ForwardingRule{Zone: "x.", Group: "missing"}))
+}
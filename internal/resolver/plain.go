@@ -0,0 +1,331 @@
+package resolver
+
+import (
+	"encoding/binary"
+	"errors"
+	"expvar"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"go.uber.org/atomic"
+)
+
+// transports - счетчики запросов по транспортам, общие для всех серверов.
+var transports = newTransportStats()
+
+type transportStats struct {
+	UDP struct {
+		Queries   *atomic.Int64 `json:"queries"`
+		Truncated *atomic.Int64 `json:"truncated"`
+		Errors    *atomic.Int64 `json:"errors"`
+	} `json:"udp"`
+	TCP struct {
+		Queries     *atomic.Int64 `json:"queries"`
+		Connections *atomic.Int64 `json:"connections"`
+		Pipelined   *atomic.Int64 `json:"pipelined"`
+		Errors      *atomic.Int64 `json:"errors"`
+	} `json:"tcp"`
+}
+
+func newTransportStats() *transportStats {
+	s := &transportStats{}
+	s.UDP.Queries = atomic.NewInt64(0)
+	s.UDP.Truncated = atomic.NewInt64(0)
+	s.UDP.Errors = atomic.NewInt64(0)
+	s.TCP.Queries = atomic.NewInt64(0)
+	s.TCP.Connections = atomic.NewInt64(0)
+	s.TCP.Pipelined = atomic.NewInt64(0)
+	s.TCP.Errors = atomic.NewInt64(0)
+
+	if expvar.Get("blackhole_transports") == nil {
+		expvar.Publish("blackhole_transports", expvar.Func(func() any {
+			return s
+		}))
+	}
+
+	return s
+}
+
+// udpClient отправляет запросы по UDP и повторяет их по TCP, если ответ
+// не поместился в датаграмму (бит TC).
+type udpClient struct {
+	udp  *dns.Client
+	size uint16
+	tcp  *tcpClient
+}
+
+func newUDPClient(u Upstream) *udpClient {
+	return &udpClient{
+		udp: &dns.Client{
+			Net:     ProtocolUDP,
+			UDPSize: u.UDPSize,
+			Timeout: u.Timeout,
+		},
+		size: u.UDPSize,
+		tcp:  newTCPClient(u),
+	}
+}
+
+func (c *udpClient) Exchange(m *dns.Msg, address string) (*dns.Msg, time.Duration, error) {
+	start := time.Now()
+
+	transports.UDP.Queries.Inc()
+	resp, _, err := c.udp.Exchange(withUDPSize(m, c.size), address)
+	if err != nil {
+		transports.UDP.Errors.Inc()
+		return nil, 0, err
+	}
+
+	if resp.Truncated {
+		transports.UDP.Truncated.Inc()
+		if resp, _, err = c.tcp.Exchange(m, address); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// клиент без EDNS0 не должен получить OPT, добавленный нами
+	if m.IsEdns0() == nil {
+		resp.Extra = withoutOPT(resp.Extra)
+	}
+
+	return resp, time.Since(start), nil
+}
+
+func (c *udpClient) CloseIdleConnections() {
+	c.tcp.CloseIdleConnections()
+}
+
+// withUDPSize объявляет серверу размер буфера, который мы готовы принять.
+func withUDPSize(m *dns.Msg, size uint16) *dns.Msg {
+	m = m.Copy()
+	if opt := m.IsEdns0(); opt != nil {
+		opt.SetUDPSize(size)
+	} else {
+		m.SetEdns0(size, false)
+	}
+	return m
+}
+
+func withoutOPT(extra []dns.RR) []dns.RR {
+	filtered := extra[:0]
+	for _, rr := range extra {
+		if rr.Header().Rrtype != dns.TypeOPT {
+			filtered = append(filtered, rr)
+		}
+	}
+	return filtered
+}
+
+var errPipelineClosed = errors.New("resolver: tcp connection closed")
+
+// tcpClient держит постоянное TCP-соединение с сервером и отправляет
+// запросы по нему, не дожидаясь ответов на предыдущие (RFC 7766).
+type tcpClient struct {
+	timeout time.Duration
+
+	mu       sync.Mutex
+	pipeline *pipeline
+}
+
+func newTCPClient(u Upstream) *tcpClient {
+	return &tcpClient{timeout: u.Timeout}
+}
+
+func (c *tcpClient) Exchange(m *dns.Msg, address string) (*dns.Msg, time.Duration, error) {
+	start := time.Now()
+	transports.TCP.Queries.Inc()
+
+	p, reused, err := c.get(address)
+	if err != nil {
+		transports.TCP.Errors.Inc()
+		return nil, 0, err
+	}
+
+	resp, err := p.exchange(m, c.timeout)
+	if errors.Is(err, errPipelineClosed) && reused {
+		// сервер мог закрыть простаивающее соединение, пробуем новое
+		if p, _, err = c.get(address); err == nil {
+			resp, err = p.exchange(m, c.timeout)
+		}
+	}
+	if err != nil {
+		transports.TCP.Errors.Inc()
+		return nil, 0, err
+	}
+
+	return resp, time.Since(start), nil
+}
+
+// get возвращает действующее соединение или открывает новое.
+func (c *tcpClient) get(address string) (*pipeline, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pipeline != nil && c.pipeline.alive() {
+		transports.TCP.Pipelined.Inc()
+		return c.pipeline, true, nil
+	}
+
+	conn, err := net.DialTimeout(ProtocolTCP, address, c.timeout)
+	if err != nil {
+		return nil, false, err
+	}
+	transports.TCP.Connections.Inc()
+
+	c.pipeline = newPipeline(conn)
+	return c.pipeline, false, nil
+}
+
+func (c *tcpClient) CloseIdleConnections() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pipeline != nil {
+		c.pipeline.close(errPipelineClosed)
+		c.pipeline = nil
+	}
+}
+
+// pipeline - соединение, по которому одновременно выполняется несколько
+// запросов. Ответы сопоставляются с запросами по ID, поэтому каждому
+// запросу на время выполнения назначается уникальный ID.
+type pipeline struct {
+	conn net.Conn
+	wmu  sync.Mutex
+
+	mu      sync.Mutex
+	pending map[uint16]chan *dns.Msg
+	err     error
+	done    chan struct{}
+}
+
+func newPipeline(conn net.Conn) *pipeline {
+	p := &pipeline{
+		conn:    conn,
+		pending: make(map[uint16]chan *dns.Msg),
+		done:    make(chan struct{}),
+	}
+	go p.readLoop()
+	return p
+}
+
+func (p *pipeline) alive() bool {
+	select {
+	case <-p.done:
+		return false
+	default:
+		return true
+	}
+}
+
+func (p *pipeline) exchange(m *dns.Msg, timeout time.Duration) (*dns.Msg, error) {
+	id, ch, err := p.register()
+	if err != nil {
+		return nil, err
+	}
+	defer p.unregister(id)
+
+	req := m.Copy()
+	req.Id = id
+	packed, err := req.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	frame := make([]byte, 2+len(packed))
+	binary.BigEndian.PutUint16(frame, uint16(len(packed)))
+	copy(frame[2:], packed)
+
+	p.wmu.Lock()
+	_ = p.conn.SetWriteDeadline(time.Now().Add(timeout))
+	_, err = p.conn.Write(frame)
+	p.wmu.Unlock()
+	if err != nil {
+		p.close(errPipelineClosed)
+		return nil, errPipelineClosed
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-ch:
+		resp.Id = m.Id
+		return resp, nil
+	case <-p.done:
+		return nil, p.err
+	case <-timer.C:
+		return nil, errors.New("resolver: tcp query timed out")
+	}
+}
+
+func (p *pipeline) register() (uint16, chan *dns.Msg, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return 0, nil, p.err
+	}
+
+	for {
+		id := uint16(rand.Intn(1 << 16))
+		if _, busy := p.pending[id]; !busy {
+			ch := make(chan *dns.Msg, 1)
+			p.pending[id] = ch
+			return id, ch, nil
+		}
+	}
+}
+
+func (p *pipeline) unregister(id uint16) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.pending, id)
+}
+
+func (p *pipeline) readLoop() {
+	for {
+		var length [2]byte
+		if _, err := io.ReadFull(p.conn, length[:]); err != nil {
+			p.close(errPipelineClosed)
+			return
+		}
+
+		buf := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(p.conn, buf); err != nil {
+			p.close(errPipelineClosed)
+			return
+		}
+
+		resp := &dns.Msg{}
+		if err := resp.Unpack(buf); err != nil {
+			continue
+		}
+
+		// ответы на запросы, брошенные по таймауту, пропускаем
+		id := resp.Id
+		p.mu.Lock()
+		if ch, ok := p.pending[id]; ok {
+			delete(p.pending, id)
+			ch <- resp
+		}
+		p.mu.Unlock()
+	}
+}
+
+func (p *pipeline) close(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return
+	}
+	p.err = err
+	close(p.done)
+	_ = p.conn.Close()
+}
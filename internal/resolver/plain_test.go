@@ -0,0 +1,162 @@
+package resolver
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+// countingListener считает принятые TCP-соединения.
+type countingListener struct {
+	net.Listener
+	accepted *atomic.Int32
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.accepted.Inc()
+	}
+	return conn, err
+}
+
+// servePlain запускает UDP и TCP серверы на одном порту.
+func servePlain(t testing.TB, udp, tcp dns.HandlerFunc) (string, *atomic.Int32) {
+	t.Helper()
+
+	for attempt := 0; attempt < 10; attempt++ {
+		pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		listener, err := net.Listen("tcp", pc.LocalAddr().String())
+		if err != nil {
+			pc.Close()
+			continue
+		}
+
+		accepted := atomic.NewInt32(0)
+		udpServer := &dns.Server{PacketConn: pc, Handler: udp}
+		tcpServer := &dns.Server{Listener: &countingListener{Listener: listener, accepted: accepted}, Handler: tcp}
+		for _, srv := range []*dns.Server{udpServer, tcpServer} {
+			srv := srv
+			go func() { _ = srv.ActivateAndServe() }()
+			t.Cleanup(func() { _ = srv.Shutdown() })
+		}
+
+		return pc.LocalAddr().String(), accepted
+	}
+
+	t.Fatal("unable to find a port free for both udp and tcp")
+	return "", nil
+}
+
+func TestUDPTruncationFallback(t *testing.T) {
+	udpSize := atomic.NewUint32(0)
+
+	address, accepted := servePlain(t,
+		func(w dns.ResponseWriter, req *dns.Msg) {
+			udpSize.Store(uint32(req.IsEdns0().UDPSize()))
+
+			resp := &dns.Msg{}
+			resp.SetReply(req)
+			resp.Truncated = true
+			_ = w.WriteMsg(resp)
+		},
+		answer,
+	)
+
+	u, err := Upstream{Address: address}.normalize()
+	require.NoError(t, err)
+	client, err := u.newClient(nil)
+	require.NoError(t, err)
+
+	truncated := transports.UDP.Truncated.Load()
+
+	req := &dns.Msg{}
+	req.SetQuestion("example.com.", dns.TypeA)
+
+	resp, _, err := client.Exchange(req, u.Address)
+	require.NoError(t, err)
+	require.Equal(t, req.Id, resp.Id)
+	require.False(t, resp.Truncated)
+	require.Len(t, resp.Answer, 1)
+	// клиент не использовал EDNS0, значит и OPT в ответе быть не должно
+	require.Nil(t, resp.IsEdns0())
+
+	require.Equal(t, uint32(DefaultUDPSize), udpSize.Load())
+	require.Equal(t, truncated+1, transports.UDP.Truncated.Load())
+	require.Equal(t, int32(1), accepted.Load())
+}
+
+func TestTCPPipelining(t *testing.T) {
+	address, accepted := servePlain(t, answer, func(w dns.ResponseWriter, req *dns.Msg) {
+		time.Sleep(10 * time.Millisecond)
+		answer(w, req)
+	})
+
+	u, err := Upstream{Address: address, Protocol: ProtocolTCP}.normalize()
+	require.NoError(t, err)
+	client, err := u.newClient(nil)
+	require.NoError(t, err)
+
+	// первое соединение открывается до параллельных запросов
+	req := &dns.Msg{}
+	req.SetQuestion("example.com.", dns.TypeA)
+	_, _, err = client.Exchange(req, u.Address)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req := &dns.Msg{}
+			req.SetQuestion("example.com.", dns.TypeA)
+
+			resp, _, err := client.Exchange(req, u.Address)
+			require.NoError(t, err)
+			require.Equal(t, req.Id, resp.Id)
+		}()
+	}
+	wg.Wait()
+
+	// все запросы прошли по одному соединению
+	require.Equal(t, int32(1), accepted.Load())
+
+	// после закрытия соединения открывается новое
+	client.(idleCloser).CloseIdleConnections()
+	_, _, err = client.Exchange(req, u.Address)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), accepted.Load())
+}
+
+// BenchmarkTransports сравнивает UDP и постоянное TCP-соединение
+// с прежним подходом, где на каждый запрос открывалось новое соединение.
+func BenchmarkTransports(b *testing.B) {
+	address, _ := servePlain(b, answer, answer)
+
+	clients := map[string]Client{
+		"udp":           newUDPClient(Upstream{Timeout: time.Second, UDPSize: DefaultUDPSize}),
+		"tcp-pipelined": newTCPClient(Upstream{Timeout: time.Second}),
+		"tcp-per-query": &dns.Client{Net: ProtocolTCP, Timeout: time.Second},
+	}
+
+	for name, client := range clients {
+		b.Run(name, func(b *testing.B) {
+			req := &dns.Msg{}
+			req.SetQuestion("example.com.", dns.TypeA)
+
+			for i := 0; i < b.N; i++ {
+				if _, _, err := client.Exchange(req, address); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package resolver
+
+import (
+	"sync"
+	"time"
+)
+
+// ewmaWeight определяет, насколько сильно последнее измерение влияет на
+// сглаженную задержку.
+const ewmaWeight = 0.3
+
+// upstreamStats накапливает метрики обращений к одному вышестоящему серверу.
+type upstreamStats struct {
+	mu        sync.Mutex
+	latency   float64 // EWMA в наносекундах, 0 пока нет измерений
+	requests  int64
+	errors    int64
+	healthy   bool
+	failures  int
+	lastCheck time.Time
+}
+
+func newUpstreamStats() *upstreamStats {
+	return &upstreamStats{healthy: true}
+}
+
+// observe учитывает обращение к серверу. Ошибка считается обращением
+// длительностью не меньше penalty (таймаута сервера): иначе у сервера,
+// который не отвечает, не было бы измерений и стратегия fastest
+// опрашивала бы его первым.
+func (s *upstreamStats) observe(rtt time.Duration, err error, penalty time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	if err != nil {
+		s.errors++
+		if rtt < penalty {
+			rtt = penalty
+		}
+	}
+
+	if s.latency == 0 {
+		s.latency = float64(rtt)
+		return
+	}
+	s.latency = ewmaWeight*float64(rtt) + (1-ewmaWeight)*s.latency
+}
+
+func (s *upstreamStats) Latency() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return time.Duration(s.latency)
+}
+
+func (s *upstreamStats) Healthy() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.healthy
+}
+
+// probed учитывает результат проверки и сообщает, изменилось ли состояние
+// сервера. Сервер считается недоступным после threshold неудач подряд и
+// возвращается в строй после первой успешной проверки.
+func (s *upstreamStats) probed(ok bool, threshold int) (healthy, changed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastCheck = time.Now()
+
+	if ok {
+		s.failures = 0
+		changed = !s.healthy
+		s.healthy = true
+		return s.healthy, changed
+	}
+
+	s.failures++
+	if s.healthy && s.failures >= threshold {
+		s.healthy = false
+		changed = true
+	}
+	return s.healthy, changed
+}
+
+type upstreamStatsDump struct {
+	Upstream  string  `json:"upstream"`
+	Healthy   bool    `json:"healthy"`
+	LatencyMs float64 `json:"latency_ms"`
+	Requests  int64   `json:"requests"`
+	Errors    int64   `json:"errors"`
+	LastCheck string  `json:"last_check,omitempty"`
+}
+
+func (s *upstreamStats) dump(name string) upstreamStatsDump {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := upstreamStatsDump{
+		Upstream:  name,
+		Healthy:   s.healthy,
+		LatencyMs: s.latency / float64(time.Millisecond),
+		Requests:  s.requests,
+		Errors:    s.errors,
+	}
+	if !s.lastCheck.IsZero() {
+		d.LastCheck = s.lastCheck.Format(time.RFC3339)
+	}
+	return d
+}
@@ -0,0 +1,97 @@
+package resolver
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const (
+	// StrategyFastest опрашивает серверы в порядке возрастания сглаженной задержки.
+	StrategyFastest = "fastest"
+	// StrategyRoundRobin распределяет запросы между серверами пропорционально весу.
+	StrategyRoundRobin = "round-robin"
+	// StrategyParallel отправляет запрос сразу нескольким серверам и берет первый ответ.
+	StrategyParallel = "parallel"
+	// StrategyFailover опрашивает серверы строго по порядку (по убыванию веса).
+	StrategyFailover = "failover"
+
+	DefaultStrategy = StrategyFastest
+	DefaultParallel = 2
+)
+
+func validateStrategy(strategy string) error {
+	switch strategy {
+	case StrategyFastest, StrategyRoundRobin, StrategyParallel, StrategyFailover:
+		return nil
+	default:
+		return fmt.Errorf("resolver: unknown strategy %q", strategy)
+	}
+}
+
+// order возвращает серверы в том порядке, в котором их следует опрашивать.
+// Недоступные серверы отбрасываются, если есть хотя бы один доступный.
+func order(strategy string, upstreams []*upstream, rr *roundRobin) []*upstream {
+	healthy := make([]*upstream, 0, len(upstreams))
+	for _, u := range upstreams {
+		if u.stats.Healthy() {
+			healthy = append(healthy, u)
+		}
+	}
+	if len(healthy) == 0 {
+		healthy = append(healthy, upstreams...)
+	}
+
+	switch strategy {
+	case StrategyFastest, StrategyParallel:
+		latencies := make(map[*upstream]int64, len(healthy))
+		for _, u := range healthy {
+			latencies[u] = int64(u.stats.Latency())
+		}
+		// серверы без измерений идут первыми, чтобы получить по ним статистику
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return latencies[healthy[i]] < latencies[healthy[j]]
+		})
+	case StrategyRoundRobin:
+		first := rr.next(healthy)
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return healthy[i] == first && healthy[j] != first
+		})
+	}
+
+	return healthy
+}
+
+// roundRobin реализует плавный взвешенный round-robin (как в nginx).
+type roundRobin struct {
+	mu      sync.Mutex
+	current map[*upstream]int
+}
+
+func newRoundRobin() *roundRobin {
+	return &roundRobin{current: make(map[*upstream]int)}
+}
+
+func (r *roundRobin) next(upstreams []*upstream) *upstream {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		best  *upstream
+		total int
+	)
+
+	for _, u := range upstreams {
+		r.current[u] += u.Weight
+		total += u.Weight
+		if best == nil || r.current[u] > r.current[best] {
+			best = u
+		}
+	}
+
+	if best != nil {
+		r.current[best] -= total
+	}
+
+	return best
+}
@@ -0,0 +1,251 @@
+package resolver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// maxIdleConns - сколько соединений с одним DoT-сервером держать открытыми.
+const maxIdleConns = 4
+
+// newClient создает транспорт, соответствующий протоколу вышестоящего сервера.
+func (u Upstream) newClient(boot *bootstrap) (Client, error) {
+	switch u.Protocol {
+	case ProtocolTLS, ProtocolHTTPS:
+		config, err := u.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		if u.Protocol == ProtocolTLS {
+			return newTLSClient(u, config, boot), nil
+		}
+		return newHTTPSClient(u, config, boot), nil
+	case ProtocolTCP:
+		return newTCPClient(u), nil
+	default:
+		return newUDPClient(u), nil
+	}
+}
+
+func (u Upstream) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: u.ServerName,
+	}
+
+	if config.ServerName == "" {
+		host, err := u.host()
+		if err != nil {
+			return nil, err
+		}
+		config.ServerName = host
+	}
+
+	if u.CAFile != "" {
+		pem, err := os.ReadFile(u.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("resolver: unable to read CA file %s: %v", u.CAFile, err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("resolver: no certificates found in %s", u.CAFile)
+		}
+	}
+
+	if len(u.Pins) > 0 {
+		pins := make([][]byte, len(u.Pins))
+		for i, pin := range u.Pins {
+			pins[i], _ = decodePin(pin)
+		}
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			return verifyPins(state.PeerCertificates, pins)
+		}
+	}
+
+	return config, nil
+}
+
+func (u Upstream) host() (string, error) {
+	address := u.Address
+	if u.Protocol == ProtocolHTTPS {
+		address = strings.TrimPrefix(address, ProtocolHTTPS+"://")
+		if i := strings.IndexAny(address, "/?"); i >= 0 {
+			address = address[:i]
+		}
+		address = withDefaultPort(address, "443")
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("resolver: invalid upstream address %s: %v", u.Address, err)
+	}
+	return host, nil
+}
+
+func decodePin(pin string) ([]byte, error) {
+	pin = strings.TrimPrefix(pin, "sha256/")
+
+	raw, err := base64.StdEncoding.DecodeString(pin)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != sha256.Size {
+		return nil, fmt.Errorf("pin must be a base64 encoded SHA-256 hash")
+	}
+	return raw, nil
+}
+
+// Pin возвращает pin сертификата в формате, принимаемом Upstream.Pins.
+func Pin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func verifyPins(certs []*x509.Certificate, pins [][]byte) error {
+	for _, cert := range certs {
+		sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+		for _, pin := range pins {
+			if string(sum[:]) == string(pin) {
+				return nil
+			}
+		}
+	}
+	return errors.New("resolver: server certificate does not match any pin")
+}
+
+// idleCloser закрывает простаивающие соединения транспорта, который
+// больше не используется.
+type idleCloser interface {
+	CloseIdleConnections()
+}
+
+// tlsClient реализует DNS-over-TLS (RFC 7858) с переиспользованием соединений.
+type tlsClient struct {
+	address string
+	timeout time.Duration
+	config  *tls.Config
+	boot    *bootstrap
+
+	mu   sync.Mutex
+	idle []*dns.Conn
+}
+
+func newTLSClient(u Upstream, config *tls.Config, boot *bootstrap) *tlsClient {
+	return &tlsClient{
+		address: u.Address,
+		timeout: u.Timeout,
+		config:  config,
+		boot:    boot,
+	}
+}
+
+func (c *tlsClient) Exchange(m *dns.Msg, _ string) (*dns.Msg, time.Duration, error) {
+	start := time.Now()
+
+	conn, reused, err := c.conn()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := c.exchange(conn, m)
+	if err != nil && reused {
+		// сервер мог закрыть простаивающее соединение, пробуем новое
+		conn.Close()
+		if conn, err = c.dial(); err != nil {
+			return nil, 0, err
+		}
+		resp, err = c.exchange(conn, m)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, 0, err
+	}
+
+	c.release(conn)
+	return resp, time.Since(start), nil
+}
+
+func (c *tlsClient) exchange(conn *dns.Conn, m *dns.Msg) (*dns.Msg, error) {
+	deadline := time.Now().Add(c.timeout)
+	_ = conn.SetDeadline(deadline)
+
+	if err := conn.WriteMsg(m); err != nil {
+		return nil, err
+	}
+
+	for {
+		resp, err := conn.ReadMsg()
+		if err != nil {
+			return nil, err
+		}
+		// ответы на запросы, брошенные по таймауту, пропускаем
+		if resp.Id == m.Id {
+			return resp, nil
+		}
+	}
+}
+
+func (c *tlsClient) conn() (*dns.Conn, bool, error) {
+	c.mu.Lock()
+	if n := len(c.idle); n > 0 {
+		conn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return conn, true, nil
+	}
+	c.mu.Unlock()
+
+	conn, err := c.dial()
+	return conn, false, err
+}
+
+func (c *tlsClient) dial() (*dns.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	raw, err := c.boot.dialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := tls.Client(raw, c.config)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		raw.Close()
+		return nil, err
+	}
+
+	return &dns.Conn{Conn: conn}, nil
+}
+
+func (c *tlsClient) release(conn *dns.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.idle) >= maxIdleConns {
+		conn.Close()
+		return
+	}
+	c.idle = append(c.idle, conn)
+}
+
+func (c *tlsClient) CloseIdleConnections() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, conn := range c.idle {
+		conn.Close()
+	}
+	c.idle = nil
+}
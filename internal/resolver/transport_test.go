@@ -0,0 +1,238 @@
+package resolver
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+func answer(w dns.ResponseWriter, req *dns.Msg) {
+	resp := &dns.Msg{}
+	resp.SetReply(req)
+	resp.Answer = append(resp.Answer, &dns.A{
+		Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+		A:   net.ParseIP("10.0.0.1"),
+	})
+	_ = w.WriteMsg(resp)
+}
+
+// dohRequest - то, что сервер DNS-over-HTTPS получил от клиента.
+// Проверяется в горутине теста, а не в обработчике.
+type dohRequest struct {
+	method      string
+	contentType string
+	id          uint16
+}
+
+func newDoHServer(t *testing.T) (*httptest.Server, <-chan dohRequest) {
+	t.Helper()
+
+	requests := make(chan dohRequest, 16)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var packed []byte
+		var err error
+
+		switch r.Method {
+		case http.MethodGet:
+			packed, err = base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+		case http.MethodPost:
+			packed, err = io.ReadAll(r.Body)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		req := &dns.Msg{}
+		if err := req.Unpack(packed); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		select {
+		case requests <- dohRequest{method: r.Method, contentType: r.Header.Get("Content-Type"), id: req.Id}:
+		default:
+		}
+
+		rec := &recorder{}
+		answer(rec, req)
+		out, _ := rec.msg.Pack()
+
+		w.Header().Set("Content-Type", dnsMessageContentType)
+		_, _ = w.Write(out)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, requests
+}
+
+// recorder собирает ответ обработчика DNS вне сервера.
+type recorder struct {
+	dns.ResponseWriter
+	msg *dns.Msg
+}
+
+func (r *recorder) WriteMsg(m *dns.Msg) error {
+	r.msg = m
+	return nil
+}
+
+func writeCA(t *testing.T, cert []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0o600))
+	return path
+}
+
+func exchange(t *testing.T, u Upstream) (*dns.Msg, error) {
+	t.Helper()
+
+	u, err := u.normalize()
+	require.NoError(t, err)
+
+	client, err := u.newClient(newBootstrap(nil))
+	require.NoError(t, err)
+
+	req := &dns.Msg{}
+	req.SetQuestion("example.com.", dns.TypeA)
+	req.Id = 4242
+
+	resp, _, err := client.Exchange(req, u.Address)
+	if err == nil {
+		require.Equal(t, uint16(4242), resp.Id)
+	}
+	return resp, err
+}
+
+func TestDNSOverHTTPS(t *testing.T) {
+	srv, requests := newDoHServer(t)
+	cert := srv.Certificate()
+	ca := writeCA(t, cert.Raw)
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		t.Run(method, func(t *testing.T) {
+			resp, err := exchange(t, Upstream{
+				Address:  srv.URL + "/dns-query",
+				Protocol: ProtocolHTTPS,
+				Method:   method,
+				CAFile:   ca,
+			})
+
+			require.NoError(t, err)
+			require.Len(t, resp.Answer, 1)
+
+			got := <-requests
+			require.Equal(t, method, got.method)
+			require.Zero(t, got.id)
+			if method == http.MethodPost {
+				require.Equal(t, dnsMessageContentType, got.contentType)
+			}
+		})
+	}
+
+	t.Run("pinned certificate", func(t *testing.T) {
+		_, err := exchange(t, Upstream{
+			Address:  srv.URL + "/dns-query",
+			Protocol: ProtocolHTTPS,
+			CAFile:   ca,
+			Pins:     []string{Pin(cert)},
+		})
+
+		require.NoError(t, err)
+		<-requests
+	})
+
+	t.Run("pin mismatch", func(t *testing.T) {
+		_, err := exchange(t, Upstream{
+			Address:  srv.URL + "/dns-query",
+			Protocol: ProtocolHTTPS,
+			CAFile:   ca,
+			Pins:     []string{base64.StdEncoding.EncodeToString(make([]byte, 32))},
+		})
+
+		require.Error(t, err)
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		_, err := exchange(t, Upstream{
+			Address:  srv.URL + "/dns-query",
+			Protocol: ProtocolHTTPS,
+		})
+
+		require.Error(t, err)
+	})
+}
+
+func TestDNSOverTLS(t *testing.T) {
+	srv, _ := newDoHServer(t) // используется только ради сертификата
+	cert := srv.TLS.Certificates[0]
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+
+	server := &dns.Server{Listener: listener, Net: "tcp-tls", Handler: dns.HandlerFunc(answer)}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	u, err := Upstream{
+		Address:    listener.Addr().String(),
+		Protocol:   ProtocolTLS,
+		ServerName: "example.com",
+		CAFile:     writeCA(t, srv.Certificate().Raw),
+	}.normalize()
+	require.NoError(t, err)
+
+	client, err := u.newClient(newBootstrap(nil))
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		req := &dns.Msg{}
+		req.SetQuestion("example.com.", dns.TypeA)
+
+		resp, _, err := client.Exchange(req, u.Address)
+		require.NoError(t, err)
+		require.Equal(t, req.Id, resp.Id)
+	}
+
+	// соединение переиспользуется между запросами
+	require.Len(t, client.(*tlsClient).idle, 1)
+}
+
+func TestBootstrap(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &dns.Server{PacketConn: pc, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := &dns.Msg{}
+		resp.SetReply(req)
+		if req.Question[0].Qtype == dns.TypeA {
+			resp.Answer = append(resp.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+				A:   net.ParseIP("127.0.0.1"),
+			})
+		}
+		_ = w.WriteMsg(resp)
+	})}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	boot := newBootstrap([]string{pc.LocalAddr().String()})
+
+	addrs, err := boot.resolve("dns.example.test")
+	require.NoError(t, err)
+	require.Equal(t, []string{"127.0.0.1"}, addrs)
+
+	addrs, err = boot.resolve("10.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.1"}, addrs)
+}
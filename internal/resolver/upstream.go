@@ -0,0 +1,216 @@
+package resolver
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	ProtocolUDP   = "udp"
+	ProtocolTCP   = "tcp"
+	ProtocolTLS   = "tls"
+	ProtocolHTTPS = "https"
+
+	DefaultProtocol = ProtocolUDP
+	DefaultTimeout  = 5 * time.Second
+	DefaultWeight   = 1
+	// DefaultUDPSize - размер буфера EDNS0, при котором ответы
+	// не фрагментируются (DNS Flag Day 2020).
+	DefaultUDPSize = 1232
+)
+
+// Upstream описывает вышестоящий DNS-сервер и параметры обращения к нему.
+type Upstream struct {
+	// Address - host:port для udp, tcp и tls, полный URL для https.
+	Address  string
+	Protocol string
+	Timeout  time.Duration
+	Weight   int
+
+	// ServerName переопределяет SNI и имя для проверки сертификата (tls, https).
+	ServerName string
+	// Pins - base64 SHA-256 от SubjectPublicKeyInfo допустимых сертификатов.
+	// Если задан хотя бы один, сертификат сервера обязан ему соответствовать.
+	Pins []string
+	// CAFile - PEM-файл с корневыми сертификатами вместо системных.
+	CAFile string
+	// Method - GET или POST для DNS-over-HTTPS (RFC 8484).
+	Method string
+	// UDPSize - размер буфера EDNS0, объявляемый серверу (udp).
+	UDPSize uint16
+}
+
+// ParseUpstream разбирает адрес вида
+// [protocol://]host[:port][/path][?timeout=2s&weight=10&sni=name&pin=base64&method=get&udp-size=1232].
+func ParseUpstream(spec string) (Upstream, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return Upstream{}, fmt.Errorf("resolver: empty upstream address")
+	}
+
+	if !strings.Contains(spec, "://") {
+		spec = DefaultProtocol + "://" + spec
+	}
+
+	u, err := url.Parse(spec)
+	if err != nil {
+		return Upstream{}, fmt.Errorf("resolver: invalid upstream %s: %v", spec, err)
+	}
+
+	upstream := Upstream{
+		Address:  u.Host,
+		Protocol: u.Scheme,
+	}
+
+	query := u.Query()
+	if timeout := query.Get("timeout"); timeout != "" {
+		if upstream.Timeout, err = time.ParseDuration(timeout); err != nil {
+			return Upstream{}, fmt.Errorf("resolver: invalid timeout of upstream %s: %v", spec, err)
+		}
+	}
+	if weight := query.Get("weight"); weight != "" {
+		if upstream.Weight, err = strconv.Atoi(weight); err != nil {
+			return Upstream{}, fmt.Errorf("resolver: invalid weight of upstream %s: %v", spec, err)
+		}
+	}
+	upstream.ServerName = query.Get("sni")
+	for _, pin := range query["pin"] {
+		// "+" из base64 в query превращается в пробел, если его не экранировали
+		upstream.Pins = append(upstream.Pins, strings.ReplaceAll(pin, " ", "+"))
+	}
+	upstream.Method = strings.ToUpper(query.Get("method"))
+	if size := query.Get("udp-size"); size != "" {
+		parsed, err := strconv.ParseUint(size, 10, 16)
+		if err != nil {
+			return Upstream{}, fmt.Errorf("resolver: invalid udp-size of upstream %s: %v", spec, err)
+		}
+		upstream.UDPSize = uint16(parsed)
+	}
+
+	if upstream.Protocol == ProtocolHTTPS {
+		// собственные параметры не должны уходить на сервер
+		for _, key := range []string{"timeout", "weight", "sni", "pin", "method", "udp-size"} {
+			query.Del(key)
+		}
+		u.RawQuery = query.Encode()
+		upstream.Address = u.String()
+	}
+
+	return upstream.normalize()
+}
+
+// NewUpstream разбирает адрес и перекрывает параметры из него явно
+// заданными ненулевыми значениями options.
+func NewUpstream(address string, options Upstream) (Upstream, error) {
+	u, err := ParseUpstream(address)
+	if err != nil {
+		return Upstream{}, err
+	}
+
+	if options.Protocol != "" && options.Protocol != u.Protocol {
+		if u, err = ParseUpstream(options.Protocol + "://" + trimScheme(address)); err != nil {
+			return Upstream{}, err
+		}
+	}
+	if options.Timeout > 0 {
+		u.Timeout = options.Timeout
+	}
+	if options.Weight > 0 {
+		u.Weight = options.Weight
+	}
+	if options.ServerName != "" {
+		u.ServerName = options.ServerName
+	}
+	if len(options.Pins) > 0 {
+		u.Pins = options.Pins
+	}
+	if options.CAFile != "" {
+		u.CAFile = options.CAFile
+	}
+	if options.Method != "" {
+		u.Method = strings.ToUpper(options.Method)
+	}
+	if options.UDPSize > 0 {
+		u.UDPSize = options.UDPSize
+	}
+
+	return u.normalize()
+}
+
+func trimScheme(address string) string {
+	if i := strings.Index(address, "://"); i >= 0 {
+		return address[i+3:]
+	}
+	return address
+}
+
+// normalize проверяет параметры и подставляет значения по умолчанию.
+func (u Upstream) normalize() (Upstream, error) {
+	if u.Protocol == "" {
+		u.Protocol = DefaultProtocol
+	}
+	if u.Timeout <= 0 {
+		u.Timeout = DefaultTimeout
+	}
+	if u.Weight <= 0 {
+		u.Weight = DefaultWeight
+	}
+	if u.Address == "" {
+		return Upstream{}, fmt.Errorf("resolver: upstream address is empty")
+	}
+
+	switch u.Protocol {
+	case ProtocolUDP:
+		u.Address = withDefaultPort(u.Address, "53")
+		if u.UDPSize == 0 {
+			u.UDPSize = DefaultUDPSize
+		}
+		if u.UDPSize < dns.MinMsgSize {
+			return Upstream{}, fmt.Errorf("resolver: udp-size of upstream %s must be at least %d", u.Address, dns.MinMsgSize)
+		}
+	case ProtocolTCP:
+		u.Address = withDefaultPort(u.Address, "53")
+	case ProtocolTLS:
+		u.Address = withDefaultPort(u.Address, "853")
+	case ProtocolHTTPS:
+		if u.Method == "" {
+			u.Method = "POST"
+		}
+		if u.Method != "GET" && u.Method != "POST" {
+			return Upstream{}, fmt.Errorf("resolver: unsupported method %q of upstream %s", u.Method, u.Address)
+		}
+		if !strings.HasPrefix(u.Address, ProtocolHTTPS+"://") {
+			u.Address = ProtocolHTTPS + "://" + u.Address
+		}
+	default:
+		return Upstream{}, fmt.Errorf("resolver: unsupported protocol %q of upstream %s", u.Protocol, u.Address)
+	}
+
+	for _, pin := range u.Pins {
+		if _, err := decodePin(pin); err != nil {
+			return Upstream{}, fmt.Errorf("resolver: invalid pin %q of upstream %s: %v", pin, u.Address, err)
+		}
+	}
+
+	return u, nil
+}
+
+func withDefaultPort(address, port string) string {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return net.JoinHostPort(strings.Trim(address, "[]"), port)
+	}
+	return address
+}
+
+func (u Upstream) String() string {
+	if u.Protocol == ProtocolHTTPS {
+		return u.Address
+	}
+	return u.Protocol + "://" + u.Address
+}
@@ -0,0 +1,68 @@
+package resolver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUpstream(t *testing.T) {
+	t.Run("plain address", func(t *testing.T) {
+		u, err := ParseUpstream("8.8.8.8")
+
+		require.NoError(t, err)
+		require.Equal(t, Upstream{
+			Address:  "8.8.8.8:53",
+			Protocol: DefaultProtocol,
+			Timeout:  DefaultTimeout,
+			Weight:   DefaultWeight,
+			UDPSize:  DefaultUDPSize,
+		}, u)
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		u, err := ParseUpstream("udp://10.0.0.1:5353?timeout=2s&weight=10&udp-size=4096")
+
+		require.NoError(t, err)
+		require.Equal(t, Upstream{
+			Address:  "10.0.0.1:5353",
+			Protocol: ProtocolUDP,
+			Timeout:  2 * time.Second,
+			Weight:   10,
+			UDPSize:  4096,
+		}, u)
+	})
+
+	t.Run("ipv6", func(t *testing.T) {
+		u, err := ParseUpstream("udp://[2001:4860:4860::8888]")
+
+		require.NoError(t, err)
+		require.Equal(t, "[2001:4860:4860::8888]:53", u.Address)
+	})
+
+	t.Run("dns over tls", func(t *testing.T) {
+		u, err := ParseUpstream("tls://dns.google?sni=dns.google&pin=sha256/47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
+
+		require.NoError(t, err)
+		require.Equal(t, "dns.google:853", u.Address)
+		require.Equal(t, "dns.google", u.ServerName)
+		require.Len(t, u.Pins, 1)
+	})
+
+	t.Run("dns over https", func(t *testing.T) {
+		u, err := ParseUpstream("https://dns.google/dns-query?method=get&timeout=1s")
+
+		require.NoError(t, err)
+		require.Equal(t, "https://dns.google/dns-query", u.Address)
+		require.Equal(t, "GET", u.Method)
+		require.Equal(t, time.Second, u.Timeout)
+		require.Equal(t, "https://dns.google/dns-query", u.String())
+	})
+
+	t.Run("unsupported protocol", func(t *testing.T) {
+		_, err := ParseUpstream("quic://10.0.0.1")
+
+		require.Error(t, err)
+	})
+}
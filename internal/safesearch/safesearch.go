@@ -0,0 +1,207 @@
+package safesearch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/miekg/dns"
+)
+
+// Engine - поисковик или видеосервис с безопасным режимом.
+type Engine string
+
+const (
+	EngineGoogle     Engine = "google"
+	EngineBing       Engine = "bing"
+	EngineDuckDuckGo Engine = "duckduckgo"
+	EngineYouTube    Engine = "youtube"
+)
+
+// Global - группа настроек, действующих для групп без своих настроек.
+const Global = ""
+
+// targets - имена, включающие безопасный режим на стороне сервиса.
+var targets = map[Engine]string{
+	EngineGoogle:     "forcesafesearch.google.com.",
+	EngineBing:       "strict.bing.com.",
+	EngineDuckDuckGo: "safe.duckduckgo.com.",
+	EngineYouTube:    "restrict.youtube.com.",
+}
+
+var hosts = map[string]Engine{
+	"bing.com.":                 EngineBing,
+	"www.bing.com.":             EngineBing,
+	"duckduckgo.com.":           EngineDuckDuckGo,
+	"www.duckduckgo.com.":       EngineDuckDuckGo,
+	"start.duckduckgo.com.":     EngineDuckDuckGo,
+	"youtube.com.":              EngineYouTube,
+	"www.youtube.com.":          EngineYouTube,
+	"m.youtube.com.":            EngineYouTube,
+	"youtubei.googleapis.com.":  EngineYouTube,
+	"youtube.googleapis.com.":   EngineYouTube,
+	"www.youtube-nocookie.com.": EngineYouTube,
+}
+
+// Engines возвращает поддерживаемые сервисы.
+func Engines() []Engine {
+	return []Engine{EngineGoogle, EngineBing, EngineDuckDuckGo, EngineYouTube}
+}
+
+// engine определяет сервис по имени. Google работает на национальных
+// доменах: google.de, www.google.co.uk.
+func engine(name string) (Engine, bool) {
+	if e, ok := hosts[name]; ok {
+		return e, true
+	}
+
+	labels := dns.SplitDomainName(strings.TrimPrefix(name, "www."))
+	if len(labels) >= 2 && len(labels) <= 3 && labels[0] == "google" {
+		return EngineGoogle, true
+	}
+	return "", false
+}
+
+type Storage interface {
+	SafeSearch(ctx context.Context) ([]datastore.SafeSearch, error)
+	SetSafeSearch(ctx context.Context, setting datastore.SafeSearch) error
+	DeleteSafeSearch(ctx context.Context, group string) error
+}
+
+// Setting - сервисы, для которых в группе включен безопасный режим.
+type Setting struct {
+	Group   string
+	Engines []Engine
+}
+
+// SafeSearch переписывает запросы к поисковикам в CNAME на их безопасные
+// адреса. Настройки группы клиентов заменяют глобальные.
+type SafeSearch struct {
+	storage Storage
+
+	mu       sync.RWMutex
+	settings map[string]map[Engine]bool
+}
+
+func New(storage Storage) *SafeSearch {
+	return &SafeSearch{
+		storage:  storage,
+		settings: make(map[string]map[Engine]bool),
+	}
+}
+
+func (s *SafeSearch) Load(ctx context.Context) error {
+	stored, err := s.storage.SafeSearch(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, st := range stored {
+		engines := make([]Engine, len(st.Engines))
+		for i, e := range st.Engines {
+			engines[i] = Engine(e)
+		}
+		set, err := engineSet(engines)
+		if err != nil {
+			return err
+		}
+		s.settings[st.Group] = set
+	}
+	return nil
+}
+
+func engineSet(engines []Engine) (map[Engine]bool, error) {
+	set := make(map[Engine]bool, len(engines))
+	for _, e := range engines {
+		if _, ok := targets[e]; !ok {
+			return nil, fmt.Errorf("safesearch: unknown engine %q", e)
+		}
+		set[e] = true
+	}
+	return set, nil
+}
+
+// Settings возвращает настройки, глобальные - первыми.
+func (s *SafeSearch) Settings() []Setting {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]Setting, 0, len(s.settings))
+	for group, set := range s.settings {
+		setting := Setting{Group: group}
+		for _, e := range Engines() {
+			if set[e] {
+				setting.Engines = append(setting.Engines, e)
+			}
+		}
+		list = append(list, setting)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Group < list[j].Group })
+	return list
+}
+
+// Set включает безопасный режим для сервисов engines глобально
+// (Global) или для группы клиентов. Пустой список отключает его.
+func (s *SafeSearch) Set(ctx context.Context, group string, engines []Engine) error {
+	set, err := engineSet(engines)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := datastore.SafeSearch{Group: group}
+	for _, e := range Engines() {
+		if set[e] {
+			stored.Engines = append(stored.Engines, string(e))
+		}
+	}
+	if err := s.storage.SetSafeSearch(ctx, stored); err != nil {
+		return err
+	}
+	s.settings[group] = set
+	return nil
+}
+
+// Reset удаляет настройки группы, и для нее снова действуют глобальные.
+func (s *SafeSearch) Reset(ctx context.Context, group string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.settings[group]; !ok {
+		return fmt.Errorf("safesearch: group %q has no settings", group)
+	}
+	if err := s.storage.DeleteSafeSearch(ctx, group); err != nil {
+		return err
+	}
+	delete(s.settings, group)
+	return nil
+}
+
+// Rewrite возвращает безопасное имя, на которое указывает CNAME для
+// клиентов группы, если для сервиса включен безопасный режим.
+func (s *SafeSearch) Rewrite(group, name string) (string, bool) {
+	e, ok := engine(dns.CanonicalName(name))
+	if !ok {
+		return "", false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	set, ok := s.settings[group]
+	if !ok {
+		set = s.settings[Global]
+	}
+	if !set[e] {
+		return "", false
+	}
+	return targets[e], true
+}
@@ -0,0 +1,88 @@
+package safesearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStorage map[string]datastore.SafeSearch
+
+func (s fakeStorage) SafeSearch(context.Context) ([]datastore.SafeSearch, error) {
+	var list []datastore.SafeSearch
+	for _, setting := range s {
+		list = append(list, setting)
+	}
+	return list, nil
+}
+
+func (s fakeStorage) SetSafeSearch(_ context.Context, setting datastore.SafeSearch) error {
+	s[setting.Group] = setting
+	return nil
+}
+
+func (s fakeStorage) DeleteSafeSearch(_ context.Context, group string) error {
+	delete(s, group)
+	return nil
+}
+
+func TestEngine(t *testing.T) {
+	for name, want := range map[string]Engine{
+		"google.com.":                 EngineGoogle,
+		"www.google.com.":             EngineGoogle,
+		"www.google.co.uk.":           EngineGoogle,
+		"google.de.":                  EngineGoogle,
+		"www.bing.com.":               EngineBing,
+		"duckduckgo.com.":             EngineDuckDuckGo,
+		"m.youtube.com.":              EngineYouTube,
+		"mail.google.com.":            "",
+		"forcesafesearch.google.com.": "",
+		"google.":                     "",
+	} {
+		e, ok := engine(name)
+		require.Equal(t, want != "", ok, name)
+		require.Equal(t, want, e, name)
+	}
+}
+
+func TestSafeSearch(t *testing.T) {
+	ctx := context.Background()
+	storage := fakeStorage{}
+	s := New(storage)
+
+	_, ok := s.Rewrite("", "www.google.com.")
+	require.False(t, ok)
+
+	require.Error(t, s.Set(ctx, Global, []Engine{"yahoo"}))
+	require.NoError(t, s.Set(ctx, Global, []Engine{EngineYouTube, EngineGoogle}))
+
+	target, ok := s.Rewrite("", "WWW.Google.com.")
+	require.True(t, ok)
+	require.Equal(t, "forcesafesearch.google.com.", target)
+	target, _ = s.Rewrite("kids", "www.youtube.com.")
+	require.Equal(t, "restrict.youtube.com.", target)
+	_, ok = s.Rewrite("kids", "www.bing.com.")
+	require.False(t, ok)
+
+	// настройки группы заменяют глобальные
+	require.NoError(t, s.Set(ctx, "adults", nil))
+	_, ok = s.Rewrite("adults", "www.google.com.")
+	require.False(t, ok)
+
+	require.Equal(t, []Setting{
+		{Group: Global, Engines: []Engine{EngineGoogle, EngineYouTube}},
+		{Group: "adults"},
+	}, s.Settings())
+
+	// настройки восстанавливаются из хранилища
+	restored := New(storage)
+	require.NoError(t, restored.Load(ctx))
+	require.Equal(t, s.Settings(), restored.Settings())
+
+	require.NoError(t, s.Reset(ctx, "adults"))
+	require.Error(t, s.Reset(ctx, "adults"))
+	_, ok = s.Rewrite("adults", "www.google.com.")
+	require.True(t, ok)
+}
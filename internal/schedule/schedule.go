@@ -0,0 +1,207 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+var weekdayNames = [7]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+const minutesPerDay = 24 * 60
+
+// Range - интервал времени в выбранные дни недели, например,
+// "mon-fri 09:00-18:00" или "sat,sun 22:00-02:00". Интервал, конец
+// которого раньше начала, переходит через полночь и относится ко дню
+// своего начала.
+type Range struct {
+	Days [7]bool
+	// Start и End - минуты от начала суток.
+	Start int
+	End   int
+}
+
+// ParseRange разбирает интервал вида "<дни> <ЧЧ:ММ>-<ЧЧ:ММ>". Дни
+// перечисляются через запятую, диапазоны дней задаются через дефис,
+// * означает все дни.
+func ParseRange(s string) (Range, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return Range{}, fmt.Errorf("schedule: range %q must look like \"mon-fri 09:00-18:00\"", s)
+	}
+
+	var r Range
+	if err := r.parseDays(fields[0]); err != nil {
+		return Range{}, fmt.Errorf("schedule: range %q: %v", s, err)
+	}
+
+	start, end, ok := strings.Cut(fields[1], "-")
+	if !ok {
+		return Range{}, fmt.Errorf("schedule: range %q has no end time", s)
+	}
+	var err error
+	if r.Start, err = parseClock(start); err != nil {
+		return Range{}, fmt.Errorf("schedule: range %q: %v", s, err)
+	}
+	if r.End, err = parseClock(end); err != nil {
+		return Range{}, fmt.Errorf("schedule: range %q: %v", s, err)
+	}
+	if r.Start == r.End {
+		return Range{}, fmt.Errorf("schedule: range %q is empty", s)
+	}
+
+	return r, nil
+}
+
+func (r *Range) parseDays(s string) error {
+	if s == "*" {
+		for d := range r.Days {
+			r.Days[d] = true
+		}
+		return nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(strings.ToLower(part), "-")
+		first, ok := weekdays[from]
+		if !ok {
+			return fmt.Errorf("unknown weekday %q", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdays[to]; !ok {
+				return fmt.Errorf("unknown weekday %q", to)
+			}
+		}
+		// диапазон может переходить через воскресенье: fri-mon
+		for d := first; ; d = (d + 1) % 7 {
+			r.Days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func parseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	h, err := strconv.Atoi(hh)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	m, err := strconv.Atoi(mm)
+	if err != nil || len(mm) != 2 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	minutes := h*60 + m
+	if h < 0 || m < 0 || m > 59 || minutes > minutesPerDay {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return minutes, nil
+}
+
+// contains проверяет день недели и минуту от начала суток.
+func (r Range) contains(day time.Weekday, minute int) bool {
+	if r.Start < r.End {
+		return r.Days[day] && minute >= r.Start && minute < r.End
+	}
+	// часть после полуночи относится к предыдущему дню
+	previous := (day + 6) % 7
+	return (r.Days[day] && minute >= r.Start) || (r.Days[previous] && minute < r.End)
+}
+
+func (r Range) String() string {
+	var days []string
+	for d, on := range r.Days {
+		if on {
+			days = append(days, weekdayNames[d])
+		}
+	}
+	day := strings.Join(days, ",")
+	if len(days) == 7 {
+		day = "*"
+	}
+	return fmt.Sprintf("%s %02d:%02d-%02d:%02d", day, r.Start/60, r.Start%60, r.End/60, r.End%60)
+}
+
+// Schedule - именованное расписание. Правила, к которым оно привязано,
+// действуют только в его интервалы.
+type Schedule struct {
+	Name string
+	// Timezone - имя зоны IANA, пустое - местное время сервера.
+	Timezone string
+	Ranges   []Range
+
+	location *time.Location
+}
+
+// Parse создает расписание из описаний интервалов.
+func Parse(name, timezone string, ranges []string) (Schedule, error) {
+	if name == "" {
+		return Schedule{}, fmt.Errorf("schedule: name is empty")
+	}
+
+	s := Schedule{Name: name, Timezone: timezone, location: time.Local}
+	if timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("schedule: unknown timezone %q", timezone)
+		}
+		s.location = location
+	}
+
+	if len(ranges) == 0 {
+		return Schedule{}, fmt.Errorf("schedule: %s has no ranges", name)
+	}
+	for _, text := range ranges {
+		r, err := ParseRange(text)
+		if err != nil {
+			return Schedule{}, err
+		}
+		s.Ranges = append(s.Ranges, r)
+	}
+
+	return s, nil
+}
+
+// Active сообщает, попадает ли момент t в один из интервалов.
+func (s Schedule) Active(t time.Time) bool {
+	location := s.location
+	if location == nil {
+		location = time.Local
+	}
+	t = t.In(location)
+	minute := t.Hour()*60 + t.Minute()
+
+	for _, r := range s.Ranges {
+		if r.contains(t.Weekday(), minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// RangeStrings возвращает интервалы в том же виде, в каком их принимает
+// Parse.
+func (s Schedule) RangeStrings() []string {
+	ranges := make([]string, len(s.Ranges))
+	for i, r := range s.Ranges {
+		ranges[i] = r.String()
+	}
+	return ranges
+}
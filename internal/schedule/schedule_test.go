@@ -0,0 +1,178 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+type fakeStorage struct {
+	schedules   map[string]datastore.Schedule
+	attachments map[[2]string]string
+}
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{
+		schedules:   make(map[string]datastore.Schedule),
+		attachments: make(map[[2]string]string),
+	}
+}
+
+func (s *fakeStorage) Schedules(context.Context) ([]datastore.Schedule, error) {
+	var list []datastore.Schedule
+	for _, schedule := range s.schedules {
+		list = append(list, schedule)
+	}
+	return list, nil
+}
+
+func (s *fakeStorage) SetSchedule(_ context.Context, schedule datastore.Schedule) error {
+	s.schedules[schedule.Name] = schedule
+	return nil
+}
+
+func (s *fakeStorage) DeleteSchedule(_ context.Context, name string) error {
+	delete(s.schedules, name)
+	for key, schedule := range s.attachments {
+		if schedule == name {
+			delete(s.attachments, key)
+		}
+	}
+	return nil
+}
+
+func (s *fakeStorage) ScheduleAttachments(context.Context) ([]datastore.ScheduleAttachment, error) {
+	var list []datastore.ScheduleAttachment
+	for key, schedule := range s.attachments {
+		list = append(list, datastore.ScheduleAttachment{Kind: key[0], Target: key[1], Schedule: schedule})
+	}
+	return list, nil
+}
+
+func (s *fakeStorage) AttachSchedule(_ context.Context, a datastore.ScheduleAttachment) error {
+	s.attachments[[2]string{a.Kind, a.Target}] = a.Schedule
+	return nil
+}
+
+func (s *fakeStorage) DetachSchedule(_ context.Context, kind, target string) error {
+	delete(s.attachments, [2]string{kind, target})
+	return nil
+}
+
+func TestParseRange(t *testing.T) {
+	r, err := ParseRange("mon-fri 09:00-18:00")
+	require.NoError(t, err)
+	require.Equal(t, "mon,tue,wed,thu,fri 09:00-18:00", r.String())
+
+	r, err = ParseRange("fri-mon 22:30-24:00")
+	require.NoError(t, err)
+	require.Equal(t, "sun,mon,fri,sat 22:30-24:00", r.String())
+
+	r, err = ParseRange("* 00:00-06:00")
+	require.NoError(t, err)
+	require.Equal(t, "* 00:00-06:00", r.String())
+
+	for _, bad := range []string{
+		"09:00-18:00",
+		"mon-fry 09:00-18:00",
+		"mon 09:00",
+		"mon 9:0-18:00",
+		"mon 09:00-25:00",
+		"mon 09:00-09:00",
+	} {
+		_, err := ParseRange(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestScheduleActive(t *testing.T) {
+	s, err := Parse("school", "Europe/Moscow", []string{"mon-fri 08:00-15:00", "sun 22:00-02:00"})
+	require.NoError(t, err)
+
+	at := func(value string) time.Time {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	// понедельник, 08:30 по Москве
+	require.True(t, s.Active(at("2026-10-19T05:30:00Z")))
+	// понедельник, 15:00 по Москве - конец интервала не входит
+	require.False(t, s.Active(at("2026-10-19T12:00:00Z")))
+	// суббота
+	require.False(t, s.Active(at("2026-10-17T07:00:00Z")))
+	// воскресенье, 23:00 и понедельник, 01:00 - интервал через полночь
+	require.True(t, s.Active(at("2026-10-18T20:00:00Z")))
+	require.True(t, s.Active(at("2026-10-18T22:00:00Z")))
+	// суббота, 01:00 - после полуночи пятницы интервала нет
+	require.False(t, s.Active(at("2026-10-16T22:00:00Z")))
+
+	_, err = Parse("broken", "Mars/Olympus", []string{"* 00:00-01:00"})
+	require.Error(t, err)
+	_, err = Parse("empty", "", nil)
+	require.Error(t, err)
+}
+
+func TestSchedules(t *testing.T) {
+	ctx := context.Background()
+	// среда, 10:00 UTC
+	clock := &fakeClock{now: time.Date(2026, 10, 21, 10, 0, 0, 0, time.UTC)}
+	storage := newFakeStorage()
+	schedules := New(storage, clock)
+
+	work, err := Parse("work", "UTC", []string{"mon-fri 09:00-18:00"})
+	require.NoError(t, err)
+	require.NoError(t, schedules.Set(ctx, work))
+
+	require.Error(t, schedules.Attach(ctx, Attachment{Kind: KindSource, Target: "https://lists.test/games", Schedule: "night"}))
+	require.Error(t, schedules.Attach(ctx, Attachment{Kind: "client", Target: "tablet", Schedule: "work"}))
+	require.NoError(t, schedules.Attach(ctx, Attachment{Kind: KindSource, Target: "https://lists.test/games", Schedule: "work"}))
+	require.NoError(t, schedules.Attach(ctx, Attachment{Kind: KindDomain, Target: "Social.Test", Schedule: "work"}))
+	require.NoError(t, schedules.Attach(ctx, Attachment{Kind: KindDomain, Target: "*.video.test", Schedule: "work"}))
+	require.NoError(t, schedules.Attach(ctx, Attachment{Kind: KindGroup, Target: "kids", Schedule: "work"}))
+
+	origins := []string{"https://lists.test/games", "https://lists.test/ads", blacklist.OriginManual}
+	require.Equal(t, origins, schedules.Origins("social.test.", origins))
+	require.True(t, schedules.GroupActive("kids"))
+
+	// вечером правила с расписанием не действуют
+	clock.now = clock.now.Add(9 * time.Hour)
+	require.Equal(t, []string{"https://lists.test/ads"}, schedules.Origins("social.test.", origins))
+	require.Equal(t, []string{"https://lists.test/ads", blacklist.OriginManual}, schedules.Origins("other.test.", origins))
+	// расписание шаблона действует на правило шаблона, а не на имя запроса
+	require.Equal(t, []string{"https://lists.test/ads"}, schedules.Origins("*.video.test.", origins))
+	require.False(t, schedules.GroupActive("kids"))
+	require.True(t, schedules.GroupActive("default"))
+
+	// состояние восстанавливается из хранилища
+	restored := New(storage, clock)
+	require.NoError(t, restored.Load(ctx))
+	require.Equal(t, schedules.Attachments(), restored.Attachments())
+	require.Equal(t, []string{"mon,tue,wed,thu,fri 09:00-18:00"}, restored.List()[0].RangeStrings())
+	require.False(t, restored.GroupActive("kids"))
+
+	require.NoError(t, schedules.Detach(ctx, KindDomain, "social.test"))
+	require.Error(t, schedules.Detach(ctx, KindDomain, "social.test"))
+	require.Equal(t, []string{"https://lists.test/ads", blacklist.OriginManual}, schedules.Origins("social.test.", origins))
+
+	// удаление расписания снимает его привязки
+	require.NoError(t, schedules.Delete(ctx, "work"))
+	require.Empty(t, schedules.Attachments())
+	require.Empty(t, storage.attachments)
+	require.True(t, schedules.GroupActive("kids"))
+	require.Error(t, schedules.Delete(ctx, "work"))
+}
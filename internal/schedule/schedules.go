@@ -0,0 +1,294 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/miekg/dns"
+)
+
+// Clock возвращает текущее время. В тестах подменяется.
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Kind - вид правил, к которым привязывается расписание.
+type Kind string
+
+const (
+	// KindDomain - правило, заблокированное через API
+	// (blacklist.OriginManual): домен или шаблон *.example.com.
+	KindDomain Kind = "domain"
+	// KindSource - все домены источника, целью служит URL списка.
+	KindSource Kind = "source"
+	// KindGroup - фильтрация группы клиентов целиком.
+	KindGroup Kind = "group"
+)
+
+// Attachment привязывает расписание к правилу.
+type Attachment struct {
+	Kind     Kind
+	Target   string
+	Schedule string
+}
+
+func (a *Attachment) normalize() error {
+	switch a.Kind {
+	case KindDomain:
+		a.Target = dns.CanonicalName(strings.TrimSpace(a.Target))
+	case KindSource, KindGroup:
+		a.Target = strings.TrimSpace(a.Target)
+	default:
+		return fmt.Errorf("schedule: unknown kind %q", a.Kind)
+	}
+	if a.Target == "" || a.Target == "." {
+		return fmt.Errorf("schedule: target is empty")
+	}
+	return nil
+}
+
+type Storage interface {
+	Schedules(ctx context.Context) ([]datastore.Schedule, error)
+	SetSchedule(ctx context.Context, schedule datastore.Schedule) error
+	DeleteSchedule(ctx context.Context, name string) error
+	ScheduleAttachments(ctx context.Context) ([]datastore.ScheduleAttachment, error)
+	AttachSchedule(ctx context.Context, attachment datastore.ScheduleAttachment) error
+	DetachSchedule(ctx context.Context, kind, target string) error
+}
+
+// Schedules хранит расписания и их привязки. Правило без расписания
+// действует всегда.
+type Schedules struct {
+	storage Storage
+	clock   Clock
+
+	mu          sync.RWMutex
+	schedules   map[string]Schedule
+	attachments map[Kind]map[string]string
+}
+
+func New(storage Storage, clock Clock) *Schedules {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	return &Schedules{
+		storage:   storage,
+		clock:     clock,
+		schedules: make(map[string]Schedule),
+		attachments: map[Kind]map[string]string{
+			KindDomain: {},
+			KindSource: {},
+			KindGroup:  {},
+		},
+	}
+}
+
+func (s *Schedules) Load(ctx context.Context) error {
+	stored, err := s.storage.Schedules(ctx)
+	if err != nil {
+		return err
+	}
+	attachments, err := s.storage.ScheduleAttachments(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, st := range stored {
+		schedule, err := Parse(st.Name, st.Timezone, st.Ranges)
+		if err != nil {
+			return err
+		}
+		s.schedules[schedule.Name] = schedule
+	}
+	for _, a := range attachments {
+		targets, ok := s.attachments[Kind(a.Kind)]
+		if !ok {
+			return fmt.Errorf("schedule: unknown kind %q", a.Kind)
+		}
+		targets[a.Target] = a.Schedule
+	}
+	return nil
+}
+
+// List возвращает расписания, упорядоченные по имени.
+func (s *Schedules) List() []Schedule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		list = append(list, schedule)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Attachments возвращает привязки, упорядоченные по виду и цели.
+func (s *Schedules) Attachments() []Attachment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var list []Attachment
+	for kind, targets := range s.attachments {
+		for target, schedule := range targets {
+			list = append(list, Attachment{Kind: kind, Target: target, Schedule: schedule})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind < list[j].Kind
+		}
+		return list[i].Target < list[j].Target
+	})
+	return list
+}
+
+// Set создает или заменяет расписание.
+func (s *Schedules) Set(ctx context.Context, schedule Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.storage.SetSchedule(ctx, datastore.Schedule{
+		Name:     schedule.Name,
+		Timezone: schedule.Timezone,
+		Ranges:   schedule.RangeStrings(),
+	})
+	if err != nil {
+		return err
+	}
+	s.schedules[schedule.Name] = schedule
+	return nil
+}
+
+// Delete удаляет расписание вместе с его привязками.
+func (s *Schedules) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.schedules[name]; !ok {
+		return fmt.Errorf("schedule: %s does not exist", name)
+	}
+	if err := s.storage.DeleteSchedule(ctx, name); err != nil {
+		return err
+	}
+	delete(s.schedules, name)
+	for _, targets := range s.attachments {
+		for target, schedule := range targets {
+			if schedule == name {
+				delete(targets, target)
+			}
+		}
+	}
+	return nil
+}
+
+// Attach привязывает расписание к правилу, заменяя прежнюю привязку.
+func (s *Schedules) Attach(ctx context.Context, attachment Attachment) error {
+	if err := attachment.normalize(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.schedules[attachment.Schedule]; !ok {
+		return fmt.Errorf("schedule: %s does not exist", attachment.Schedule)
+	}
+	err := s.storage.AttachSchedule(ctx, datastore.ScheduleAttachment{
+		Kind:     string(attachment.Kind),
+		Target:   attachment.Target,
+		Schedule: attachment.Schedule,
+	})
+	if err != nil {
+		return err
+	}
+	s.attachments[attachment.Kind][attachment.Target] = attachment.Schedule
+	return nil
+}
+
+// Detach отвязывает расписание, правило снова действует всегда.
+func (s *Schedules) Detach(ctx context.Context, kind Kind, target string) error {
+	attachment := Attachment{Kind: kind, Target: target}
+	if err := attachment.normalize(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.attachments[kind][attachment.Target]; !ok {
+		return fmt.Errorf("schedule: %s %s has no schedule", kind, attachment.Target)
+	}
+	if err := s.storage.DetachSchedule(ctx, string(kind), attachment.Target); err != nil {
+		return err
+	}
+	delete(s.attachments[kind], attachment.Target)
+	return nil
+}
+
+// Active сообщает, действует ли расписание сейчас.
+func (s *Schedules) Active(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.active(name, s.clock.Now())
+}
+
+func (s *Schedules) active(name string, now time.Time) bool {
+	schedule, ok := s.schedules[name]
+	return !ok || schedule.Active(now)
+}
+
+// GroupActive сообщает, действует ли сейчас фильтрация группы клиентов.
+func (s *Schedules) GroupActive(group string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	name, ok := s.attachments[KindGroup][group]
+	return !ok || s.active(name, s.clock.Now())
+}
+
+// Origins оставляет из источников правила черного списка те, что
+// действуют сейчас: источники и ручные блокировки вне своего расписания
+// отбрасываются. rule - правило, под которое попал запрос: домен или
+// шаблон родительского домена.
+func (s *Schedules) Origins(rule string, origins []string) []string {
+	if len(origins) == 0 {
+		return origins
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.attachments[KindDomain]) == 0 && len(s.attachments[KindSource]) == 0 {
+		return origins
+	}
+
+	now := s.clock.Now()
+	active := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		name, ok := s.attachments[KindSource][origin]
+		if origin == blacklist.OriginManual {
+			name, ok = s.attachments[KindDomain][dns.CanonicalName(rule)]
+		}
+		if !ok || s.active(name, now) {
+			active = append(active, origin)
+		}
+	}
+	return active
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/miekg/dns"
+)
+
+// Global - настройки, действующие для групп без своих настроек.
+const Global = ""
+
+//go:embed catalog.json
+var builtin []byte
+
+// Service - сервис и правила, блокирующие его домены. Правило вида
+// *.example.com блокирует поддомены example.com.
+type Service struct {
+	ID    string   `json:"id"`
+	Name  string   `json:"name"`
+	Rules []string `json:"rules"`
+}
+
+// Catalog - каталог сервисов. Version растет с каждым изменением
+// каталога, из встроенного и внешнего используется более новый.
+type Catalog struct {
+	Version  int       `json:"version"`
+	Services []Service `json:"services"`
+}
+
+// ParseCatalog разбирает и проверяет каталог в формате JSON.
+func ParseCatalog(data []byte) (Catalog, error) {
+	var catalog Catalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return Catalog{}, fmt.Errorf("services: invalid catalog: %v", err)
+	}
+	if catalog.Version <= 0 {
+		return Catalog{}, fmt.Errorf("services: catalog version is missing")
+	}
+
+	ids := make(map[string]bool, len(catalog.Services))
+	for i, s := range catalog.Services {
+		if s.ID == "" || strings.ContainsAny(s.ID, " :/") {
+			return Catalog{}, fmt.Errorf("services: invalid service id %q", s.ID)
+		}
+		if ids[s.ID] {
+			return Catalog{}, fmt.Errorf("services: service %s is defined twice", s.ID)
+		}
+		ids[s.ID] = true
+		if len(s.Rules) == 0 {
+			return Catalog{}, fmt.Errorf("services: service %s has no rules", s.ID)
+		}
+		for j, rule := range s.Rules {
+			rule = dns.CanonicalName(rule)
+			if _, ok := dns.IsDomainName(rule); !ok || rule == "." || strings.Contains(strings.TrimPrefix(rule, "*."), "*") {
+				return Catalog{}, fmt.Errorf("services: invalid rule %q of service %s", s.Rules[j], s.ID)
+			}
+			catalog.Services[i].Rules[j] = rule
+		}
+	}
+	sort.Slice(catalog.Services, func(i, j int) bool { return catalog.Services[i].ID < catalog.Services[j].ID })
+
+	return catalog, nil
+}
+
+// Builtin возвращает встроенный каталог.
+func Builtin() Catalog {
+	catalog, err := ParseCatalog(builtin)
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+// LoadCatalog читает каталог из файла и возвращает его, если он новее
+// встроенного, иначе - встроенный.
+func LoadCatalog(path string) (Catalog, error) {
+	catalog := Builtin()
+	if path == "" {
+		return catalog, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Catalog{}, fmt.Errorf("services: unable to read catalog: %v", err)
+	}
+	external, err := ParseCatalog(data)
+	if err != nil {
+		return Catalog{}, fmt.Errorf("%v (%s)", err, path)
+	}
+	if external.Version > catalog.Version {
+		return external, nil
+	}
+	return catalog, nil
+}
+
+type Blacklist interface {
+	AddFrom(ctx context.Context, origin string, domains ...string) int
+	RemoveFrom(ctx context.Context, origin string, domains ...string) int
+}
+
+type Storage interface {
+	BlockedServices(ctx context.Context) ([]datastore.BlockedService, error)
+	SetBlockedService(ctx context.Context, setting datastore.BlockedService) error
+	DeleteBlockedService(ctx context.Context, service, group string) error
+}
+
+// Status - сервис и где он заблокирован. Groups - настройки групп,
+// заменяющие глобальную.
+type Status struct {
+	Service
+	Blocked bool
+	Groups  map[string]bool
+}
+
+// Services блокирует сервисы каталога глобально или для групп клиентов.
+// Правила сервиса добавляются в черный список с происхождением
+// service:<id>, пока сервис заблокирован хоть где-то, а Origins
+// отбрасывает сервисы, не заблокированные для группы клиента.
+type Services struct {
+	bl      Blacklist
+	storage Storage
+
+	mu      sync.RWMutex
+	catalog Catalog
+	byID    map[string]Service
+	// settings - блокировка по сервисам и группам
+	settings map[string]map[string]bool
+}
+
+func New(bl Blacklist, storage Storage, catalog Catalog) *Services {
+	s := &Services{
+		bl:       bl,
+		storage:  storage,
+		catalog:  catalog,
+		byID:     make(map[string]Service, len(catalog.Services)),
+		settings: make(map[string]map[string]bool),
+	}
+	for _, service := range catalog.Services {
+		s.byID[service.ID] = service
+	}
+	return s
+}
+
+// Origin - происхождение правил сервиса.
+func Origin(id string) string {
+	return blacklist.OriginServicePrefix + id
+}
+
+// Load восстанавливает настройки и добавляет в черный список правила
+// заблокированных сервисов. Настройки сервисов, которых больше нет
+// в каталоге, сохраняются, но не действуют.
+func (s *Services) Load(ctx context.Context) error {
+	stored, err := s.storage.BlockedServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, st := range stored {
+		if s.settings[st.Service] == nil {
+			s.settings[st.Service] = make(map[string]bool)
+		}
+		s.settings[st.Service][st.Group] = st.Blocked
+	}
+	for id := range s.settings {
+		if service, ok := s.byID[id]; ok && s.used(id) {
+			s.bl.AddFrom(ctx, Origin(id), service.Rules...)
+		}
+	}
+	return nil
+}
+
+// Version возвращает версию каталога.
+func (s *Services) Version() int {
+	return s.catalog.Version
+}
+
+// List возвращает сервисы каталога с их настройками.
+func (s *Services) List() []Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]Status, len(s.catalog.Services))
+	for i, service := range s.catalog.Services {
+		status := Status{Service: service, Groups: make(map[string]bool)}
+		for group, blocked := range s.settings[service.ID] {
+			if group == Global {
+				status.Blocked = blocked
+				continue
+			}
+			status.Groups[group] = blocked
+		}
+		list[i] = status
+	}
+	return list
+}
+
+// Set блокирует или разблокирует сервис глобально (Global) или для
+// группы клиентов.
+func (s *Services) Set(ctx context.Context, id, group string, blocked bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	service, ok := s.byID[id]
+	if !ok {
+		return fmt.Errorf("services: unknown service %q", id)
+	}
+	err := s.storage.SetBlockedService(ctx, datastore.BlockedService{Service: id, Group: group, Blocked: blocked})
+	if err != nil {
+		return err
+	}
+
+	wasUsed := s.used(id)
+	if s.settings[id] == nil {
+		s.settings[id] = make(map[string]bool)
+	}
+	s.settings[id][group] = blocked
+	s.sync(ctx, service, wasUsed)
+	return nil
+}
+
+// Reset удаляет настройку группы, и для нее снова действует глобальная.
+func (s *Services) Reset(ctx context.Context, id, group string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.settings[id][group]; !ok {
+		return fmt.Errorf("services: service %q has no settings for group %q", id, group)
+	}
+	if err := s.storage.DeleteBlockedService(ctx, id, group); err != nil {
+		return err
+	}
+
+	wasUsed := s.used(id)
+	delete(s.settings[id], group)
+	if service, ok := s.byID[id]; ok {
+		s.sync(ctx, service, wasUsed)
+	}
+	return nil
+}
+
+// used сообщает, заблокирован ли сервис хоть где-то.
+func (s *Services) used(id string) bool {
+	for _, blocked := range s.settings[id] {
+		if blocked {
+			return true
+		}
+	}
+	return false
+}
+
+// sync добавляет или убирает правила сервиса из черного списка.
+func (s *Services) sync(ctx context.Context, service Service, wasUsed bool) {
+	switch used := s.used(service.ID); {
+	case used && !wasUsed:
+		s.bl.AddFrom(ctx, Origin(service.ID), service.Rules...)
+	case !used && wasUsed:
+		s.bl.RemoveFrom(ctx, Origin(service.ID), service.Rules...)
+	}
+}
+
+// Blocked сообщает, заблокирован ли сервис для группы.
+func (s *Services) Blocked(id, group string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.blocked(id, group)
+}
+
+func (s *Services) blocked(id, group string) bool {
+	if blocked, ok := s.settings[id][group]; ok {
+		return blocked
+	}
+	return s.settings[id][Global]
+}
+
+// Origins отбрасывает из источников домена сервисы, не заблокированные
+// для группы.
+func (s *Services) Origins(group string, origins []string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	active := origins[:0:0]
+	for _, origin := range origins {
+		if id, ok := strings.CutPrefix(origin, blacklist.OriginServicePrefix); ok && !s.blocked(id, group) {
+			continue
+		}
+		active = append(active, origin)
+	}
+	return active
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/denisdubovitskiy/blackhole/internal/blacklist"
+	"github.com/denisdubovitskiy/blackhole/internal/datastore"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStorage map[[2]string]bool
+
+func (s fakeStorage) BlockedServices(context.Context) ([]datastore.BlockedService, error) {
+	var list []datastore.BlockedService
+	for key, blocked := range s {
+		list = append(list, datastore.BlockedService{Service: key[0], Group: key[1], Blocked: blocked})
+	}
+	return list, nil
+}
+
+func (s fakeStorage) SetBlockedService(_ context.Context, setting datastore.BlockedService) error {
+	s[[2]string{setting.Service, setting.Group}] = setting.Blocked
+	return nil
+}
+
+func (s fakeStorage) DeleteBlockedService(_ context.Context, service, group string) error {
+	delete(s, [2]string{service, group})
+	return nil
+}
+
+const catalog = `{
+  "version": 3,
+  "services": [
+    {"id": "tiktok", "name": "TikTok", "rules": ["tiktok.com", "*.tiktok.com"]},
+    {"id": "steam", "name": "Steam", "rules": ["*.steampowered.com"]}
+  ]
+}`
+
+func TestCatalog(t *testing.T) {
+	builtin := Builtin()
+	require.NotEmpty(t, builtin.Services)
+
+	path := filepath.Join(t.TempDir(), "services.json")
+	require.NoError(t, os.WriteFile(path, []byte(catalog), 0o644))
+
+	// внешний каталог новее встроенного
+	c, err := LoadCatalog(path)
+	require.NoError(t, err)
+	require.Equal(t, 3, c.Version)
+	require.Equal(t, "steam", c.Services[0].ID)
+	require.Equal(t, []string{"tiktok.com.", "*.tiktok.com."}, c.Services[1].Rules)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 1, "services": []}`), 0o644))
+	c, err = LoadCatalog(path)
+	require.NoError(t, err)
+	require.Equal(t, builtin, c)
+
+	// сервис не блокирует общие ресурсы родительского сайта
+	for _, service := range builtin.Services {
+		if service.ID == "amazon_streaming" {
+			require.NotContains(t, service.Rules, "*.media-amazon.com.")
+		}
+	}
+
+	for _, bad := range []string{
+		`{"services": []}`,
+		`{"version": 3, "services": [{"id": "x", "rules": []}]}`,
+		`{"version": 3, "services": [{"id": "x", "rules": ["a.*.com"]}]}`,
+		`{"version": 3, "services": [{"id": "x", "rules": ["x.com"]}, {"id": "x", "rules": ["x.com"]}]}`,
+	} {
+		_, err := ParseCatalog([]byte(bad))
+		require.Error(t, err, bad)
+	}
+}
+
+func TestServices(t *testing.T) {
+	ctx := context.Background()
+	c, err := ParseCatalog([]byte(catalog))
+	require.NoError(t, err)
+
+	bl := blacklist.New(16)
+	storage := fakeStorage{}
+	s := New(bl, storage, c)
+
+	require.Error(t, s.Set(ctx, "fortnite", Global, true))
+
+	// сервис заблокирован только для группы kids
+	require.NoError(t, s.Set(ctx, "tiktok", "kids", true))
+	origins := bl.Origins(ctx, "www.tiktok.com.")
+	require.Equal(t, []string{"service:tiktok"}, origins)
+	require.Equal(t, origins, s.Origins("kids", origins))
+	require.Empty(t, s.Origins("adults", origins))
+
+	// глобальная блокировка и исключение для группы
+	require.NoError(t, s.Set(ctx, "tiktok", Global, true))
+	require.NoError(t, s.Set(ctx, "tiktok", "adults", false))
+	require.True(t, s.Blocked("tiktok", "default"))
+	require.False(t, s.Blocked("tiktok", "adults"))
+
+	list := s.List()
+	require.Len(t, list, 2)
+	require.True(t, list[1].Blocked)
+	require.Equal(t, map[string]bool{"kids": true, "adults": false}, list[1].Groups)
+
+	// настройки восстанавливаются из хранилища
+	restored := New(blacklist.New(16), storage, c)
+	require.NoError(t, restored.Load(ctx))
+	require.Equal(t, s.List(), restored.List())
+
+	// правила уходят из черного списка, когда сервис нигде не заблокирован
+	require.NoError(t, s.Set(ctx, "tiktok", Global, false))
+	require.True(t, bl.Has(ctx, "tiktok.com"))
+	require.NoError(t, s.Reset(ctx, "tiktok", "kids"))
+	require.Error(t, s.Reset(ctx, "tiktok", "kids"))
+	require.False(t, bl.Has(ctx, "tiktok.com"))
+	require.False(t, bl.Has(ctx, "www.tiktok.com"))
+}
@@ -0,0 +1,94 @@
+package ttl
+
+import (
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Bounds ограничивает TTL снизу и сверху. Нулевое значение границы означает,
+// что ограничение не применяется.
+type Bounds struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+func (b Bounds) Clamp(ttl uint32) uint32 {
+	if min := seconds(b.Min); min > 0 && ttl < min {
+		ttl = min
+	}
+	if max := seconds(b.Max); max > 0 && ttl > max {
+		ttl = max
+	}
+	return ttl
+}
+
+// Policy описывает ограничения TTL для разных типов ответов.
+type Policy struct {
+	Positive Bounds
+	Negative Bounds
+	Blocked  Bounds
+}
+
+// RewritePositive приводит TTL всех записей положительного ответа к допустимым
+// границам и возвращает время жизни ответа в кеше (минимальный TTL).
+func (p Policy) RewritePositive(msg *dns.Msg) uint32 {
+	lifetime := uint32(0)
+	first := true
+
+	for _, section := range [][]dns.RR{msg.Answer, msg.Ns} {
+		for _, rr := range section {
+			hdr := rr.Header()
+			hdr.Ttl = p.Positive.Clamp(hdr.Ttl)
+			if first || hdr.Ttl < lifetime {
+				lifetime = hdr.Ttl
+				first = false
+			}
+		}
+	}
+
+	return lifetime
+}
+
+// RewriteNegative вычисляет TTL отрицательного ответа по RFC 2308
+// (минимум из TTL записи SOA и поля MINIMUM), ограничивает его и
+// проставляет в записи раздела authority.
+func (p Policy) RewriteNegative(msg *dns.Msg) uint32 {
+	var lifetime uint32
+
+	for _, rr := range msg.Ns {
+		if soa, ok := rr.(*dns.SOA); ok {
+			lifetime = soa.Hdr.Ttl
+			if soa.Minttl < lifetime {
+				lifetime = soa.Minttl
+			}
+			break
+		}
+	}
+
+	lifetime = p.Negative.Clamp(lifetime)
+
+	for _, rr := range msg.Ns {
+		rr.Header().Ttl = lifetime
+	}
+
+	return lifetime
+}
+
+func (p Policy) Blocking(ttl time.Duration) uint32 {
+	return p.Blocked.Clamp(seconds(ttl))
+}
+
+// IsNegative сообщает, является ли ответ отрицательным
+// (NXDOMAIN или NOERROR без записей в answer).
+func IsNegative(msg *dns.Msg) bool {
+	return msg.Rcode == dns.RcodeNameError ||
+		(msg.Rcode == dns.RcodeSuccess && len(msg.Answer) == 0)
+}
+
+func seconds(d time.Duration) uint32 {
+	if d <= 0 {
+		return 0
+	}
+	return uint32(d / time.Second)
+}
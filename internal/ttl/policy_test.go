@@ -0,0 +1,57 @@
+package ttl
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBounds(t *testing.T) {
+	b := Bounds{Min: 30 * time.Second, Max: time.Hour}
+
+	require.Equal(t, uint32(30), b.Clamp(0))
+	require.Equal(t, uint32(300), b.Clamp(300))
+	require.Equal(t, uint32(3600), b.Clamp(86400))
+	require.Equal(t, uint32(5), Bounds{}.Clamp(5))
+}
+
+func TestPolicy(t *testing.T) {
+	p := Policy{
+		Positive: Bounds{Min: time.Minute, Max: time.Hour},
+		Negative: Bounds{Max: 5 * time.Minute},
+		Blocked:  Bounds{Min: time.Minute},
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		msg := &dns.Msg{}
+		msg.Answer = []dns.RR{
+			&dns.A{Hdr: dns.RR_Header{Name: "a.", Rrtype: dns.TypeA, Ttl: 5}, A: net.ParseIP("10.0.0.1")},
+			&dns.A{Hdr: dns.RR_Header{Name: "a.", Rrtype: dns.TypeA, Ttl: 86400}, A: net.ParseIP("10.0.0.2")},
+		}
+
+		lifetime := p.RewritePositive(msg)
+
+		require.Equal(t, uint32(60), lifetime)
+		require.Equal(t, uint32(60), msg.Answer[0].Header().Ttl)
+		require.Equal(t, uint32(3600), msg.Answer[1].Header().Ttl)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		msg := &dns.Msg{}
+		msg.Rcode = dns.RcodeNameError
+		msg.Ns = []dns.RR{
+			&dns.SOA{Hdr: dns.RR_Header{Name: "a.", Rrtype: dns.TypeSOA, Ttl: 3600}, Minttl: 900},
+		}
+
+		require.True(t, IsNegative(msg))
+		require.Equal(t, uint32(300), p.RewriteNegative(msg))
+		require.Equal(t, uint32(300), msg.Ns[0].Header().Ttl)
+	})
+
+	t.Run("blocked", func(t *testing.T) {
+		require.Equal(t, uint32(60), p.Blocking(10*time.Second))
+	})
+}